@@ -0,0 +1,383 @@
+package evaluations
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AssertionType identifies a deterministic check run against an eval's response or trace
+type AssertionType string
+
+const (
+	AssertionContains       AssertionType = "contains"         // Final response contains Value
+	AssertionNotContains    AssertionType = "not_contains"     // Final response does not contain Value
+	AssertionRegex          AssertionType = "regex"            // Final response matches the regular expression in Value
+	AssertionJSONPathEquals AssertionType = "json_path_equals" // JSON in the final response has Expected at Path
+	AssertionToolCalled     AssertionType = "tool_called"      // Tool was called at least once
+	AssertionToolNotCalled  AssertionType = "tool_not_called"  // Tool was never called
+	AssertionToolCallCount  AssertionType = "tool_call_count"  // Number of calls to Tool (or all tools) is within Min/Max
+	AssertionToolArgsMatch  AssertionType = "tool_args_match"  // At least one call to Tool had arguments containing Args
+)
+
+var assertionTypes = []AssertionType{
+	AssertionContains, AssertionNotContains, AssertionRegex, AssertionJSONPathEquals,
+	AssertionToolCalled, AssertionToolNotCalled, AssertionToolCallCount, AssertionToolArgsMatch,
+}
+
+// Assertion is a deterministic pass/fail check evaluated alongside LLM grading
+type Assertion struct {
+	Type            AssertionType  `yaml:"type" json:"type" jsonschema:"Assertion type: contains, not_contains, regex, json_path_equals, tool_called, tool_not_called, tool_call_count, tool_args_match"`
+	Value           string         `yaml:"value,omitempty" json:"value,omitempty" jsonschema:"Text or regular expression to look for in the final response (contains, not_contains, regex)"`
+	CaseInsensitive bool           `yaml:"case_insensitive,omitempty" json:"case_insensitive,omitempty" jsonschema:"Ignore case when matching value (contains, not_contains, regex)"`
+	Path            string         `yaml:"path,omitempty" json:"path,omitempty" jsonschema:"Path into the JSON in the final response, e.g. '$.user.teams[0]' (json_path_equals)"`
+	Expected        any            `yaml:"expected,omitempty" json:"expected,omitempty" jsonschema:"Expected value at path (json_path_equals)"`
+	Tool            string         `yaml:"tool,omitempty" json:"tool,omitempty" jsonschema:"Name of the MCP tool (tool_called, tool_not_called, tool_args_match; optional for tool_call_count)"`
+	Min             *int           `yaml:"min,omitempty" json:"min,omitempty" jsonschema:"Minimum number of calls (tool_call_count)"`
+	Max             *int           `yaml:"max,omitempty" json:"max,omitempty" jsonschema:"Maximum number of calls (tool_call_count)"`
+	Args            map[string]any `yaml:"args,omitempty" json:"args,omitempty" jsonschema:"Arguments that at least one call must contain; nested objects match partially (tool_args_match)"`
+}
+
+// AssertionResult records the outcome of a single assertion
+type AssertionResult struct {
+	Assertion Assertion `json:"assertion"` // The assertion that was checked
+	Passed    bool      `json:"passed"`    // Whether the check passed
+	Message   string    `json:"message"`   // Human-readable explanation of the outcome
+}
+
+// Validate checks that the assertion has the fields its type requires
+func (a Assertion) Validate() error {
+	switch a.Type {
+	case AssertionContains, AssertionNotContains:
+		if a.Value == "" {
+			return fmt.Errorf("%s assertion requires value", a.Type)
+		}
+	case AssertionRegex:
+		if a.Value == "" {
+			return fmt.Errorf("%s assertion requires value", a.Type)
+		}
+		if _, err := a.compileRegex(); err != nil {
+			return fmt.Errorf("invalid regex '%s': %w", a.Value, err)
+		}
+	case AssertionJSONPathEquals:
+		if a.Path == "" {
+			return fmt.Errorf("%s assertion requires path", a.Type)
+		}
+		if _, err := parseJSONPath(a.Path); err != nil {
+			return err
+		}
+	case AssertionToolCalled, AssertionToolNotCalled:
+		if a.Tool == "" {
+			return fmt.Errorf("%s assertion requires tool", a.Type)
+		}
+	case AssertionToolCallCount:
+		if a.Min == nil && a.Max == nil {
+			return fmt.Errorf("%s assertion requires min and/or max", a.Type)
+		}
+		if a.Min != nil && a.Max != nil && *a.Min > *a.Max {
+			return fmt.Errorf("%s assertion min (%d) is greater than max (%d)", a.Type, *a.Min, *a.Max)
+		}
+	case AssertionToolArgsMatch:
+		if a.Tool == "" {
+			return fmt.Errorf("%s assertion requires tool", a.Type)
+		}
+		if len(a.Args) == 0 {
+			return fmt.Errorf("%s assertion requires args", a.Type)
+		}
+	default:
+		return fmt.Errorf("invalid assertion type '%s': must be one of: %s", a.Type, joinEnum(assertionTypes))
+	}
+	return nil
+}
+
+// String returns a short description of the assertion for reports
+func (a Assertion) String() string {
+	switch a.Type {
+	case AssertionContains, AssertionNotContains, AssertionRegex:
+		return fmt.Sprintf("%s %q", a.Type, a.Value)
+	case AssertionJSONPathEquals:
+		expected, _ := json.Marshal(a.Expected)
+		return fmt.Sprintf("%s %s == %s", a.Type, a.Path, expected)
+	case AssertionToolCallCount:
+		tool := a.Tool
+		if tool == "" {
+			tool = "*"
+		}
+		return fmt.Sprintf("%s %s in [%s, %s]", a.Type, tool, formatBound(a.Min), formatBound(a.Max))
+	case AssertionToolArgsMatch:
+		args, _ := json.Marshal(a.Args)
+		return fmt.Sprintf("%s %s %s", a.Type, a.Tool, args)
+	default:
+		return fmt.Sprintf("%s %s", a.Type, a.Tool)
+	}
+}
+
+// Check evaluates the assertion against the final response and execution trace
+func (a Assertion) Check(response string, trace *EvalTrace) AssertionResult {
+	passed, message := a.check(response, trace)
+	return AssertionResult{
+		Assertion: a,
+		Passed:    passed,
+		Message:   message,
+	}
+}
+
+func (a Assertion) check(response string, trace *EvalTrace) (bool, string) {
+	switch a.Type {
+	case AssertionContains:
+		if a.containsValue(response) {
+			return true, fmt.Sprintf("response contains %q", a.Value)
+		}
+		return false, fmt.Sprintf("response does not contain %q", a.Value)
+
+	case AssertionNotContains:
+		if a.containsValue(response) {
+			return false, fmt.Sprintf("response contains %q", a.Value)
+		}
+		return true, fmt.Sprintf("response does not contain %q", a.Value)
+
+	case AssertionRegex:
+		re, err := a.compileRegex()
+		if err != nil {
+			return false, fmt.Sprintf("invalid regex: %v", err)
+		}
+		if re.MatchString(response) {
+			return true, fmt.Sprintf("response matches /%s/", a.Value)
+		}
+		return false, fmt.Sprintf("response does not match /%s/", a.Value)
+
+	case AssertionJSONPathEquals:
+		return a.checkJSONPath(response)
+
+	case AssertionToolCalled:
+		if count := len(toolCallsNamed(trace, a.Tool)); count > 0 {
+			return true, fmt.Sprintf("%s was called %d time(s)", a.Tool, count)
+		}
+		return false, fmt.Sprintf("%s was not called", a.Tool)
+
+	case AssertionToolNotCalled:
+		if count := len(toolCallsNamed(trace, a.Tool)); count > 0 {
+			return false, fmt.Sprintf("%s was called %d time(s)", a.Tool, count)
+		}
+		return true, fmt.Sprintf("%s was not called", a.Tool)
+
+	case AssertionToolCallCount:
+		count := len(toolCallsNamed(trace, a.Tool))
+		inRange := (a.Min == nil || count >= *a.Min) && (a.Max == nil || count <= *a.Max)
+		message := fmt.Sprintf("%d call(s), expected [%s, %s]", count, formatBound(a.Min), formatBound(a.Max))
+		return inRange, message
+
+	case AssertionToolArgsMatch:
+		calls := toolCallsNamed(trace, a.Tool)
+		if len(calls) == 0 {
+			return false, fmt.Sprintf("%s was not called", a.Tool)
+		}
+		expected := normalizeJSON(a.Args)
+		for _, call := range calls {
+			var input any
+			if err := json.Unmarshal(call.Input, &input); err != nil {
+				continue
+			}
+			if jsonContains(input, expected) {
+				return true, fmt.Sprintf("%s called with matching arguments %s", a.Tool, string(call.Input))
+			}
+		}
+		return false, fmt.Sprintf("none of %d call(s) to %s had matching arguments", len(calls), a.Tool)
+	}
+
+	return false, fmt.Sprintf("unknown assertion type '%s'", a.Type)
+}
+
+func (a Assertion) containsValue(response string) bool {
+	if a.CaseInsensitive {
+		return strings.Contains(strings.ToLower(response), strings.ToLower(a.Value))
+	}
+	return strings.Contains(response, a.Value)
+}
+
+func (a Assertion) compileRegex() (*regexp.Regexp, error) {
+	pattern := a.Value
+	if a.CaseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+func (a Assertion) checkJSONPath(response string) (bool, string) {
+	cleaned, err := extractJSONFromResponse(response)
+	if err != nil {
+		return false, fmt.Sprintf("response does not contain JSON: %v", err)
+	}
+
+	var document any
+	if err := json.Unmarshal([]byte(cleaned), &document); err != nil {
+		return false, fmt.Sprintf("response does not contain valid JSON: %v", err)
+	}
+
+	segments, err := parseJSONPath(a.Path)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	actual, ok := lookupJSONPath(document, segments)
+	if !ok {
+		return false, fmt.Sprintf("path %s not found in response", a.Path)
+	}
+
+	actualJSON, _ := json.Marshal(actual)
+	if reflect.DeepEqual(actual, normalizeJSON(a.Expected)) {
+		return true, fmt.Sprintf("%s is %s", a.Path, actualJSON)
+	}
+	expectedJSON, _ := json.Marshal(a.Expected)
+	return false, fmt.Sprintf("%s is %s, expected %s", a.Path, actualJSON, expectedJSON)
+}
+
+// CheckAssertions evaluates every assertion and returns the results in order
+func CheckAssertions(assertions []Assertion, response string, trace *EvalTrace) []AssertionResult {
+	if len(assertions) == 0 {
+		return nil
+	}
+
+	results := make([]AssertionResult, 0, len(assertions))
+	for _, assertion := range assertions {
+		results = append(results, assertion.Check(response, trace))
+	}
+	return results
+}
+
+// AssertionFailures returns an error describing the failed assertions, or nil if all passed
+func AssertionFailures(results []AssertionResult) error {
+	var failures []string
+	for _, result := range results {
+		if !result.Passed {
+			failures = append(failures, fmt.Sprintf("%s (%s)", result.Assertion, result.Message))
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d assertion(s) failed: %s", len(failures), len(results), strings.Join(failures, "; "))
+}
+
+// toolCallsNamed returns the calls to the named tool, or every call when name is empty
+func toolCallsNamed(trace *EvalTrace, name string) []ToolCall {
+	if trace == nil {
+		return nil
+	}
+
+	var calls []ToolCall
+	for _, step := range trace.Steps {
+		for _, call := range step.ToolCalls {
+			if name == "" || call.ToolName == name {
+				calls = append(calls, call)
+			}
+		}
+	}
+	return calls
+}
+
+// parseJSONPath splits a simple JSONPath such as $.user.teams[0] or user.teams.0
+// into object keys and array indexes
+func parseJSONPath(path string) ([]string, error) {
+	trimmed := strings.TrimPrefix(path, "$")
+
+	var segments []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(trimmed); i++ {
+		switch c := trimmed[i]; c {
+		case '.':
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(trimmed[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path '%s': unclosed '['", path)
+			}
+			segments = append(segments, strings.Trim(trimmed[i+1:i+end], `'"`))
+			i += end
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return segments, nil
+}
+
+// lookupJSONPath walks a decoded JSON document following the path segments
+func lookupJSONPath(document any, segments []string) (any, bool) {
+	current := document
+	for _, segment := range segments {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// normalizeJSON round-trips a value through JSON so values decoded from YAML
+// compare equal to values decoded from JSON (e.g. int vs float64)
+func normalizeJSON(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// jsonContains reports whether actual contains expected. Objects match when
+// every expected key matches recursively, any other values must be equal.
+func jsonContains(actual, expected any) bool {
+	expectedMap, ok := expected.(map[string]any)
+	if !ok {
+		return reflect.DeepEqual(actual, expected)
+	}
+
+	actualMap, ok := actual.(map[string]any)
+	if !ok {
+		return false
+	}
+
+	for key, expectedValue := range expectedMap {
+		actualValue, ok := actualMap[key]
+		if !ok || !jsonContains(actualValue, expectedValue) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatBound(bound *int) string {
+	if bound == nil {
+		return "-"
+	}
+	return strconv.Itoa(*bound)
+}
@@ -0,0 +1,225 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertion_Check(t *testing.T) {
+	trace := &EvalTrace{
+		Steps: []AgenticStep{
+			{
+				ToolCalls: []ToolCall{
+					{ToolName: "add", Input: json.RawMessage(`{"a":5,"b":3}`)},
+					{ToolName: "get_user_info", Input: json.RawMessage(`{"user_id":"123","options":{"include":"teams","limit":10}}`)},
+				},
+			},
+			{
+				ToolCalls: []ToolCall{
+					{ToolName: "add", Input: json.RawMessage(`{"a":1,"b":2}`)},
+				},
+			},
+		},
+	}
+
+	response := "The user is:\n```json\n{\"user\": {\"name\": \"Alice\", \"teams\": [\"core\", \"infra\"], \"age\": 30}}\n```"
+
+	tests := []struct {
+		name      string
+		assertion Assertion
+		passed    bool
+	}{
+		{"contains", Assertion{Type: AssertionContains, Value: "Alice"}, true},
+		{"contains missing", Assertion{Type: AssertionContains, Value: "Bob"}, false},
+		{"contains case sensitive", Assertion{Type: AssertionContains, Value: "alice"}, false},
+		{"contains case insensitive", Assertion{Type: AssertionContains, Value: "alice", CaseInsensitive: true}, true},
+		{"not_contains", Assertion{Type: AssertionNotContains, Value: "error"}, true},
+		{"not_contains present", Assertion{Type: AssertionNotContains, Value: "Alice"}, false},
+		{"regex", Assertion{Type: AssertionRegex, Value: `"age": \d+`}, true},
+		{"regex no match", Assertion{Type: AssertionRegex, Value: `^Alice`}, false},
+		{"regex case insensitive", Assertion{Type: AssertionRegex, Value: `THE USER`, CaseInsensitive: true}, true},
+		{"json_path_equals string", Assertion{Type: AssertionJSONPathEquals, Path: "$.user.name", Expected: "Alice"}, true},
+		{"json_path_equals index", Assertion{Type: AssertionJSONPathEquals, Path: "$.user.teams[1]", Expected: "infra"}, true},
+		{"json_path_equals number", Assertion{Type: AssertionJSONPathEquals, Path: "user.age", Expected: 30}, true},
+		{"json_path_equals array", Assertion{Type: AssertionJSONPathEquals, Path: "$.user.teams", Expected: []any{"core", "infra"}}, true},
+		{"json_path_equals mismatch", Assertion{Type: AssertionJSONPathEquals, Path: "$.user.name", Expected: "Bob"}, false},
+		{"json_path_equals missing path", Assertion{Type: AssertionJSONPathEquals, Path: "$.user.email", Expected: "a@b.c"}, false},
+		{"json_path_equals index out of range", Assertion{Type: AssertionJSONPathEquals, Path: "$.user.teams[5]", Expected: "core"}, false},
+		{"tool_called", Assertion{Type: AssertionToolCalled, Tool: "add"}, true},
+		{"tool_called missing", Assertion{Type: AssertionToolCalled, Tool: "echo"}, false},
+		{"tool_not_called", Assertion{Type: AssertionToolNotCalled, Tool: "echo"}, true},
+		{"tool_not_called present", Assertion{Type: AssertionToolNotCalled, Tool: "add"}, false},
+		{"tool_call_count exact", Assertion{Type: AssertionToolCallCount, Tool: "add", Min: toPtr(2), Max: toPtr(2)}, true},
+		{"tool_call_count too many", Assertion{Type: AssertionToolCallCount, Tool: "add", Max: toPtr(1)}, false},
+		{"tool_call_count all tools", Assertion{Type: AssertionToolCallCount, Min: toPtr(3)}, true},
+		{"tool_args_match", Assertion{Type: AssertionToolArgsMatch, Tool: "add", Args: map[string]any{"a": 1}}, true},
+		{"tool_args_match nested partial", Assertion{Type: AssertionToolArgsMatch, Tool: "get_user_info", Args: map[string]any{"options": map[string]any{"include": "teams"}}}, true},
+		{"tool_args_match no match", Assertion{Type: AssertionToolArgsMatch, Tool: "add", Args: map[string]any{"a": 9}}, false},
+		{"tool_args_match not called", Assertion{Type: AssertionToolArgsMatch, Tool: "echo", Args: map[string]any{"message": "hi"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			result := tt.assertion.Check(response, trace)
+			assert.Equal(tt.passed, result.Passed, result.Message)
+			assert.NotEmpty(result.Message)
+		})
+	}
+}
+
+func TestAssertion_CheckJSONPath_NoJSON(t *testing.T) {
+	assert := require.New(t)
+
+	result := Assertion{Type: AssertionJSONPathEquals, Path: "$.a", Expected: 1}.Check("no json here", nil)
+	assert.False(result.Passed)
+	assert.Contains(result.Message, "valid JSON")
+}
+
+func TestAssertion_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		assertion Assertion
+		wantErr   string
+	}{
+		{"valid contains", Assertion{Type: AssertionContains, Value: "x"}, ""},
+		{"contains missing value", Assertion{Type: AssertionContains}, "requires value"},
+		{"invalid regex", Assertion{Type: AssertionRegex, Value: "("}, "invalid regex"},
+		{"json_path_equals missing path", Assertion{Type: AssertionJSONPathEquals, Expected: 1}, "requires path"},
+		{"json_path_equals unclosed bracket", Assertion{Type: AssertionJSONPathEquals, Path: "$.a[0"}, "unclosed"},
+		{"tool_called missing tool", Assertion{Type: AssertionToolCalled}, "requires tool"},
+		{"tool_call_count missing bounds", Assertion{Type: AssertionToolCallCount, Tool: "add"}, "requires min and/or max"},
+		{"tool_call_count min greater than max", Assertion{Type: AssertionToolCallCount, Min: toPtr(3), Max: toPtr(1)}, "greater than max"},
+		{"tool_args_match missing args", Assertion{Type: AssertionToolArgsMatch, Tool: "add"}, "requires args"},
+		{"unknown type", Assertion{Type: "bogus"}, "invalid assertion type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			err := tt.assertion.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(err)
+				return
+			}
+			assert.Error(err)
+			assert.Contains(err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"$.user.name", []string{"user", "name"}},
+		{"user.teams[0]", []string{"user", "teams", "0"}},
+		{"$.items[2].tags['primary']", []string{"items", "2", "tags", "primary"}},
+		{"$", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert := require.New(t)
+
+			got, err := parseJSONPath(tt.path)
+			assert.NoError(err)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestAssertionFailures(t *testing.T) {
+	assert := require.New(t)
+
+	results := CheckAssertions([]Assertion{
+		{Type: AssertionContains, Value: "8"},
+		{Type: AssertionToolCalled, Tool: "add"},
+	}, "5 plus 3 is 8", nil)
+	assert.Len(results, 2)
+	assert.True(results[0].Passed)
+	assert.False(results[1].Passed)
+
+	err := AssertionFailures(results)
+	assert.Error(err)
+	assert.Contains(err.Error(), "1 of 2 assertion(s) failed")
+	assert.Contains(err.Error(), "tool_called add")
+
+	assert.NoError(AssertionFailures(results[:1]))
+	assert.Nil(CheckAssertions(nil, "", nil))
+}
+
+func TestLoadConfig_InvalidAssertion(t *testing.T) {
+	assert := require.New(t)
+
+	configContent := `
+model: claude-3-5-sonnet-20241022
+mcp_server:
+  command: echo
+evals:
+  - name: test
+    prompt: "test"
+    assertions:
+      - type: contains
+        value: "ok"
+      - type: tool_call_count
+        tool: add
+`
+
+	tmpFile, err := os.CreateTemp("", "config-assertions-*.yaml")
+	assert.NoError(err)
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString(configContent)
+	assert.NoError(err)
+	tmpFile.Close()
+
+	_, err = LoadConfig(tmpFile.Name())
+	assert.Error(err)
+	assert.Contains(err.Error(), "invalid assertion[1]")
+	assert.Contains(err.Error(), "requires min and/or max")
+}
+
+func TestEvalClient_RunEval_AssertionFailureFailsEval(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("5 plus 3 is 8"),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:   "add",
+		Prompt: "What is 5 plus 3?",
+		Assertions: []Assertion{
+			{Type: AssertionContains, Value: "8"},
+			{Type: AssertionToolCalled, Tool: "add"},
+		},
+	})
+	assert.NoError(err)
+
+	// The grade passed but the model answered without calling the tool
+	assert.NotNil(result.Grade)
+	assert.Equal(5, result.Grade.Accuracy)
+	assert.Len(result.Assertions, 2)
+	assert.True(result.Assertions[0].Passed)
+	assert.False(result.Assertions[1].Passed)
+	assert.Error(result.Error)
+	assert.Contains(result.Error.Error(), "tool_called add")
+}
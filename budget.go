@@ -0,0 +1,149 @@
+package evaluations
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is wrapped by the error of an eval stopped by its own or the suite's budget
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Budget caps the tokens, cost and wall time spent by an eval or a whole run. Limits
+// are checked between agentic steps, so a single step can take usage past them.
+type Budget struct {
+	MaxTokens   int     `yaml:"max_tokens,omitempty" json:"max_tokens,omitempty" jsonschema:"Maximum input, output and cache tokens across agent, sampling, simulated user and grading requests"`
+	MaxCostUSD  float64 `yaml:"max_cost_usd,omitempty" json:"max_cost_usd,omitempty" jsonschema:"Maximum cost in USD, see pricing"`
+	MaxDuration string  `yaml:"max_duration,omitempty" json:"max_duration,omitempty" jsonschema:"Maximum wall time (e.g. '10m'); unlike timeout the current step is allowed to finish"`
+}
+
+// Validate checks that no limit is negative and the duration parses
+func (b *Budget) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must not be negative, got %d", b.MaxTokens)
+	}
+	if b.MaxCostUSD < 0 {
+		return fmt.Errorf("max_cost_usd must not be negative, got %g", b.MaxCostUSD)
+	}
+	return validateDuration("max_duration", b.MaxDuration)
+}
+
+// BudgetUsage is what an eval or run has spent against its budget
+type BudgetUsage struct {
+	Tokens   int
+	CostUSD  float64
+	Duration time.Duration
+}
+
+// check returns an error wrapping ErrBudgetExceeded when usage has reached a limit.
+// scope names the budget in the message, e.g. "eval" or "suite".
+func (b *Budget) check(scope string, usage BudgetUsage) error {
+	if b == nil {
+		return nil
+	}
+	if b.MaxTokens > 0 && usage.Tokens >= b.MaxTokens {
+		return fmt.Errorf("%s %w: max_tokens %d reached (%d used)", scope, ErrBudgetExceeded, b.MaxTokens, usage.Tokens)
+	}
+	if b.MaxCostUSD > 0 && usage.CostUSD >= b.MaxCostUSD {
+		return fmt.Errorf("%s %w: max_cost_usd $%.2f reached ($%.4f used)", scope, ErrBudgetExceeded, b.MaxCostUSD, usage.CostUSD)
+	}
+	if b.MaxDuration != "" {
+		// Validated before the run starts
+		maxDuration, _ := time.ParseDuration(b.MaxDuration)
+		if maxDuration > 0 && usage.Duration >= maxDuration {
+			return fmt.Errorf("%s %w: max_duration %s reached (%s elapsed)", scope, ErrBudgetExceeded, maxDuration, usage.Duration.Round(time.Millisecond))
+		}
+	}
+	return nil
+}
+
+// suiteSpend accumulates the usage of every eval run by a client, it is safe for
+// concurrent use by the evals of a parallel run
+type suiteSpend struct {
+	mu      sync.Mutex
+	started time.Time // when the first eval started
+	tokens  int
+	cost    float64
+}
+
+// begin starts the suite's clock if no eval has run yet
+func (s *suiteSpend) begin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started.IsZero() {
+		s.started = time.Now()
+	}
+}
+
+func (s *suiteSpend) add(tokens int, cost float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens += tokens
+	s.cost += cost
+}
+
+func (s *suiteSpend) usage() BudgetUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return BudgetUsage{Tokens: s.tokens, CostUSD: s.cost, Duration: time.Since(s.started)}
+}
+
+// evalMeter tracks a single eval run against its own budget and the suite's, passing
+// the run's usage on to the suite as it grows
+type evalMeter struct {
+	ec     *EvalClient
+	budget *Budget
+	start  time.Time
+	usage  BudgetUsage // reported to the suite so far
+}
+
+func (ec *EvalClient) newEvalMeter(eval Eval, start time.Time) *evalMeter {
+	ec.spend.begin()
+	return &evalMeter{ec: ec, budget: eval.Budget, start: start}
+}
+
+// update records the usage in trace, with the server requests made so far, and adds
+// the increase since the last update to the suite
+func (m *evalMeter) update(trace *EvalTrace, serverRequests []ServerRequest) {
+	partial := *trace
+	partial.ServerRequests = serverRequests
+
+	tokens := traceTokens(&partial)
+	cost := m.ec.traceCost(&partial).Total
+	m.ec.spend.add(tokens-m.usage.Tokens, cost-m.usage.CostUSD)
+	m.usage.Tokens, m.usage.CostUSD = tokens, cost
+}
+
+// check returns an error wrapping ErrBudgetExceeded when the eval or suite budget has run out
+func (m *evalMeter) check() error {
+	if err := m.ec.config.Budget.check("suite", m.ec.spend.usage()); err != nil {
+		return err
+	}
+	usage := m.usage
+	usage.Duration = time.Since(m.start)
+	return m.budget.check("eval", usage)
+}
+
+// traceTokens counts every token processed for a trace, including cached input
+func traceTokens(trace *EvalTrace) int {
+	total := 0
+	for _, step := range trace.Steps {
+		total += step.InputTokens + step.OutputTokens + step.CacheCreationInputTokens + step.CacheReadInputTokens
+	}
+	for _, request := range trace.ServerRequests {
+		total += request.InputTokens + request.OutputTokens
+	}
+	for _, turn := range trace.Turns {
+		if call := turn.Simulation; call != nil {
+			total += call.InputTokens + call.OutputTokens
+		}
+	}
+	if grading := trace.Grading; grading != nil {
+		total += grading.InputTokens + grading.OutputTokens + grading.CacheCreationInputTokens + grading.CacheReadInputTokens
+	}
+	return total
+}
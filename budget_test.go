@@ -0,0 +1,182 @@
+package evaluations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  *Budget
+		wantErr string
+	}{
+		{name: "nil", budget: nil},
+		{name: "all limits", budget: &Budget{MaxTokens: 100000, MaxCostUSD: 2.5, MaxDuration: "10m"}},
+		{name: "negative tokens", budget: &Budget{MaxTokens: -1}, wantErr: "max_tokens must not be negative, got -1"},
+		{name: "negative cost", budget: &Budget{MaxCostUSD: -0.5}, wantErr: "max_cost_usd must not be negative, got -0.5"},
+		{name: "bad duration", budget: &Budget{MaxDuration: "ten minutes"}, wantErr: "invalid max_duration 'ten minutes'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			err := tt.budget.Validate()
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestBudget_Check(t *testing.T) {
+	budget := &Budget{MaxTokens: 1000, MaxCostUSD: 1, MaxDuration: "1m"}
+
+	tests := []struct {
+		name    string
+		budget  *Budget
+		usage   BudgetUsage
+		wantErr string
+	}{
+		{name: "nil budget", budget: nil, usage: BudgetUsage{Tokens: 1 << 30}},
+		{name: "within limits", budget: budget, usage: BudgetUsage{Tokens: 999, CostUSD: 0.99, Duration: 59 * time.Second}},
+		{name: "tokens", budget: budget, usage: BudgetUsage{Tokens: 1200}, wantErr: "eval budget exceeded: max_tokens 1000 reached (1200 used)"},
+		{name: "cost", budget: budget, usage: BudgetUsage{CostUSD: 1.25}, wantErr: "eval budget exceeded: max_cost_usd $1.00 reached ($1.2500 used)"},
+		{name: "duration", budget: budget, usage: BudgetUsage{Duration: 90 * time.Second}, wantErr: "eval budget exceeded: max_duration 1m0s reached (1m30s elapsed)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			err := tt.budget.check("eval", tt.usage)
+			if tt.wantErr != "" {
+				assert.EqualError(err, tt.wantErr)
+				assert.ErrorIs(err, ErrBudgetExceeded)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestEvalClient_RunEval_EvalBudget(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamToolUseResponse("toolu_1", "echo", `{"message":"hello"}`),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:   "runaway",
+		Prompt: "Echo hello",
+		Budget: &Budget{MaxTokens: 50},
+	})
+	assert.NoError(err)
+
+	// The first step uses 60 tokens, so the eval stops before the second and is not graded
+	assert.Equal(EvalStatusBudgetExceeded, result.Status)
+	assert.Equal(EvalStatusBudgetExceeded, result.Trace.Status)
+	assert.True(errors.Is(result.Error, ErrBudgetExceeded))
+	assert.EqualError(result.Error, "eval budget exceeded: max_tokens 50 reached (60 used)")
+	assert.Len(result.Trace.Steps, 1)
+	assert.Len(result.Trace.Steps[0].ToolCalls, 1)
+	assert.Nil(result.Grade)
+	assert.Nil(result.Trace.Grading)
+	assert.Len(api.Requests(), 1)
+}
+
+func TestEvalClient_RunEvals_SuiteBudget(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("first"), gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		// One dollar per token, so the first eval and its grading cost $120
+		Pricing: Pricing{"test-model": {Input: 1_000_000, Output: 1_000_000}},
+		Budget:  &Budget{MaxCostUSD: 100},
+	})
+
+	results, err := client.RunEvals(context.Background(), []Eval{
+		{Name: "first", Prompt: "First"},
+		{Name: "second", Prompt: "Second"},
+		{Name: "third", Prompt: "Third", Trials: 3},
+	})
+	assert.NoError(err)
+
+	assert.NoError(results[0].Error)
+	assert.NotNil(results[0].Grade)
+
+	assert.Equal(EvalStatusBudgetExceeded, results[1].Status)
+	assert.EqualError(results[1].Error, "eval skipped: suite budget exceeded: max_cost_usd $100.00 reached ($120.0000 used)")
+	assert.Empty(results[1].Trace.Steps)
+
+	// Every trial is recorded as skipped
+	assert.Len(results[2].Trials, 3)
+	for _, trial := range results[2].Trials {
+		assert.Equal(EvalStatusBudgetExceeded, trial.Status)
+	}
+	assert.Equal(3, results[2].Aggregate.Trials)
+	assert.False(results[2].Aggregate.Passed)
+
+	assert.Len(api.Requests(), 2)
+}
+
+func TestEvalClient_RunEvalTrials_SuiteBudgetBetweenTrials(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("first"), gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		// The first trial and its grading use 120 tokens
+		Budget: &Budget{MaxTokens: 100},
+	})
+
+	result, err := client.RunEvalTrials(context.Background(), Eval{Name: "trials", Prompt: "Hello", Trials: 3})
+	assert.NoError(err)
+
+	assert.Len(result.Trials, 3)
+	assert.NoError(result.Trials[0].Error)
+	for _, trial := range result.Trials[1:] {
+		assert.Equal(EvalStatusBudgetExceeded, trial.Status)
+		assert.ErrorIs(trial.Error, ErrBudgetExceeded)
+	}
+
+	assert.Equal(3, result.Aggregate.Trials)
+	assert.Equal(1, result.Aggregate.Passes)
+	assert.False(result.Aggregate.Passed)
+	assert.EqualError(result.Error, "1 of 3 trials passed, all trials must pass")
+	assert.Len(api.Requests(), 2)
+}
@@ -0,0 +1,176 @@
+package evaluations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// CassetteVersion is incremented when the cassette file format changes incompatibly
+const CassetteVersion = 1
+
+// CassetteMode selects how an EvalClient uses its cassette
+type CassetteMode string
+
+const (
+	CassetteRecord CassetteMode = "record" // Send requests to the provider and store each response
+	CassetteReplay CassetteMode = "replay" // Answer requests from the cassette without contacting the provider (default)
+)
+
+// Cassette stores model requests and responses so eval runs can be replayed offline.
+// Interactions are keyed by a hash of the provider and request, so a replay only
+// matches when the eval sends exactly the same requests, including tool results
+// from the MCP server.
+type Cassette struct {
+	Version      int                   `json:"version"`
+	Interactions []CassetteInteraction `json:"interactions"`
+
+	mu     sync.Mutex
+	served map[string]int // responses replayed so far for each key
+}
+
+// CassetteInteraction is one recorded model request and its response
+type CassetteInteraction struct {
+	Key      string           `json:"key"`      // SHA-256 of the provider name and request
+	Provider ProviderName     `json:"provider"` // Provider that answered the request
+	Request  ProviderRequest  `json:"request"`  // Request sent, kept for debugging replay misses
+	Response ProviderResponse `json:"response"` // Response returned by the provider
+}
+
+// NewCassette returns an empty cassette ready for recording
+func NewCassette() *Cassette {
+	return &Cassette{Version: CassetteVersion}
+}
+
+// LoadCassette reads a cassette written by Save
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if cassette.Version != CassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s: expected %d, record it again", cassette.Version, path, CassetteVersion)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to path as JSON
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of recorded interactions
+func (c *Cassette) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.Interactions)
+}
+
+func (c *Cassette) record(provider ProviderName, key string, req ProviderRequest, resp *ProviderResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, CassetteInteraction{
+		Key:      key,
+		Provider: provider,
+		Request:  req,
+		Response: *resp,
+	})
+}
+
+// replay returns the recorded response for key. Repeated requests, such as the first
+// request of each trial, are answered in the order they were recorded and the last
+// response is reused once they run out.
+func (c *Cassette) replay(key string) (*ProviderResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var matches []int
+	for i, interaction := range c.Interactions {
+		if interaction.Key == key {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, false
+	}
+
+	if c.served == nil {
+		c.served = make(map[string]int)
+	}
+	n := min(c.served[key], len(matches)-1)
+	c.served[key]++
+
+	resp := c.Interactions[matches[n]].Response
+	return &resp, true
+}
+
+// cassetteKey hashes a request along with the provider it is sent to
+func cassetteKey(provider ProviderName, req ProviderRequest) (string, error) {
+	data, err := json.Marshal(struct {
+		Provider ProviderName
+		Request  ProviderRequest
+	}{provider, req})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// cassetteProvider records the responses of the provider it wraps, or replays them
+// without calling it
+type cassetteProvider struct {
+	name     ProviderName
+	inner    Provider
+	cassette *Cassette
+	mode     CassetteMode
+}
+
+func newCassetteProvider(inner Provider, cassette *Cassette, mode CassetteMode) *cassetteProvider {
+	return &cassetteProvider{name: inner.Name(), inner: inner, cassette: cassette, mode: mode}
+}
+
+func (p *cassetteProvider) Name() ProviderName {
+	return p.name
+}
+
+func (p *cassetteProvider) CreateMessage(ctx context.Context, req ProviderRequest) (*ProviderResponse, error) {
+	key, err := cassetteKey(p.name, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.mode == CassetteReplay {
+		resp, ok := p.cassette.replay(key)
+		if !ok {
+			return nil, fmt.Errorf("no recorded response for %s request to model %s (key %s), record the cassette again", p.name, req.Model, key)
+		}
+		return resp, nil
+	}
+
+	resp, err := p.inner.CreateMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	p.cassette.record(p.name, key, req, resp)
+	return resp, nil
+}
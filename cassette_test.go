@@ -0,0 +1,137 @@
+package evaluations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalClient_CassetteRecordReplay(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	eval := Eval{Name: "echo", Prompt: "Echo hello"}
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	// Record against the API
+	api := newFakeModelAPI(t,
+		streamToolUseResponse("toolu_1", "echo", `{"message":"hello"}`),
+		streamTextResponse("Echoed hello."),
+		gradeResponse(4),
+	)
+	recording := NewCassette()
+	recorder := NewEvalClient(EvalClientConfig{
+		APIKey:       "test-key",
+		BaseURL:      api.URL,
+		Transport:    TransportStreamableHTTP,
+		URL:          mcpServer.URL,
+		Model:        "test-model",
+		Cassette:     recording,
+		CassetteMode: CassetteRecord,
+	})
+	recorded, err := recorder.RunEval(context.Background(), eval)
+	assert.NoError(err)
+	assert.NoError(recorded.Error)
+	assert.Equal(3, recording.Len())
+	assert.NoError(recording.Save(path))
+
+	// Replay without an API key or a reachable API
+	cassette, err := LoadCassette(path)
+	assert.NoError(err)
+	offline := newFakeModelAPI(t)
+	replayer := NewEvalClient(EvalClientConfig{
+		BaseURL:   offline.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		Cassette:  cassette,
+	})
+	replayed, err := replayer.RunEval(context.Background(), eval)
+	assert.NoError(err)
+	assert.NoError(replayed.Error)
+	assert.Empty(offline.Requests())
+
+	assert.Equal(recorded.Result.RawResponse, replayed.Result.RawResponse)
+	assert.Equal(recorded.Grade, replayed.Grade)
+	assert.Len(replayed.Trace.Steps, 2)
+	assert.Equal("echo", replayed.Trace.Steps[0].ToolCalls[0].ToolName)
+	assert.Equal(recorded.Trace.Steps[0].ToolCalls[0].Output, replayed.Trace.Steps[0].ToolCalls[0].Output)
+
+	// A changed prompt sends a request that was never recorded
+	missed, err := replayer.RunEval(context.Background(), Eval{Name: "echo", Prompt: "Echo goodbye"})
+	assert.Nil(missed)
+	assert.ErrorContains(err, "no recorded response for anthropic request to model test-model")
+	assert.Empty(offline.Requests())
+}
+
+func TestCassette_ReplayRepeatedRequests(t *testing.T) {
+	assert := require.New(t)
+
+	req := ProviderRequest{Model: "test-model", Messages: []Message{{Role: RoleUser, Content: []ContentBlock{TextBlock("hi")}}}}
+	key, err := cassetteKey(ProviderAnthropic, req)
+	assert.NoError(err)
+
+	cassette := NewCassette()
+	cassette.record(ProviderAnthropic, key, req, &ProviderResponse{Content: []ContentBlock{TextBlock("first")}})
+	cassette.record(ProviderAnthropic, key, req, &ProviderResponse{Content: []ContentBlock{TextBlock("second")}})
+
+	// Repeated requests are answered in recording order, then the last answer is reused
+	for _, want := range []string{"first", "second", "second"} {
+		resp, ok := cassette.replay(key)
+		assert.True(ok)
+		assert.Equal(want, resp.Text())
+	}
+
+	// The key depends on the provider as well as the request
+	openAIKey, err := cassetteKey(ProviderOpenAI, req)
+	assert.NoError(err)
+	assert.NotEqual(key, openAIKey)
+	_, ok := cassette.replay(openAIKey)
+	assert.False(ok)
+}
+
+func TestLoadCassette_Errors(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	_, err := LoadCassette(filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(err, "failed to read cassette")
+
+	future := filepath.Join(dir, "future.json")
+	assert.NoError(os.WriteFile(future, []byte(`{"version":99,"interactions":[]}`), 0o600))
+	_, err = LoadCassette(future)
+	assert.ErrorContains(err, "unsupported cassette version 99")
+}
+
+func TestEvalClient_UseCassette_DefaultsToReplay(t *testing.T) {
+	assert := require.New(t)
+
+	// The mode is left empty even without ApplyDefaults
+	offline := newFakeModelAPI(t)
+	ec := &EvalClient{
+		config:   EvalClientConfig{Cassette: NewCassette()},
+		provider: newAnthropicProvider(EvalClientConfig{APIKey: "test-key", BaseURL: offline.URL}),
+	}
+	ec.useCassette()
+	assert.NoError(ec.providerErr)
+	assert.Equal(CassetteReplay, ec.config.CassetteMode)
+
+	_, err := ec.provider.CreateMessage(context.Background(), ProviderRequest{Model: "test-model"})
+	assert.ErrorContains(err, "no recorded response")
+	assert.Empty(offline.Requests())
+}
+
+func TestNewEvalClient_InvalidCassetteMode(t *testing.T) {
+	assert := require.New(t)
+
+	client := NewEvalClient(EvalClientConfig{
+		Model:        "test-model",
+		Cassette:     NewCassette(),
+		CassetteMode: "rewind",
+	})
+	_, err := client.RunEval(context.Background(), Eval{Name: "test", Prompt: "test"})
+	assert.EqualError(err, "invalid cassette mode 'rewind': must be one of: replay, record")
+}
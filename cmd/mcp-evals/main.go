@@ -16,10 +16,13 @@ type CLI struct {
 
 	Version kong.VersionFlag `help:"Show version information"`
 
-	Run      commands.RunCmd      `cmd:"" help:"Run evaluations against an MCP server (default)" default:"1"`
-	Report   commands.ReportCmd   `cmd:"" help:"Generate report from trace files"`
-	Validate commands.ValidateCmd `cmd:"" help:"Validate configuration file against JSON schema"`
-	Schema   commands.SchemaCmd   `cmd:"" help:"Generate JSON schema for evaluation configuration"`
+	Run        commands.RunCmd        `cmd:"" help:"Run evaluations against an MCP server (default)" default:"1"`
+	Report     commands.ReportCmd     `cmd:"" help:"Generate report from trace files"`
+	List       commands.ListCmd       `cmd:"" help:"List the evals a run would select with their tags and settings"`
+	Compare    commands.CompareCmd    `cmd:"" help:"Compare trace files from two runs and detect regressions"`
+	MockServer commands.MockServerCmd `cmd:"" name:"mock-server" help:"Serve the tool calls recorded in trace files as a mock MCP server"`
+	Validate   commands.ValidateCmd   `cmd:"" help:"Validate configuration file against JSON schema"`
+	Schema     commands.SchemaCmd     `cmd:"" help:"Generate JSON schema for evaluation configuration"`
 }
 
 func main() {
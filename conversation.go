@@ -0,0 +1,173 @@
+package evaluations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SimulatedUserDone is the reply the simulated user sends to end the conversation
+const SimulatedUserDone = "[DONE]"
+
+// SimulatedUserPrompt is the system prompt for the model playing the user in multi-turn evals
+const SimulatedUserPrompt = `You are role-playing a user talking to an AI assistant that has access to tools. Stay in character and pursue the goal below, answering the assistant's questions as this user would.
+
+Reply with only the user's next message, without quotes or commentary. Keep it short, as a real user would. When the goal has been achieved, or the assistant cannot make any further progress, reply with exactly ` + SimulatedUserDone + `.`
+
+// SimulatedUser drives a conversation with an LLM playing the user once the scripted turns are used up
+type SimulatedUser struct {
+	Persona  string `yaml:"persona,omitempty" json:"persona,omitempty" jsonschema:"Who the simulated user is, e.g. 'an on-call engineer who is short on time'"`
+	Goal     string `yaml:"goal" json:"goal" jsonschema:"What the simulated user wants to achieve; they end the conversation once it is done"`
+	MaxTurns int    `yaml:"max_turns,omitempty" json:"max_turns,omitempty" jsonschema:"Maximum number of messages the simulated user sends (default 5)"`
+	Model    string `yaml:"model,omitempty" json:"model,omitempty" jsonschema:"Model ID playing the user (defaults to the eval model)"`
+}
+
+// Validate checks the simulated user has a goal and a usable turn limit
+func (s *SimulatedUser) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if strings.TrimSpace(s.Goal) == "" {
+		return fmt.Errorf("goal is required")
+	}
+	if s.MaxTurns < 0 {
+		return fmt.Errorf("max_turns must be at least 1, got %d", s.MaxTurns)
+	}
+	return nil
+}
+
+func (s *SimulatedUser) maxTurns() int {
+	if s.MaxTurns <= 0 {
+		return 5
+	}
+	return s.MaxTurns
+}
+
+// TurnSource identifies where the user message of a conversation turn came from
+type TurnSource string
+
+const (
+	TurnSourcePrompt    TurnSource = "prompt"    // The eval's prompt or prompt_ref
+	TurnSourceScripted  TurnSource = "scripted"  // An entry of Eval.Turns
+	TurnSourceSimulated TurnSource = "simulated" // Written by the simulated user
+)
+
+// TurnTrace records one user message of a multi-turn eval and the agent's steps answering it
+type TurnTrace struct {
+	TurnNumber  int           `json:"turn_number"`          // 1-indexed turn number
+	Source      TurnSource    `json:"source"`               // Where the user message came from
+	UserMessage string        `json:"user_message"`         // Message sent by the user
+	Response    string        `json:"response"`             // Text the agent answered with over the turn's steps
+	FirstStep   int           `json:"first_step"`           // Number of the turn's first step in EvalTrace.Steps
+	LastStep    int           `json:"last_step"`            // Number of the turn's last step in EvalTrace.Steps
+	StartTime   time.Time     `json:"start_time"`           // When the user message was sent
+	Duration    time.Duration `json:"duration"`             // How long the agent took to answer
+	Simulation  *ModelCall    `json:"simulation,omitempty"` // The simulated user's request, for simulated turns
+}
+
+// ModelCall records a model request made on behalf of the eval outside the agentic loop
+type ModelCall struct {
+	Model         string           `json:"model"`                    // Model that answered
+	InputTokens   int              `json:"input_tokens"`             // Input tokens used
+	OutputTokens  int              `json:"output_tokens"`            // Output tokens used
+	Duration      time.Duration    `json:"duration"`                 // Request duration including retries
+	Attempts      []RequestAttempt `json:"attempts,omitempty"`       // Each model API attempt, including retries
+	RetryDuration time.Duration    `json:"retry_duration,omitempty"` // Time spent on failed attempts and backoff
+}
+
+// conversational reports whether the eval continues after the agent's first answer
+func (e Eval) conversational() bool {
+	return len(e.Turns) > 0 || e.SimulatedUser != nil
+}
+
+// nextUserTurn returns the user message for the turn after completed ones, using the
+// scripted turns first and then the simulated user. ok is false when the conversation is over.
+func (ec *EvalClient) nextUserTurn(ctx context.Context, eval Eval, completed []TurnTrace) (turn TurnTrace, ok bool, err error) {
+	followUps := len(completed) - 1 // the first turn is the eval's prompt
+	if followUps < len(eval.Turns) {
+		return TurnTrace{Source: TurnSourceScripted, UserMessage: eval.Turns[followUps]}, true, nil
+	}
+
+	simulated := followUps - len(eval.Turns)
+	if eval.SimulatedUser == nil || simulated >= eval.SimulatedUser.maxTurns() {
+		return TurnTrace{}, false, nil
+	}
+
+	message, call, err := ec.simulateUser(ctx, eval.SimulatedUser, completed)
+	if err != nil {
+		return TurnTrace{}, false, err
+	}
+	if message == "" || strings.Contains(message, SimulatedUserDone) {
+		return TurnTrace{}, false, nil
+	}
+	return TurnTrace{Source: TurnSourceSimulated, UserMessage: message, Simulation: call}, true, nil
+}
+
+// simulateUser asks the model playing the user for their next message
+func (ec *EvalClient) simulateUser(ctx context.Context, user *SimulatedUser, completed []TurnTrace) (string, *ModelCall, error) {
+	model := user.Model
+	if model == "" {
+		model = ec.config.Model
+	}
+
+	var system strings.Builder
+	system.WriteString(SimulatedUserPrompt)
+	if user.Persona != "" {
+		system.WriteString("\n\nPersona: " + user.Persona)
+	}
+	system.WriteString("\n\nGoal: " + user.Goal)
+
+	prompt := "Here is the conversation so far:\n\n" + formatTranscript(completed) +
+		"\n\nWrite the user's next message, or " + SimulatedUserDone + " if the conversation is over."
+
+	start := time.Now()
+	var resp *ProviderResponse
+	attempts, err := ec.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = ec.provider.CreateMessage(ctx, ProviderRequest{
+			Model:     model,
+			System:    system.String(),
+			Messages:  []Message{{Role: RoleUser, Content: []ContentBlock{TextBlock(prompt)}}},
+			MaxTokens: ec.config.MaxTokens,
+		})
+		return err
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("simulated user request failed: %w", err)
+	}
+
+	call := &ModelCall{
+		Model:         model,
+		InputTokens:   resp.Usage.InputTokens + resp.Usage.CacheCreationInputTokens + resp.Usage.CacheReadInputTokens,
+		OutputTokens:  resp.Usage.OutputTokens,
+		Duration:      time.Since(start),
+		Attempts:      attempts,
+		RetryDuration: retryDuration(attempts),
+	}
+	return strings.TrimSpace(resp.Text()), call, nil
+}
+
+// formatTranscript writes the turns as a conversation between the user and the assistant
+func formatTranscript(turns []TurnTrace) string {
+	parts := make([]string, 0, 2*len(turns))
+	for _, turn := range turns {
+		parts = append(parts, "[user] "+turn.UserMessage)
+		response := turn.Response
+		if response == "" {
+			response = "(no text response)"
+		}
+		parts = append(parts, "[assistant] "+response)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// appendUserText adds a user message to the conversation, joining it with a trailing
+// user message such as pending tool results
+func appendUserText(messages []Message, text string) []Message {
+	if n := len(messages); n > 0 && messages[n-1].Role == RoleUser {
+		messages[n-1].Content = append(messages[n-1].Content, TextBlock(text))
+		return messages
+	}
+	return append(messages, Message{Role: RoleUser, Content: []ContentBlock{TextBlock(text)}})
+}
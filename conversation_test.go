@@ -0,0 +1,215 @@
+package evaluations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalClient_RunEval_ScriptedTurns(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("Which environment should I use?"),
+		streamToolUseResponse("toolu_1", "echo", `{"message":"production"}`),
+		streamTextResponse("Echoed production."),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:   "clarify",
+		Prompt: "Echo the environment name",
+		Turns:  []string{"production"},
+	})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+
+	turns := result.Trace.Turns
+	assert.Len(turns, 2)
+	assert.Equal(TurnSourcePrompt, turns[0].Source)
+	assert.Equal("Echo the environment name", turns[0].UserMessage)
+	assert.Equal("Which environment should I use?", turns[0].Response)
+	assert.Equal(1, turns[0].FirstStep)
+	assert.Equal(1, turns[0].LastStep)
+	assert.Equal(TurnSourceScripted, turns[1].Source)
+	assert.Equal("production", turns[1].UserMessage)
+	assert.Equal(2, turns[1].FirstStep)
+	assert.Equal(3, turns[1].LastStep)
+	assert.Equal(3, result.Trace.StepCount)
+
+	// Assertions and grading judge the answer to the last turn
+	assert.Equal("Echoed production.", result.Result.RawResponse)
+
+	// The follow-up is sent after the agent's question
+	messages := api.Requests()[1]["messages"].([]any)
+	assert.Len(messages, 3)
+	last := messages[2].(map[string]any)
+	assert.Equal("user", last["role"])
+	assert.Equal("production", last["content"].([]any)[0].(map[string]any)["text"])
+
+	// The grader sees the whole conversation
+	grading := result.Trace.Grading.GradingPrompt
+	assert.Contains(grading, "conversation between the user and the LLM")
+	assert.Contains(grading, "[assistant] Which environment should I use?")
+	assert.Contains(grading, "[user] production")
+	assert.Contains(grading, "[assistant] Echoed production.")
+}
+
+func TestEvalClient_RunEval_SimulatedUser(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("What would you like me to echo?"),
+		streamTextResponse("Echo hello please"), // simulated user
+		streamToolUseResponse("toolu_1", "echo", `{"message":"hello"}`),
+		streamTextResponse("Echoed hello."),
+		streamTextResponse(SimulatedUserDone), // simulated user ends the conversation
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:   "simulated",
+		Prompt: "I need something echoed",
+		SimulatedUser: &SimulatedUser{
+			Persona: "a terse developer",
+			Goal:    "get the word hello echoed",
+			Model:   "user-model",
+		},
+	})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+
+	turns := result.Trace.Turns
+	assert.Len(turns, 2)
+	assert.Equal(TurnSourceSimulated, turns[1].Source)
+	assert.Equal("Echo hello please", turns[1].UserMessage)
+	assert.Equal("Echoed hello.", turns[1].Response)
+	assert.Equal("user-model", turns[1].Simulation.Model)
+	assert.Positive(turns[1].Simulation.OutputTokens)
+
+	// The simulated user plays the persona and sees the conversation so far
+	simulator := api.Requests()[1]
+	assert.Equal("user-model", simulator["model"])
+	assert.Nil(simulator["tools"])
+	system := simulator["system"].([]any)[0].(map[string]any)["text"].(string)
+	assert.Contains(system, "Persona: a terse developer")
+	assert.Contains(system, "Goal: get the word hello echoed")
+	prompt := simulator["messages"].([]any)[0].(map[string]any)["content"].([]any)[0].(map[string]any)["text"].(string)
+	assert.Contains(prompt, "[user] I need something echoed")
+	assert.Contains(prompt, "[assistant] What would you like me to echo?")
+
+	assert.Len(api.Requests(), 6)
+}
+
+func TestEvalClient_RunEval_SimulatedUserMaxTurns(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("Anything else?"),
+		streamTextResponse("Yes, one more thing"), // simulated user
+		streamTextResponse("Sure, what is it?"),
+		gradeResponse(4),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:          "limited",
+		Prompt:        "Hello",
+		SimulatedUser: &SimulatedUser{Goal: "keep talking", MaxTurns: 1},
+	})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+	assert.Len(result.Trace.Turns, 2)
+	assert.Len(api.Requests(), 4)
+}
+
+func TestEvalClient_RunEval_MaxStepsAcrossTurns(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamToolUseResponse("toolu_1", "echo", `{"message":"one"}`),
+		streamTextResponse("Echoed one."),
+		streamTextResponse("Echoing two."),
+		gradeResponse(4),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		MaxSteps:  3,
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:   "capped",
+		Prompt: "Echo one",
+		Turns:  []string{"Echo two", "Echo three"},
+		Assertions: []Assertion{
+			{Type: AssertionNotContains, Value: "one"},
+		},
+	})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+
+	// The third turn is never sent, the steps of the first two use up max_steps
+	assert.Equal(3, result.Trace.StepCount)
+	assert.Len(result.Trace.Turns, 2)
+	assert.Equal("Echoing two.", result.Result.RawResponse)
+	assert.Len(api.Requests(), 4)
+}
+
+func TestSimulatedUser_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *SimulatedUser
+		wantErr string
+	}{
+		{name: "nil", user: nil},
+		{name: "goal only", user: &SimulatedUser{Goal: "book a meeting"}},
+		{name: "missing goal", user: &SimulatedUser{Persona: "busy manager"}, wantErr: "goal is required"},
+		{name: "negative max turns", user: &SimulatedUser{Goal: "book a meeting", MaxTurns: -1}, wantErr: "max_turns must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			err := tt.user.Validate()
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package evaluations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/wolfeidau/mcp-evals/testdata/mcp-test-server/testserver"
+)
+
+// fakeAPIResponse is a canned response served by fakeModelAPI
+type fakeAPIResponse struct {
+	status  int
+	headers map[string]string
+	body    string // JSON body, or the SSE event stream when stream is true
+	stream  bool
+}
+
+// fakeModelAPI is a local stand-in for a model provider API (Anthropic Messages or
+// OpenAI chat completions) which serves queued responses in order and records
+// every request body
+type fakeModelAPI struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses []fakeAPIResponse
+	requests  []map[string]any
+}
+
+func newFakeModelAPI(t *testing.T, responses ...fakeAPIResponse) *fakeModelAPI {
+	t.Helper()
+
+	api := &fakeModelAPI{responses: responses}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.handle))
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+func (f *fakeModelAPI) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	var request map[string]any
+	_ = json.Unmarshal(body, &request)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, request)
+	if len(f.responses) == 0 {
+		f.mu.Unlock()
+		http.Error(w, `{"type":"error","error":{"type":"api_error","message":"no more fake responses"}}`, http.StatusInternalServerError)
+		return
+	}
+	resp := f.responses[0]
+	f.responses = f.responses[1:]
+	f.mu.Unlock()
+
+	for key, value := range resp.headers {
+		w.Header().Set(key, value)
+	}
+	if resp.stream {
+		w.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	status := resp.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, resp.body)
+}
+
+// Requests returns the decoded request bodies received so far
+func (f *fakeModelAPI) Requests() []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]map[string]any(nil), f.requests...)
+}
+
+// apiError builds an Anthropic style error response
+func apiError(status int, errorType string, headers map[string]string) fakeAPIResponse {
+	return fakeAPIResponse{
+		status:  status,
+		headers: headers,
+		body:    fmt.Sprintf(`{"type":"error","error":{"type":%q,"message":"fake %s"}}`, errorType, errorType),
+	}
+}
+
+// gradeResponse builds a streamed Messages response containing a grade
+func gradeResponse(score int) fakeAPIResponse {
+	grade := fmt.Sprintf(`{"accuracy":%d,"completeness":%d,"relevance":%d,"clarity":%d,"reasoning":%d,"overall_comments":"fake grade"}`,
+		score, score, score, score, score)
+	return streamTextResponse(grade)
+}
+
+// streamTextResponse builds a streamed Messages response that ends the turn with text
+func streamTextResponse(text string) fakeAPIResponse {
+	content, _ := json.Marshal(text)
+	return fakeAPIResponse{
+		stream: true,
+		body: sseStream(
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			fmt.Sprintf(`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":%s}}`, content),
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"message_delta","delta":{"stop_reason":"end_turn","stop_sequence":null},"usage":{"output_tokens":10}}`,
+		),
+	}
+}
+
+// streamToolUseResponse builds a streamed Messages response requesting a single tool call
+func streamToolUseResponse(id, name, input string) fakeAPIResponse {
+	partial, _ := json.Marshal(input)
+	return fakeAPIResponse{
+		stream: true,
+		body: sseStream(
+			fmt.Sprintf(`{"type":"content_block_start","index":0,"content_block":{"type":"tool_use","id":%q,"name":%q,"input":{}}}`, id, name),
+			fmt.Sprintf(`{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":%s}}`, partial),
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":10}}`,
+		),
+	}
+}
+
+// streamThinkingToolUseResponse builds a streamed Messages response with a signed thinking
+// block followed by a single tool call
+func streamThinkingToolUseResponse(thinking, signature, id, name, input string) fakeAPIResponse {
+	text, _ := json.Marshal(thinking)
+	partial, _ := json.Marshal(input)
+	return fakeAPIResponse{
+		stream: true,
+		body: sseStream(
+			`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":"","signature":""}}`,
+			fmt.Sprintf(`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":%s}}`, text),
+			fmt.Sprintf(`{"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":%q}}`, signature),
+			`{"type":"content_block_stop","index":0}`,
+			fmt.Sprintf(`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":%q,"name":%q,"input":{}}}`, id, name),
+			fmt.Sprintf(`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":%s}}`, partial),
+			`{"type":"content_block_stop","index":1}`,
+			`{"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":10}}`,
+		),
+	}
+}
+
+// sseStream wraps content events with message_start and message_stop events
+func sseStream(events ...string) string {
+	var sb strings.Builder
+
+	writeEvent := func(data string) {
+		var typed struct {
+			Type string `json:"type"`
+		}
+		_ = json.Unmarshal([]byte(data), &typed)
+		sb.WriteString("event: " + typed.Type + "\n")
+		sb.WriteString("data: " + data + "\n\n")
+	}
+
+	writeEvent(`{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","model":"test-model",` +
+		`"content":[],"stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":50,"output_tokens":1}}}`)
+	for _, event := range events {
+		writeEvent(event)
+	}
+	writeEvent(`{"type":"message_stop"}`)
+
+	return sb.String()
+}
+
+// newTestMCPServer serves the test MCP server over streamable HTTP so evals can run without a subprocess
+func newTestMCPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return testserver.New() }, nil))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"os"
+	"time"
 
 	evaluations "github.com/wolfeidau/mcp-evals"
 	"github.com/wolfeidau/mcp-evals/internal/help"
@@ -12,19 +13,38 @@ import (
 type Globals struct {
 }
 
-func createClient(config *evaluations.EvalConfig, apiKey, baseURL string, quiet bool) *evaluations.EvalClient {
+func createClient(config *evaluations.EvalConfig, apiKey, baseURL string, quiet bool, concurrency int, timeout time.Duration, cassette *evaluations.Cassette, cassetteMode evaluations.CassetteMode) (*evaluations.EvalClient, error) {
 	styles := help.DefaultStyles()
 
+	retry, err := config.Retry.Policy()
+	if err != nil {
+		return nil, err
+	}
+
 	clientConfig := evaluations.EvalClientConfig{
+		Provider:     config.Provider,
 		APIKey:       apiKey,
 		BaseURL:      baseURL,
+		Transport:    config.MCPServer.Transport,
 		Command:      config.MCPServer.Command,
 		Args:         config.MCPServer.Args,
 		Env:          config.MCPServer.Env,
+		URL:          config.MCPServer.URL,
+		Headers:      config.MCPServer.Headers,
+		Roots:        config.MCPServer.Roots,
+		SessionMode:  config.MCPServer.SessionMode,
 		Model:        config.Model,
 		GradingModel: config.GradingModel,
 		MaxSteps:     int(config.MaxSteps),
 		MaxTokens:    int(config.MaxTokens),
+		Concurrency:  concurrency,
+		Timeout:      timeout,
+		Trials:       config.Trials,
+		Retry:        retry,
+		Pricing:      config.Pricing,
+		Budget:       config.Budget,
+		Cassette:     cassette,
+		CassetteMode: cassetteMode,
 		StderrCallback: func(line string) {
 			if !quiet {
 				fmt.Fprintln(os.Stderr, styles.FormatMCPStderr(line))
@@ -32,6 +52,16 @@ func createClient(config *evaluations.EvalConfig, apiKey, baseURL string, quiet
 		},
 	}
 
+	if config.TrialPass != nil {
+		clientConfig.TrialPass = *config.TrialPass
+	}
+	if config.Grader != nil {
+		clientConfig.Grader = *config.Grader
+	}
+	if config.Thinking != nil {
+		clientConfig.ThinkingBudget = config.Thinking.BudgetTokens
+	}
+
 	// Map caching configuration from YAML to client config
 	if config.EnablePromptCaching != nil {
 		clientConfig.EnablePromptCaching = config.EnablePromptCaching
@@ -40,5 +70,5 @@ func createClient(config *evaluations.EvalConfig, apiKey, baseURL string, quiet
 		clientConfig.CacheTTL = config.CacheTTL
 	}
 
-	return evaluations.NewEvalClient(clientConfig)
+	return evaluations.NewEvalClient(clientConfig), nil
 }
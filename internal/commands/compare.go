@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wolfeidau/mcp-evals/internal/reporting"
+)
+
+// CompareCmd handles the compare command
+type CompareCmd struct {
+	Baseline  string `help:"Directory of trace files from the baseline run" required:"" type:"existingdir"`
+	Candidate string `help:"Directory of trace files from the candidate run" required:"" type:"existingdir"`
+
+	MaxScoreDrop        float64 `help:"Largest allowed drop in an eval's average score, negative disables" default:"0.5"`
+	MaxDimensionDrop    float64 `help:"Largest allowed drop in any grading dimension of an eval, negative disables" default:"1"`
+	MaxNewFailures      int     `help:"Number of evals allowed to go from passing to failing, negative disables" default:"0"`
+	MaxTokenIncrease    float64 `help:"Largest allowed increase in an eval's tokens in percent, negative disables" default:"-1"`
+	MaxDurationIncrease float64 `help:"Largest allowed increase in an eval's duration in percent, negative disables" default:"-1"`
+}
+
+// Run executes the compare command
+func (c *CompareCmd) Run(globals *Globals) error {
+	baseline, err := reporting.LoadTraceDir(c.Baseline)
+	if err != nil {
+		return fmt.Errorf("failed to load baseline: %w", err)
+	}
+	candidate, err := reporting.LoadTraceDir(c.Candidate)
+	if err != nil {
+		return fmt.Errorf("failed to load candidate: %w", err)
+	}
+
+	comparison := reporting.CompareResults(baseline, candidate)
+	regressions := comparison.Regressions(reporting.CompareThresholds{
+		MaxScoreDrop:        c.MaxScoreDrop,
+		MaxDimensionDrop:    c.MaxDimensionDrop,
+		MaxNewFailures:      c.MaxNewFailures,
+		MaxTokenIncrease:    c.MaxTokenIncrease,
+		MaxDurationIncrease: c.MaxDurationIncrease,
+	})
+
+	if err := reporting.PrintComparison(os.Stdout, comparison, regressions); err != nil {
+		return fmt.Errorf("failed to print comparison: %w", err)
+	}
+
+	if len(regressions) > 0 {
+		return fmt.Errorf("%d regressions exceed the thresholds", len(regressions))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+	"github.com/wolfeidau/mcp-evals/internal/reporting"
+)
+
+// ListCmd handles the list command
+type ListCmd struct {
+	Config string `help:"Path to evaluation configuration file (YAML or JSON)" required:"" type:"path"`
+
+	EvalSelection
+}
+
+// Run executes the list command
+func (l *ListCmd) Run(globals *Globals) error {
+	config, err := evaluations.LoadConfig(l.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	evals, err := l.Apply(config.Evals)
+	if err != nil {
+		return err
+	}
+
+	if err := reporting.PrintEvalList(os.Stdout, config, evals); err != nil {
+		return fmt.Errorf("failed to print eval list: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+	evaluations "github.com/wolfeidau/mcp-evals"
+	"github.com/wolfeidau/mcp-evals/internal/reporting"
+)
+
+// MockServerCmd handles the mock-server command
+type MockServerCmd struct {
+	Traces         string  `help:"Directory of trace files written by run --trace-dir" required:"" type:"existingdir"`
+	HTTP           string  `help:"Serve streamable HTTP on this address (e.g. localhost:8080) instead of stdio" name:"http"`
+	MinSimilarity  float64 `help:"Share of arguments a fuzzy match must have in common with the call, above 1 allows only exact matches" default:"0.5"`
+	UnmatchedError string  `help:"Tool error returned for calls that match no recorded call (default: \"no recorded call matches these arguments\")"`
+}
+
+// Run executes the mock-server command
+func (m *MockServerCmd) Run(globals *Globals) error {
+	results, err := reporting.LoadTraceDir(m.Traces)
+	if err != nil {
+		return fmt.Errorf("failed to load traces: %w", err)
+	}
+
+	server := evaluations.NewMockServer(results, evaluations.MockServerOptions{
+		MinSimilarity:  m.MinSimilarity,
+		UnmatchedError: m.UnmatchedError,
+	})
+
+	// Stdout carries the protocol in stdio mode, so progress is only logged
+	log.Info().
+		Str("traces", m.Traces).
+		Int("trace_files", len(results)).
+		Msg("Serving recorded tool calls")
+
+	if m.HTTP == "" {
+		return server.Run(context.Background(), &mcp.StdioTransport{})
+	}
+
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	httpServer := &http.Server{Addr: m.HTTP, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	log.Info().Str("addr", m.HTTP).Msg("Listening for streamable HTTP connections")
+	return httpServer.ListenAndServe()
+}
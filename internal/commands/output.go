@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+	"github.com/wolfeidau/mcp-evals/internal/reporting"
+)
+
+// outputSpec is a parsed --output value: a format and the file to write it to,
+// or stdout when path is empty
+type outputSpec struct {
+	format   string
+	path     string
+	reporter reporting.Reporter
+}
+
+// parseOutputs parses --output values of the form FORMAT or FORMAT=PATH. A path
+// of "-" is the same as no path. At most one output may be written to stdout.
+func parseOutputs(values []string) ([]outputSpec, error) {
+	specs := make([]outputSpec, 0, len(values))
+	toStdout := false
+
+	for _, value := range values {
+		format, path, _ := strings.Cut(value, "=")
+		spec := outputSpec{format: strings.ToLower(strings.TrimSpace(format)), path: strings.TrimSpace(path)}
+		if spec.path == "-" {
+			spec.path = ""
+		}
+
+		reporter, err := reporting.NewReporter(spec.format)
+		if err != nil {
+			return nil, err
+		}
+		spec.reporter = reporter
+		if spec.path == "" {
+			if toStdout {
+				return nil, fmt.Errorf("only one output can be written to stdout, give the others a path with FORMAT=PATH")
+			}
+			toStdout = true
+		}
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// hasStdoutOutput reports whether any output is written to stdout, in which case
+// progress and the styled report are suppressed so stdout only holds that output
+func hasStdoutOutput(specs []outputSpec) bool {
+	for _, spec := range specs {
+		if spec.path == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeOutputs writes the results in each requested format
+func writeOutputs(specs []outputSpec, info reporting.RunInfo, results []evaluations.EvalRunResult) error {
+	for _, spec := range specs {
+		if err := spec.write(info, results); err != nil {
+			return fmt.Errorf("failed to write %s output: %w", spec.format, err)
+		}
+	}
+	return nil
+}
+
+func (s outputSpec) write(info reporting.RunInfo, results []evaluations.EvalRunResult) error {
+	if s.path == "" {
+		return s.reporter.Write(os.Stdout, info, results)
+	}
+
+	f, err := os.Create(s.path)
+	if err != nil {
+		return err
+	}
+	if err := s.reporter.Write(f, info, results); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// reportFileFormat picks the report format for a --report-file path from its extension
+func reportFileFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return "markdown", nil
+	case ".html", ".htm":
+		return "html", nil
+	default:
+		return "", fmt.Errorf("cannot tell the report format of '%s': use a .md or .html extension", path)
+	}
+}
+
+// writeReportFile renders a report of the results to path in the format given by its extension
+func writeReportFile(path string, verbose bool, results []evaluations.EvalRunResult) error {
+	format, err := reportFileFormat(path)
+	if err != nil {
+		return err
+	}
+	renderer, err := reporting.NewRenderer(format, verbose)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	if err := renderer.Render(f, results); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	return f.Close()
+}
@@ -2,7 +2,9 @@ package commands
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	evaluations "github.com/wolfeidau/mcp-evals"
 	"github.com/wolfeidau/mcp-evals/internal/reporting"
@@ -12,10 +14,21 @@ import (
 type ReportCmd struct {
 	TraceFiles []string `help:"Path(s) to trace JSON file(s)" required:"" type:"existingfile"`
 	Verbose    bool     `help:"Show detailed per-eval breakdown" short:"v"`
+	Format     string   `help:"Report format: text, markdown or html. Markdown and html always include the per-eval breakdown" default:"text"`
+	Output     []string `help:"Write results in a machine-readable format as FORMAT or FORMAT=PATH (formats: json, junit, tap). Without a path the output replaces the report on stdout" short:"o"`
 }
 
 // Run executes the report command
 func (r *ReportCmd) Run(globals *Globals) error {
+	outputs, err := parseOutputs(r.Output)
+	if err != nil {
+		return err
+	}
+	renderer, err := reporting.NewRenderer(strings.ToLower(r.Format), r.Verbose)
+	if err != nil {
+		return err
+	}
+
 	// Load trace files
 	results := make([]evaluations.EvalRunResult, 0, len(r.TraceFiles))
 
@@ -27,6 +40,14 @@ func (r *ReportCmd) Run(globals *Globals) error {
 		results = append(results, result)
 	}
 
-	// Generate styled report
-	return reporting.PrintStyledReport(results, r.Verbose)
+	// Trace files don't record the run configuration, so the run info is left empty
+	if err := writeOutputs(outputs, reporting.RunInfo{}, results); err != nil {
+		return err
+	}
+	if hasStdoutOutput(outputs) {
+		return nil
+	}
+
+	// Generate the report
+	return renderer.Render(os.Stdout, results)
 }
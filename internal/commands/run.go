@@ -18,22 +18,45 @@ import (
 
 // RunCmd handles the run command
 type RunCmd struct {
-	Quiet    bool   `help:"Suppress progress output, only show summary" short:"q"`
-	TraceDir string `help:"Directory to write trace files" type:"path"`
-	Config   string `help:"Path to evaluation configuration file (YAML or JSON)" required:"" type:"path"`
-	APIKey   string `help:"Anthropic API key (overrides ANTHROPIC_API_KEY env var)"`
-	BaseURL  string `help:"Base URL for Anthropic API (overrides ANTHROPIC_BASE_URL env var)"`
-	Verbose  bool   `help:"Show detailed per-eval breakdown" short:"v"`
-	Filter   string `help:"Regex pattern to filter which evals to run (matches against eval name)" short:"f"`
+	Quiet      bool     `help:"Suppress progress output, only show summary" short:"q"`
+	TraceDir   string   `help:"Directory to write trace files" type:"path"`
+	Config     string   `help:"Path to evaluation configuration file (YAML or JSON)" required:"" type:"path"`
+	APIKey     string   `help:"API key for the model provider (overrides ANTHROPIC_API_KEY or OPENAI_API_KEY env var)"`
+	BaseURL    string   `help:"Base URL for the model provider API (overrides ANTHROPIC_BASE_URL or OPENAI_BASE_URL env var)"`
+	Verbose    bool     `help:"Show detailed per-eval breakdown" short:"v"`
+	DryRun     bool     `help:"List the evals that would run with their tags and settings, without starting the MCP server"`
+	Parallel   int      `help:"Number of evals to run concurrently" short:"p" default:"1"`
+	Output     []string `help:"Write results in a machine-readable format as FORMAT or FORMAT=PATH (formats: json, junit, tap). Without a path the output replaces the report on stdout" short:"o"`
+	ReportFile string   `help:"Also write the report to a file, in Markdown (.md) or HTML (.html) according to its extension" type:"path"`
+	Record     string   `help:"Record every model request and response to a cassette file for later replay" type:"path" xor:"cassette"`
+	Replay     string   `help:"Answer model requests from a cassette file written by --record instead of calling the model API, no API key is needed" type:"path" xor:"cassette"`
 
 	// MCP Server overrides
-	MCPCommand string   `help:"Override MCP server command from config"`
-	MCPArgs    []string `help:"Override MCP server args from config"`
-	MCPEnv     []string `help:"Override MCP server env vars from config"`
+	MCPTransport string            `help:"Override MCP server transport from config (stdio, sse, streamable-http)"`
+	MCPCommand   string            `help:"Override MCP server command from config"`
+	MCPArgs      []string          `help:"Override MCP server args from config"`
+	MCPEnv       []string          `help:"Override MCP server env vars from config"`
+	MCPURL       string            `help:"Override MCP server URL from config (sse and streamable-http transports)" name:"mcp-url"`
+	MCPHeaders   map[string]string `help:"Override MCP server HTTP headers from config (KEY=VALUE)" name:"mcp-headers"`
+
+	EvalSelection
 }
 
 // Run executes the run command
 func (r *RunCmd) Run(globals *Globals) error {
+	outputs, err := parseOutputs(r.Output)
+	if err != nil {
+		return err
+	}
+	if r.ReportFile != "" {
+		if _, err := reportFileFormat(r.ReportFile); err != nil {
+			return err
+		}
+	}
+
+	// Keep stdout for the machine-readable output when it is written there
+	quiet := r.Quiet || hasStdoutOutput(outputs)
+
 	// Load configuration
 	config, err := evaluations.LoadConfig(r.Config)
 	if err != nil {
@@ -41,6 +64,9 @@ func (r *RunCmd) Run(globals *Globals) error {
 	}
 
 	// Apply MCP server overrides from command-line flags
+	if r.MCPTransport != "" {
+		config.MCPServer.Transport = evaluations.MCPTransport(r.MCPTransport)
+	}
 	if r.MCPCommand != "" {
 		config.MCPServer.Command = r.MCPCommand
 	}
@@ -50,59 +76,129 @@ func (r *RunCmd) Run(globals *Globals) error {
 	if len(r.MCPEnv) > 0 {
 		config.MCPServer.Env = r.MCPEnv
 	}
+	if r.MCPURL != "" {
+		config.MCPServer.URL = r.MCPURL
+	}
+	if len(r.MCPHeaders) > 0 {
+		config.MCPServer.Headers = r.MCPHeaders
+	}
+	if err := config.MCPServer.Validate(); err != nil {
+		return fmt.Errorf("invalid MCP server configuration: %w", err)
+	}
 
-	// Filter evals if pattern provided
+	// Select evals if any selection flags were provided
 	evalsToRun := config.Evals
-	if r.Filter != "" {
-		filtered, err := filterEvals(config.Evals, r.Filter)
+	if r.IsSet() {
+		selected, err := r.Apply(config.Evals)
 		if err != nil {
-			return fmt.Errorf("invalid filter pattern: %w", err)
+			return err
 		}
-		if len(filtered) == 0 {
-			return fmt.Errorf("no evals matched filter pattern: %s", r.Filter)
+		if len(selected) == 0 && !r.DryRun {
+			return fmt.Errorf("no evals matched %s", r.EvalSelection)
 		}
-		evalsToRun = filtered
+		evalsToRun = selected
+
+		if !quiet && !r.DryRun {
+			fmt.Printf("Selection (%s) matched %d of %d eval(s)\n", r.EvalSelection, len(selected), len(config.Evals))
+		}
+	}
 
-		if !r.Quiet {
-			fmt.Printf("Filter '%s' matched %d of %d eval(s)\n", r.Filter, len(filtered), len(config.Evals))
+	if r.DryRun {
+		if err := reporting.PrintEvalList(os.Stdout, config, evalsToRun); err != nil {
+			return fmt.Errorf("failed to print eval list: %w", err)
 		}
+		return nil
 	}
 
-	// Parse timeout if specified
-	var timeout time.Duration
+	// Parse timeouts if specified, the eval timeout is applied by the client to each eval
+	var timeout, suiteTimeout time.Duration
 	if config.Timeout != "" {
 		timeout, err = time.ParseDuration(config.Timeout)
 		if err != nil {
 			return fmt.Errorf("invalid timeout: %w", err)
 		}
 	}
+	if config.SuiteTimeout != "" {
+		suiteTimeout, err = time.ParseDuration(config.SuiteTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid suite_timeout: %w", err)
+		}
+	}
 
-	// Create context with timeout
+	// Create context with the suite timeout
 	ctx := context.Background()
-	if timeout > 0 {
+	if suiteTimeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, timeout)
+		ctx, cancel = context.WithTimeout(ctx, suiteTimeout)
 		defer cancel()
 	}
 
-	// Resolve base URL: flag takes precedence, then env var
+	// Resolve base URL: flag takes precedence, then the provider's env var
 	resolvedBaseURL := r.BaseURL
 	if resolvedBaseURL == "" {
-		resolvedBaseURL = os.Getenv("ANTHROPIC_BASE_URL")
+		if config.Provider == evaluations.ProviderOpenAI {
+			resolvedBaseURL = os.Getenv("OPENAI_BASE_URL")
+		} else {
+			resolvedBaseURL = os.Getenv("ANTHROPIC_BASE_URL")
+		}
+	}
+
+	// Record model interactions to a cassette or replay them from one
+	var cassette *evaluations.Cassette
+	var cassetteMode evaluations.CassetteMode
+	switch {
+	case r.Replay != "":
+		cassette, err = evaluations.LoadCassette(r.Replay)
+		if err != nil {
+			return err
+		}
+		cassetteMode = evaluations.CassetteReplay
+	case r.Record != "":
+		cassette = evaluations.NewCassette()
+		cassetteMode = evaluations.CassetteRecord
 	}
 
 	// Create client
-	client := createClient(config, r.APIKey, resolvedBaseURL, r.Quiet)
+	if r.Parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1, got %d", r.Parallel)
+	}
+	client, err := createClient(config, r.APIKey, resolvedBaseURL, quiet, r.Parallel, timeout, cassette, cassetteMode)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
 
 	// Run evaluations
-	if !r.Quiet {
-		fmt.Printf("Running %d evaluation(s)...\n\n", len(evalsToRun))
+	runInfo := reporting.NewRunInfo(r.Config, config)
+	runInfo.Filter = r.Filter
+	runInfo.Exclude = r.Exclude
+	runInfo.Tags = r.Tag
+	runInfo.ExcludeTags = r.ExcludeTag
+	runInfo.Select = r.Select
+	runInfo.StartedAt = time.Now().UTC()
+
+	if !quiet {
+		if r.Parallel > 1 {
+			fmt.Printf("Running %d evaluation(s) with %d workers...\n\n", len(evalsToRun), r.Parallel)
+		} else {
+			fmt.Printf("Running %d evaluation(s)...\n\n", len(evalsToRun))
+		}
 	}
 
-	results, err := runEvals(ctx, client, evalsToRun, r.Quiet)
+	results, err := runEvals(ctx, client, evalsToRun, quiet, r.Parallel > 1)
 	if err != nil {
 		return err
 	}
+	runInfo.FinishedAt = time.Now().UTC()
+
+	if r.Record != "" {
+		if err := cassette.Save(r.Record); err != nil {
+			return err
+		}
+		if !quiet {
+			fmt.Printf("Recorded %d model interaction(s) to %s\n\n", cassette.Len(), r.Record)
+		}
+	}
 
 	// Write traces if directory specified
 	if r.TraceDir != "" {
@@ -112,9 +208,20 @@ func (r *RunCmd) Run(globals *Globals) error {
 		}
 	}
 
+	if err := writeOutputs(outputs, runInfo, results); err != nil {
+		return err
+	}
+	if r.ReportFile != "" {
+		if err := writeReportFile(r.ReportFile, r.Verbose, results); err != nil {
+			return err
+		}
+	}
+
 	// Print summary using new reporting system
-	if err := reporting.PrintStyledReport(results, r.Verbose); err != nil {
-		return fmt.Errorf("failed to print report: %w", err)
+	if !hasStdoutOutput(outputs) {
+		if err := reporting.PrintStyledReport(results, r.Verbose); err != nil {
+			return fmt.Errorf("failed to print report: %w", err)
+		}
 	}
 
 	// Check for failures
@@ -125,53 +232,73 @@ func (r *RunCmd) Run(globals *Globals) error {
 	return nil
 }
 
-func runEvals(ctx context.Context, client *evaluations.EvalClient, evals []evaluations.Eval, quiet bool) ([]evaluations.EvalRunResult, error) {
+// runEvals executes the evals and prints progress as they run. When parallel is
+// true evals complete out of order, so each eval's header and status are
+// printed together once it finishes to keep the output readable.
+func runEvals(ctx context.Context, client *evaluations.EvalClient, evals []evaluations.Eval, quiet, parallel bool) ([]evaluations.EvalRunResult, error) {
 	styles := help.DefaultStyles()
-	results := make([]evaluations.EvalRunResult, len(evals))
 
 	// Style for indented content (description, status)
 	indentStyle := lipgloss.NewStyle().Padding(0, 0, 0, 8)
 
-	for i, eval := range evals {
-		if !quiet {
-			// Print eval header with index
-			header := fmt.Sprintf("[%d/%d] Running eval: %s", i+1, len(evals), eval.Name)
-			fmt.Println(styles.Heading.Render(header))
+	printHeader := func(i int, eval evaluations.Eval) {
+		// Print eval header with index
+		header := fmt.Sprintf("[%d/%d] Running eval: %s", i+1, len(evals), eval.Name)
+		fmt.Println(styles.Heading.Render(header))
 
-			if eval.Description != "" {
-				desc := indentStyle.Render(styles.Muted.Render(eval.Description))
-				fmt.Println(desc)
-			}
+		if eval.Description != "" {
+			desc := indentStyle.Render(styles.Muted.Render(eval.Description))
+			fmt.Println(desc)
 		}
+	}
 
-		result, err := client.RunEval(ctx, eval)
-		if err != nil {
-			results[i] = evaluations.EvalRunResult{
-				Eval:  eval,
-				Error: err,
-			}
-			if !quiet {
-				errMsg := fmt.Sprintf("❌ Error: %v", err)
-				fmt.Println(indentStyle.Render(styles.Error.Render(errMsg)))
-				fmt.Println()
+	progress := func(i int, eval evaluations.Eval, result *evaluations.EvalRunResult) {
+		if quiet {
+			return
+		}
+
+		// Eval started
+		if result == nil {
+			if !parallel {
+				printHeader(i, eval)
 			}
-			continue
+			return
 		}
 
-		results[i] = *result
+		if parallel {
+			printHeader(i, eval)
+		}
 
-		if !quiet {
-			if result.Grade != nil {
-				msg := fmt.Sprintf("✓ Completed (avg score: %.1f/5)", avgScore(result.Grade))
-				fmt.Println(indentStyle.Render(styles.Success.Render(msg)))
+		switch {
+		case result.Aggregate != nil:
+			msg := formatTrialSummary(result.Aggregate)
+			if result.Aggregate.Passed {
+				fmt.Println(indentStyle.Render(styles.Success.Render("✓ " + msg)))
 			} else {
-				fmt.Println(indentStyle.Render(styles.Success.Render("✓ Completed")))
+				fmt.Println(indentStyle.Render(styles.Error.Render("✗ " + msg)))
 			}
-			fmt.Println()
+		case result.Status == evaluations.EvalStatusTimeout:
+			errMsg := fmt.Sprintf("⏱ Timed out: %v", result.Error)
+			fmt.Println(indentStyle.Render(styles.Error.Render(errMsg)))
+		case result.Status == evaluations.EvalStatusBudgetExceeded:
+			errMsg := fmt.Sprintf("$ Budget exceeded: %v", result.Error)
+			fmt.Println(indentStyle.Render(styles.Error.Render(errMsg)))
+		case result.Trace == nil && result.Error != nil:
+			errMsg := fmt.Sprintf("❌ Error: %v", result.Error)
+			fmt.Println(indentStyle.Render(styles.Error.Render(errMsg)))
+		case result.CheckFailures() != nil:
+			errMsg := fmt.Sprintf("✗ %v", result.CheckFailures())
+			fmt.Println(indentStyle.Render(styles.Error.Render(errMsg)))
+		case result.Grade != nil:
+			msg := fmt.Sprintf("✓ Completed (avg score: %.1f/5)", result.Grade.Average())
+			fmt.Println(indentStyle.Render(styles.Success.Render(msg)))
+		default:
+			fmt.Println(indentStyle.Render(styles.Success.Render("✓ Completed")))
 		}
+		fmt.Println()
 	}
 
-	return results, nil
+	return client.RunEvalsWithProgress(ctx, evals, progress)
 }
 
 func writeTraces(results []evaluations.EvalRunResult, traceDir string) error {
@@ -181,24 +308,15 @@ func writeTraces(results []evaluations.EvalRunResult, traceDir string) error {
 	}
 
 	for _, result := range results {
-		if result.Trace == nil {
+		if result.Trace == nil && result.Aggregate == nil {
 			continue
 		}
 
 		filename := filepath.Join(traceDir, fmt.Sprintf("%s.json", result.Eval.Name))
 
-		// Save full result (eval + grade + trace) for better report generation
-		traceData := struct {
-			Eval  evaluations.Eval         `json:"eval"`
-			Grade *evaluations.GradeResult `json:"grade,omitempty"`
-			Trace *evaluations.EvalTrace   `json:"trace"`
-		}{
-			Eval:  result.Eval,
-			Grade: result.Grade,
-			Trace: result.Trace,
-		}
-
-		data, err := json.MarshalIndent(traceData, "", "  ")
+		// Save the full result so reports, comparisons and the mock server see the same
+		// errors and statuses as the run, evals with several trials include each trial
+		data, err := json.MarshalIndent(result, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal trace for %s: %w", result.Eval.Name, err)
 		}
@@ -211,15 +329,22 @@ func writeTraces(results []evaluations.EvalRunResult, traceDir string) error {
 	return nil
 }
 
+// formatTrialSummary describes how an eval's trials went, e.g. "4/5 trials passed (rule: all, mean score 3.8 ± 0.4)"
+func formatTrialSummary(aggregate *evaluations.TrialAggregate) string {
+	msg := fmt.Sprintf("%d/%d trials passed (rule: %s", aggregate.Passes, aggregate.Trials, aggregate.Policy.Rule)
+	if aggregate.Scores != nil {
+		msg += fmt.Sprintf(", mean score %.1f ± %.1f", aggregate.Scores.Average.Mean, aggregate.Scores.Average.StdDev)
+	}
+	return msg + ")"
+}
+
 func hasFailures(results []evaluations.EvalRunResult) bool {
 	for _, result := range results {
 		if result.Error != nil {
 			return true
 		}
 		if result.Grade != nil {
-			sum := result.Grade.Accuracy + result.Grade.Completeness + result.Grade.Relevance + result.Grade.Clarity + result.Grade.Reasoning
-			avg := float64(sum) / 5.0
-			if avg < 3.0 {
+			if result.Grade.Average() < evaluations.PassingScore {
 				return true
 			}
 		}
@@ -227,11 +352,6 @@ func hasFailures(results []evaluations.EvalRunResult) bool {
 	return false
 }
 
-func avgScore(grade *evaluations.GradeResult) float64 {
-	sum := grade.Accuracy + grade.Completeness + grade.Relevance + grade.Clarity + grade.Reasoning
-	return float64(sum) / 5.0
-}
-
 // filterEvals filters evaluations by regex pattern matching against eval names
 func filterEvals(evals []evaluations.Eval, pattern string) ([]evaluations.Eval, error) {
 	regex, err := regexp.Compile(pattern)
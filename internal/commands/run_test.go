@@ -1,10 +1,16 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	evaluations "github.com/wolfeidau/mcp-evals"
+	"github.com/wolfeidau/mcp-evals/internal/reporting"
 )
 
 func TestFilterEvals(t *testing.T) {
@@ -143,3 +149,198 @@ func TestFilterEvals_ComplexPattern(t *testing.T) {
 	}
 	assert.Equal([]string{"api_v2_users", "api_v2_posts"}, names)
 }
+
+func TestParseOutputs(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     []string
+		want       []string
+		wantStdout bool
+		wantErr    string
+	}{
+		{name: "none", values: nil, want: []string{}},
+		{name: "stdout", values: []string{"json"}, want: []string{"json"}, wantStdout: true},
+		{name: "dash is stdout", values: []string{"json=-"}, want: []string{"json"}, wantStdout: true},
+		{name: "file", values: []string{"JSON=out/results.json"}, want: []string{"json=out/results.json"}},
+		{name: "file and stdout", values: []string{"json=results.json", "json"}, want: []string{"json=results.json", "json"}, wantStdout: true},
+		{name: "junit and tap", values: []string{"junit=junit.xml", "TAP"}, want: []string{"junit=junit.xml", "tap"}, wantStdout: true},
+		{name: "unknown format", values: []string{"yaml"}, wantErr: "invalid output format 'yaml': must be one of: json, junit, tap"},
+		{name: "two to stdout", values: []string{"json", "tap=-"}, wantErr: "only one output can be written to stdout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			specs, err := parseOutputs(tt.values)
+			if tt.wantErr != "" {
+				assert.Error(err)
+				assert.Contains(err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+
+			got := make([]string, len(specs))
+			for i, spec := range specs {
+				assert.NotNil(spec.reporter)
+				got[i] = spec.format
+				if spec.path != "" {
+					got[i] += "=" + spec.path
+				}
+			}
+			assert.Equal(tt.want, got)
+			assert.Equal(tt.wantStdout, hasStdoutOutput(specs))
+		})
+	}
+}
+
+func TestWriteOutputs(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	results := []evaluations.EvalRunResult{
+		{
+			Eval:  evaluations.Eval{Name: "add"},
+			Grade: &evaluations.GradeResult{Accuracy: 5, Completeness: 5, Relevance: 5, Clarity: 5, Reasoning: 5},
+			Trace: &evaluations.EvalTrace{StepCount: 1},
+		},
+		{Eval: evaluations.Eval{Name: "broken"}, Error: errors.New("failed to connect to MCP server")},
+	}
+
+	specs, err := parseOutputs([]string{
+		"json=" + filepath.Join(dir, "results.json"),
+		"junit=" + filepath.Join(dir, "junit.xml"),
+		"tap=" + filepath.Join(dir, "results.tap"),
+	})
+	assert.NoError(err)
+	assert.NoError(writeOutputs(specs, reporting.RunInfo{Model: "test-model"}, results))
+
+	data, err := os.ReadFile(filepath.Join(dir, "results.json"))
+	assert.NoError(err)
+
+	var doc reporting.Results
+	assert.NoError(json.Unmarshal(data, &doc))
+	assert.Equal(reporting.ResultsVersion, doc.Version)
+	assert.Equal("test-model", doc.Run.Model)
+	assert.Equal(1, doc.Summary.Passed)
+	assert.Equal(1, doc.Summary.Errors)
+	assert.Equal("failed to connect to MCP server", doc.Evals[1].Error)
+
+	junit, err := os.ReadFile(filepath.Join(dir, "junit.xml"))
+	assert.NoError(err)
+	assert.Contains(string(junit), `<testsuites name="mcp-evals" tests="2" failures="0" errors="1"`)
+
+	tap, err := os.ReadFile(filepath.Join(dir, "results.tap"))
+	assert.NoError(err)
+	assert.Contains(string(tap), "ok 1 - add\n")
+	assert.Contains(string(tap), "not ok 2 - broken\n")
+}
+
+func TestWriteReportFile(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	results := []evaluations.EvalRunResult{
+		{
+			Eval:  evaluations.Eval{Name: "add"},
+			Grade: &evaluations.GradeResult{Accuracy: 5, Completeness: 5, Relevance: 5, Clarity: 5, Reasoning: 5},
+			Trace: &evaluations.EvalTrace{StepCount: 1},
+		},
+	}
+
+	assert.NoError(writeReportFile(filepath.Join(dir, "report.md"), false, results))
+	data, err := os.ReadFile(filepath.Join(dir, "report.md"))
+	assert.NoError(err)
+	assert.Contains(string(data), "| add | ✅ PASS | 5.0 |")
+
+	assert.NoError(writeReportFile(filepath.Join(dir, "report.HTML"), false, results))
+	data, err = os.ReadFile(filepath.Join(dir, "report.HTML"))
+	assert.NoError(err)
+	assert.Contains(string(data), "<!DOCTYPE html>")
+
+	err = writeReportFile(filepath.Join(dir, "report.pdf"), false, results)
+	assert.ErrorContains(err, "use a .md or .html extension")
+	assert.NoFileExists(filepath.Join(dir, "report.pdf"))
+}
+
+func TestWriteTraces_RoundTrip(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	grade := &evaluations.GradeResult{Accuracy: 4, Completeness: 4, Relevance: 4, Clarity: 4, Reasoning: 4}
+	results := []evaluations.EvalRunResult{
+		{
+			Eval:   evaluations.Eval{Name: "slow", Tags: []string{"slow"}},
+			Status: evaluations.EvalStatusTimeout,
+			Error:  errors.New("eval timed out after 1m0s: context deadline exceeded"),
+			Trace:  &evaluations.EvalTrace{StepCount: 2, Status: evaluations.EvalStatusTimeout},
+		},
+		{
+			Eval:      evaluations.Eval{Name: "flaky"},
+			Error:     errors.New("1 of 2 trials passed, pass rate 0.50 is below 1.00"),
+			Aggregate: &evaluations.TrialAggregate{Trials: 2, Passes: 1},
+			Trials: []evaluations.EvalRunResult{
+				{Eval: evaluations.Eval{Name: "flaky"}, Grade: grade, Trace: &evaluations.EvalTrace{StepCount: 1}},
+				{Eval: evaluations.Eval{Name: "flaky"}, Error: errors.New("failed to call tool"), Trace: &evaluations.EvalTrace{StepCount: 1}},
+			},
+		},
+		{Eval: evaluations.Eval{Name: "broken"}, Error: errors.New("failed to connect to MCP server")},
+	}
+
+	assert.NoError(writeTraces(results, dir))
+	assert.NoFileExists(filepath.Join(dir, "broken.json"))
+
+	slow, err := reporting.LoadTraceFile(filepath.Join(dir, "slow.json"))
+	assert.NoError(err)
+	assert.Equal(results[0].Eval, slow.Eval)
+	assert.Equal(evaluations.EvalStatusTimeout, slow.Status)
+	assert.EqualError(slow.Error, results[0].Error.Error())
+
+	flaky, err := reporting.LoadTraceFile(filepath.Join(dir, "flaky.json"))
+	assert.NoError(err)
+	assert.EqualError(flaky.Error, results[1].Error.Error())
+	assert.Equal(results[1].Aggregate, flaky.Aggregate)
+	assert.Len(flaky.Trials, 2)
+	assert.Equal(grade, flaky.Trials[0].Grade)
+	assert.NoError(flaky.Trials[0].Error)
+	assert.EqualError(flaky.Trials[1].Error, "failed to call tool")
+}
+
+func TestWriteTraces_ReportsFailuresAfterReload(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	rubric := &evaluations.GradingRubric{MinimumScores: map[string]int{"accuracy": 3}}
+	grade := &evaluations.GradeResult{Accuracy: 2, Completeness: 5, Relevance: 5, Clarity: 5, Reasoning: 5}
+	results := []evaluations.EvalRunResult{
+		{
+			Eval:  evaluations.Eval{Name: "below_minimum", GradingRubric: rubric},
+			Grade: grade,
+			Error: rubric.CheckMinimumScores(grade),
+			Trace: &evaluations.EvalTrace{StepCount: 1},
+		},
+		{
+			Eval:  evaluations.Eval{Name: "ungraded"},
+			Error: errors.New("grading failed: unexpected end of JSON input"),
+			Trace: &evaluations.EvalTrace{StepCount: 1},
+		},
+	}
+	assert.NoError(writeTraces(results, dir))
+
+	var reloaded []evaluations.EvalRunResult
+	for _, name := range []string{"below_minimum", "ungraded"} {
+		result, err := reporting.LoadTraceFile(filepath.Join(dir, name+".json"))
+		assert.NoError(err)
+		assert.Error(result.Error)
+		reloaded = append(reloaded, result)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(reporting.WriteJUnit(&buf, reporting.RunInfo{}, reloaded))
+	assert.Contains(buf.String(), `tests="2" failures="1" errors="1"`)
+
+	buf.Reset()
+	assert.NoError(reporting.WriteTAP(&buf, reporting.RunInfo{}, reloaded))
+	assert.Contains(buf.String(), "not ok 1 - below_minimum\n  ---\n  status: fail\n")
+	assert.Contains(buf.String(), "not ok 2 - ungraded\n  ---\n  status: error\n")
+}
@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+)
+
+// EvalSelection holds the flags choosing which evals of a config to run. An eval is
+// selected when it passes every flag that is set.
+type EvalSelection struct {
+	Filter     string   `help:"Regex pattern to filter which evals to run (matches against eval name)" short:"f"`
+	Exclude    string   `help:"Regex pattern of eval names to skip"`
+	Tag        []string `help:"Only run evals with at least one of these tags"`
+	ExcludeTag []string `help:"Skip evals with any of these tags"`
+	Select     string   `help:"Boolean expression over eval tags choosing which evals to run, using &&, ||, ! and parentheses (e.g. 'smoke && !slow')"`
+}
+
+// IsSet reports whether any selection flag was given
+func (s EvalSelection) IsSet() bool {
+	return s.Filter != "" || s.Exclude != "" || len(s.Tag) > 0 || len(s.ExcludeTag) > 0 || s.Select != ""
+}
+
+// String describes the selection flags that were given, e.g. "filter 'auth', tag smoke"
+func (s EvalSelection) String() string {
+	var parts []string
+	if s.Filter != "" {
+		parts = append(parts, fmt.Sprintf("filter '%s'", s.Filter))
+	}
+	if s.Exclude != "" {
+		parts = append(parts, fmt.Sprintf("exclude '%s'", s.Exclude))
+	}
+	if len(s.Tag) > 0 {
+		parts = append(parts, "tag "+strings.Join(s.Tag, ", "))
+	}
+	if len(s.ExcludeTag) > 0 {
+		parts = append(parts, "exclude tag "+strings.Join(s.ExcludeTag, ", "))
+	}
+	if s.Select != "" {
+		parts = append(parts, fmt.Sprintf("select '%s'", s.Select))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Apply returns the evals passing the selection, keeping their order
+func (s EvalSelection) Apply(evals []evaluations.Eval) ([]evaluations.Eval, error) {
+	var exclude *regexp.Regexp
+	if s.Exclude != "" {
+		var err error
+		if exclude, err = regexp.Compile(s.Exclude); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+		}
+	}
+	var expr tagExpr
+	if s.Select != "" {
+		var err error
+		if expr, err = parseTagExpr(s.Select); err != nil {
+			return nil, fmt.Errorf("invalid select expression: %w", err)
+		}
+	}
+
+	if s.Filter != "" {
+		var err error
+		if evals, err = filterEvals(evals, s.Filter); err != nil {
+			return nil, fmt.Errorf("invalid filter pattern: %w", err)
+		}
+	}
+
+	var selected []evaluations.Eval
+	for _, eval := range evals {
+		switch {
+		case exclude != nil && exclude.MatchString(eval.Name):
+		case len(s.Tag) > 0 && !hasAnyTag(eval, s.Tag):
+		case hasAnyTag(eval, s.ExcludeTag):
+		case expr != nil && !expr(eval.Tags):
+		default:
+			selected = append(selected, eval)
+		}
+	}
+	return selected, nil
+}
+
+func hasAnyTag(eval evaluations.Eval, tags []string) bool {
+	for _, tag := range tags {
+		if slices.Contains(eval.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagExpr reports whether a set of eval tags satisfies a selection expression
+type tagExpr func(tags []string) bool
+
+// parseTagExpr parses a selection expression such as "(smoke || regression) && !slow".
+// ! binds tightest, then &&, then ||. Tags use the characters LoadConfig accepts.
+func parseTagExpr(input string) (tagExpr, error) {
+	p := &tagExprParser{input: input}
+	p.next()
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.token != "" {
+		return nil, p.unexpected()
+	}
+	return expr, nil
+}
+
+type tagExprParser struct {
+	input string
+	pos   int    // byte offset of the next token
+	start int    // byte offset of the current token
+	token string // current token, empty at the end of the input
+}
+
+// next advances to the next token: an operator, a parenthesis or a tag
+func (p *tagExprParser) next() {
+	for p.pos < len(p.input) {
+		r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.pos += size
+	}
+	p.start = p.pos
+
+	rest := p.input[p.pos:]
+	switch {
+	case rest == "":
+		p.token = ""
+	case strings.HasPrefix(rest, "&&"), strings.HasPrefix(rest, "||"):
+		p.token = rest[:2]
+	case !isTagChar(rest[0]):
+		_, size := utf8.DecodeRuneInString(rest)
+		p.token = rest[:size]
+	default:
+		end := 1
+		for end < len(rest) && isTagChar(rest[end]) {
+			end++
+		}
+		p.token = rest[:end]
+	}
+	p.pos += len(p.token)
+}
+
+func (p *tagExprParser) unexpected() error {
+	if p.token == "" {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected '%s' at position %d", p.token, utf8.RuneCountInString(p.input[:p.start])+1)
+}
+
+func (p *tagExprParser) parseOr() (tagExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.token == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		left = func(tags []string) bool { return a(tags) || b(tags) }
+	}
+	return left, nil
+}
+
+func (p *tagExprParser) parseAnd() (tagExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.token == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		left = func(tags []string) bool { return a(tags) && b(tags) }
+	}
+	return left, nil
+}
+
+func (p *tagExprParser) parseNot() (tagExpr, error) {
+	switch {
+	case p.token == "!":
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(tags []string) bool { return !inner(tags) }, nil
+	case p.token == "(":
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.token != ")" {
+			return nil, p.unexpected()
+		}
+		p.next()
+		return inner, nil
+	case p.token != "" && isTagChar(p.token[0]):
+		tag := p.token
+		p.next()
+		return func(tags []string) bool { return slices.Contains(tags, tag) }, nil
+	default:
+		return nil, p.unexpected()
+	}
+}
+
+func isTagChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_.:/-", c) >= 0
+}
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	evaluations "github.com/wolfeidau/mcp-evals"
+)
+
+func TestEvalSelection_Apply(t *testing.T) {
+	evals := []evaluations.Eval{
+		{Name: "auth_basic", Tags: []string{"smoke", "auth"}},
+		{Name: "auth_token", Tags: []string{"auth", "slow"}},
+		{Name: "user_create", Tags: []string{"smoke"}},
+		{Name: "report_monthly", Tags: []string{"regression", "slow", "expensive"}},
+		{Name: "untagged"},
+	}
+
+	tests := []struct {
+		name      string
+		selection EvalSelection
+		expected  []string
+		wantErr   string
+	}{
+		{
+			name:      "no flags",
+			selection: EvalSelection{},
+			expected:  []string{"auth_basic", "auth_token", "user_create", "report_monthly", "untagged"},
+		},
+		{
+			name:      "any of the tags",
+			selection: EvalSelection{Tag: []string{"smoke", "regression"}},
+			expected:  []string{"auth_basic", "user_create", "report_monthly"},
+		},
+		{
+			name:      "exclude tag",
+			selection: EvalSelection{ExcludeTag: []string{"slow"}},
+			expected:  []string{"auth_basic", "user_create", "untagged"},
+		},
+		{
+			name:      "exclude name",
+			selection: EvalSelection{Exclude: "^auth_"},
+			expected:  []string{"user_create", "report_monthly", "untagged"},
+		},
+		{
+			name:      "filter and exclude tag",
+			selection: EvalSelection{Filter: "auth", ExcludeTag: []string{"slow"}},
+			expected:  []string{"auth_basic"},
+		},
+		{
+			name:      "expression",
+			selection: EvalSelection{Select: "smoke && !auth"},
+			expected:  []string{"user_create"},
+		},
+		{
+			name:      "expression with parentheses",
+			selection: EvalSelection{Select: "(auth || regression) && slow && !expensive"},
+			expected:  []string{"auth_token"},
+		},
+		{
+			name:      "negated expression keeps untagged evals",
+			selection: EvalSelection{Select: "!slow"},
+			expected:  []string{"auth_basic", "user_create", "untagged"},
+		},
+		{
+			name:      "no matches",
+			selection: EvalSelection{Tag: []string{"nightly"}},
+			expected:  nil,
+		},
+		{
+			name:      "invalid filter",
+			selection: EvalSelection{Filter: "[invalid"},
+			wantErr:   "invalid filter pattern",
+		},
+		{
+			name:      "invalid exclude",
+			selection: EvalSelection{Exclude: "(unclosed"},
+			wantErr:   "invalid exclude pattern",
+		},
+		{
+			name:      "invalid expression",
+			selection: EvalSelection{Select: "smoke slow"},
+			wantErr:   "invalid select expression: unexpected 'slow' at position 7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			result, err := tt.selection.Apply(evals)
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+
+			var names []string
+			for _, eval := range result {
+				names = append(names, eval.Name)
+			}
+			assert.Equal(tt.expected, names)
+		})
+	}
+}
+
+func TestParseTagExpr(t *testing.T) {
+	tests := []struct {
+		expr    string
+		tags    []string
+		want    bool
+		wantErr string
+	}{
+		{expr: "smoke", tags: []string{"smoke"}, want: true},
+		{expr: "team:payments", tags: []string{"team:payments"}, want: true},
+		{expr: "!smoke", tags: []string{"smoke"}, want: false},
+		{expr: "!!smoke", tags: []string{"smoke"}, want: true},
+		{expr: "a || b && c", tags: []string{"a"}, want: true},
+		{expr: "(a || b) && c", tags: []string{"a"}, want: false},
+		{expr: "a&&!b", tags: []string{"a", "b"}, want: false},
+		{expr: "\tsmoke &&\n\t!slow\r\n", tags: []string{"smoke"}, want: true},
+		{expr: "smoke\u00a0||\u3000slow", tags: []string{"slow"}, want: true},
+		{expr: "", wantErr: "unexpected end of expression"},
+		{expr: "smoke &&", wantErr: "unexpected end of expression"},
+		{expr: "(smoke", wantErr: "unexpected end of expression"},
+		{expr: "smoke)", wantErr: "unexpected ')' at position 6"},
+		{expr: "smoke & slow", wantErr: "unexpected '&' at position 7"},
+		{expr: "smoke && é", wantErr: "unexpected 'é' at position 10"},
+		{expr: "smoke\u3000&& slow)", wantErr: "unexpected ')' at position 14"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			assert := require.New(t)
+
+			expr, err := parseTagExpr(tt.expr)
+			if tt.wantErr != "" {
+				assert.EqualError(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, expr(tt.tags))
+		})
+	}
+}
+
+func TestEvalSelection_String(t *testing.T) {
+	selection := EvalSelection{Filter: "auth", Tag: []string{"smoke", "fast"}, ExcludeTag: []string{"slow"}, Select: "!flaky"}
+	require.Equal(t, "filter 'auth', tag smoke, fast, exclude tag slow, select '!flaky'", selection.String())
+	require.False(t, EvalSelection{}.IsSet())
+}
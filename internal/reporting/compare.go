@@ -0,0 +1,456 @@
+package reporting
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/lipgloss/v2/table"
+	"github.com/rs/zerolog/log"
+	evaluations "github.com/wolfeidau/mcp-evals"
+	"github.com/wolfeidau/mcp-evals/internal/help"
+)
+
+// ChangeKind describes how an eval's outcome changed between two runs
+type ChangeKind string
+
+const (
+	ChangeUnchanged ChangeKind = "unchanged" // Passed or failed in both runs
+	ChangeRegressed ChangeKind = "regressed" // Passed in the baseline and no longer passes
+	ChangeFixed     ChangeKind = "fixed"     // Did not pass in the baseline and passes now
+	ChangeAdded     ChangeKind = "added"     // Only in the candidate
+	ChangeRemoved   ChangeKind = "removed"   // Only in the baseline
+)
+
+// EvalMetrics are the comparable measurements of an eval. For evals with several
+// trials the scores are the trial means and the usage is averaged per trial.
+type EvalMetrics struct {
+	Status       string        // Result status, see StatusPass and friends
+	Graded       bool          // Whether the scores are set
+	Accuracy     float64       // Accuracy score
+	Completeness float64       // Completeness score
+	Relevance    float64       // Relevance score
+	Clarity      float64       // Clarity score
+	Reasoning    float64       // Reasoning score
+	Average      float64       // Average of the five dimensions
+	Tokens       float64       // Agent input and output tokens
+	Duration     time.Duration // Execution time
+	ToolCalls    float64       // Tool calls made
+}
+
+// EvalComparison pairs the baseline and candidate metrics of an eval
+type EvalComparison struct {
+	Name      string
+	Baseline  *EvalMetrics // nil when the eval was added
+	Candidate *EvalMetrics // nil when the eval was removed
+	Change    ChangeKind
+}
+
+// Comparison is the result of comparing two runs, with evals in name order
+type Comparison struct {
+	Evals []EvalComparison
+}
+
+// CompareThresholds sets how far a candidate may fall behind its baseline before the
+// comparison counts as a regression. A negative value disables the check.
+type CompareThresholds struct {
+	MaxScoreDrop        float64 // Largest allowed drop in an eval's average score
+	MaxDimensionDrop    float64 // Largest allowed drop in any single grading dimension
+	MaxNewFailures      int     // Number of evals allowed to go from passing to failing
+	MaxTokenIncrease    float64 // Largest allowed increase in an eval's tokens, in percent
+	MaxDurationIncrease float64 // Largest allowed increase in an eval's duration, in percent
+}
+
+// LoadTraceDir loads every trace file in dir written by run --trace-dir. Other JSON
+// files, such as a results.json or cassette kept alongside them, are skipped.
+func LoadTraceDir(dir string) ([]evaluations.EvalRunResult, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("no trace files found in %s", dir)
+	}
+
+	results := make([]evaluations.EvalRunResult, 0, len(paths))
+	for _, path := range paths {
+		result, err := LoadTraceFile(path)
+		if errors.Is(err, ErrNotTraceFile) {
+			log.Warn().Str("file", path).Msg("Skipping JSON file that isn't a trace file")
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load trace file %s: %w", filepath.Base(path), err)
+		}
+		results = append(results, result)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no trace files found in %s", dir)
+	}
+	return results, nil
+}
+
+// CompareResults matches the evals of two runs by name
+func CompareResults(baseline, candidate []evaluations.EvalRunResult) Comparison {
+	byName := make(map[string]*EvalComparison)
+	var names []string
+	entry := func(name string) *EvalComparison {
+		if c, ok := byName[name]; ok {
+			return c
+		}
+		c := &EvalComparison{Name: name}
+		byName[name] = c
+		names = append(names, name)
+		return c
+	}
+
+	for _, result := range baseline {
+		m := NewEvalMetrics(result)
+		entry(result.Eval.Name).Baseline = &m
+	}
+	for _, result := range candidate {
+		m := NewEvalMetrics(result)
+		entry(result.Eval.Name).Candidate = &m
+	}
+
+	sort.Strings(names)
+	comparison := Comparison{Evals: make([]EvalComparison, 0, len(names))}
+	for _, name := range names {
+		c := byName[name]
+		c.Change = changeKind(c.Baseline, c.Candidate)
+		comparison.Evals = append(comparison.Evals, *c)
+	}
+	return comparison
+}
+
+// NewEvalMetrics measures a result for comparison
+func NewEvalMetrics(result evaluations.EvalRunResult) EvalMetrics {
+	m := EvalMetrics{Status: resultStatus(result)}
+
+	if agg := result.Aggregate; agg != nil {
+		if scores := agg.Scores; scores != nil {
+			m.Graded = true
+			m.Accuracy = scores.Accuracy.Mean
+			m.Completeness = scores.Completeness.Mean
+			m.Relevance = scores.Relevance.Mean
+			m.Clarity = scores.Clarity.Mean
+			m.Reasoning = scores.Reasoning.Mean
+			m.Average = scores.Average.Mean
+		}
+
+		traced := 0
+		for _, trial := range result.Trials {
+			if trial.Trace == nil {
+				continue
+			}
+			traced++
+			m.Tokens += float64(trial.Trace.TotalInputTokens + trial.Trace.TotalOutputTokens)
+			m.Duration += trial.Trace.TotalDuration
+			m.ToolCalls += float64(trial.Trace.ToolCallCount)
+		}
+		if traced > 0 {
+			m.Tokens /= float64(traced)
+			m.Duration /= time.Duration(traced)
+			m.ToolCalls /= float64(traced)
+		}
+		return m
+	}
+
+	if grade := result.Grade; grade != nil {
+		m.Graded = true
+		m.Accuracy = float64(grade.Accuracy)
+		m.Completeness = float64(grade.Completeness)
+		m.Relevance = float64(grade.Relevance)
+		m.Clarity = float64(grade.Clarity)
+		m.Reasoning = float64(grade.Reasoning)
+		m.Average = grade.Average()
+	}
+	if trace := result.Trace; trace != nil {
+		m.Tokens = float64(trace.TotalInputTokens + trace.TotalOutputTokens)
+		m.Duration = trace.TotalDuration
+		m.ToolCalls = float64(trace.ToolCallCount)
+	}
+	return m
+}
+
+func changeKind(baseline, candidate *EvalMetrics) ChangeKind {
+	switch {
+	case baseline == nil:
+		return ChangeAdded
+	case candidate == nil:
+		return ChangeRemoved
+	case baseline.Status == StatusPass && candidate.Status != StatusPass:
+		return ChangeRegressed
+	case baseline.Status != StatusPass && candidate.Status == StatusPass:
+		return ChangeFixed
+	default:
+		return ChangeUnchanged
+	}
+}
+
+// Count returns the number of evals with the given change
+func (c Comparison) Count(kind ChangeKind) int {
+	n := 0
+	for _, e := range c.Evals {
+		if e.Change == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Regressions describes every way the candidate exceeds the thresholds, or nil if none
+func (c Comparison) Regressions(th CompareThresholds) []string {
+	var regressions []string
+
+	if th.MaxNewFailures >= 0 {
+		var failing []string
+		for _, e := range c.Evals {
+			if e.Change == ChangeRegressed {
+				failing = append(failing, e.Name)
+			}
+		}
+		if len(failing) > th.MaxNewFailures {
+			regressions = append(regressions, fmt.Sprintf("%d newly failing evals (allowed %d): %s",
+				len(failing), th.MaxNewFailures, strings.Join(failing, ", ")))
+		}
+	}
+
+	for _, e := range c.Evals {
+		base, cand := e.Baseline, e.Candidate
+		if base == nil || cand == nil {
+			continue
+		}
+
+		if base.Graded && cand.Graded {
+			if drop := base.Average - cand.Average; th.MaxScoreDrop >= 0 && exceeds(drop, th.MaxScoreDrop) {
+				regressions = append(regressions, fmt.Sprintf("%s: average score dropped %.2f (%.2f → %.2f), allowed %.2f",
+					e.Name, drop, base.Average, cand.Average, th.MaxScoreDrop))
+			}
+			if th.MaxDimensionDrop >= 0 {
+				for _, d := range dimensionDeltas(base, cand) {
+					if drop := -d.delta; exceeds(drop, th.MaxDimensionDrop) {
+						regressions = append(regressions, fmt.Sprintf("%s: %s dropped %.2f, allowed %.2f",
+							e.Name, strings.ToLower(d.name), drop, th.MaxDimensionDrop))
+					}
+				}
+			}
+		}
+
+		if increase, ok := percentChange(base.Tokens, cand.Tokens); ok && th.MaxTokenIncrease >= 0 && exceeds(increase, th.MaxTokenIncrease) {
+			regressions = append(regressions, fmt.Sprintf("%s: tokens increased %.0f%%, allowed %.0f%%",
+				e.Name, increase, th.MaxTokenIncrease))
+		}
+		if increase, ok := percentChange(float64(base.Duration), float64(cand.Duration)); ok && th.MaxDurationIncrease >= 0 && exceeds(increase, th.MaxDurationIncrease) {
+			regressions = append(regressions, fmt.Sprintf("%s: duration increased %.0f%%, allowed %.0f%%",
+				e.Name, increase, th.MaxDurationIncrease))
+		}
+	}
+
+	return regressions
+}
+
+// exceeds reports whether value is above limit, ignoring floating point noise
+func exceeds(value, limit float64) bool {
+	return value > limit+1e-9
+}
+
+// percentChange returns the change from base to cand in percent, ok is false when base is zero
+func percentChange(base, cand float64) (float64, bool) {
+	if base == 0 {
+		return 0, false
+	}
+	return (cand - base) / base * 100, true
+}
+
+type dimensionDelta struct {
+	name  string
+	delta float64
+}
+
+func dimensionDeltas(base, cand *EvalMetrics) []dimensionDelta {
+	return []dimensionDelta{
+		{"Accuracy", cand.Accuracy - base.Accuracy},
+		{"Completeness", cand.Completeness - base.Completeness},
+		{"Relevance", cand.Relevance - base.Relevance},
+		{"Clarity", cand.Clarity - base.Clarity},
+		{"Reasoning", cand.Reasoning - base.Reasoning},
+	}
+}
+
+// PrintComparison writes a styled comparison of two runs to w, followed by the regressions
+func PrintComparison(w io.Writer, c Comparison, regressions []string) error {
+	styles := help.DefaultStyles()
+
+	var content strings.Builder
+	content.WriteString(h1(styles, "Comparison"))
+	content.WriteString(captureComparisonTable(c, styles))
+
+	content.WriteString(h2(styles, "Changes"))
+	content.WriteString(fmt.Sprintf("Compared:       %d evals\n", len(c.Evals)))
+	content.WriteString(fmt.Sprintf("Newly failing:  %d\n", c.Count(ChangeRegressed)))
+	content.WriteString(fmt.Sprintf("Fixed:          %d\n", c.Count(ChangeFixed)))
+	content.WriteString(fmt.Sprintf("Added:          %d\n", c.Count(ChangeAdded)))
+	content.WriteString(fmt.Sprintf("Removed:        %d\n", c.Count(ChangeRemoved)))
+	content.WriteString("\n")
+
+	for _, section := range []struct {
+		kind  ChangeKind
+		title string
+		style lipgloss.Style
+	}{
+		{ChangeRegressed, "Newly Failing", styles.Error},
+		{ChangeFixed, "Fixed", styles.Success},
+		{ChangeAdded, "Added", styles.Muted},
+		{ChangeRemoved, "Removed", styles.Muted},
+	} {
+		if c.Count(section.kind) == 0 {
+			continue
+		}
+		content.WriteString(h3(styles, section.title))
+		for _, e := range c.Evals {
+			if e.Change == section.kind {
+				content.WriteString(section.style.Render("• "+e.Name) + " " + styles.Muted.Render(statusTransition(e)) + "\n")
+			}
+		}
+		content.WriteString("\n")
+	}
+
+	if len(regressions) > 0 {
+		content.WriteString(h2(styles, "Regressions"))
+		for _, r := range regressions {
+			content.WriteString(styles.Error.Render("✗ "+r) + "\n")
+		}
+		content.WriteString("\n")
+	} else {
+		content.WriteString(styles.Success.Render("✓ No regressions beyond the thresholds") + "\n")
+	}
+
+	marginStyle := lipgloss.NewStyle().
+		MarginTop(1).
+		MarginBottom(1)
+
+	_, err := fmt.Fprintln(w, marginStyle.Render(content.String()))
+	return err
+}
+
+func captureComparisonTable(c Comparison, styles help.Styles) string {
+	rows := make([][]string, 0, len(c.Evals))
+	for _, e := range c.Evals {
+		rows = append(rows, buildComparisonRow(e, styles))
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(styles.Heading).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return lipgloss.NewStyle().
+					Bold(true).
+					Foreground(styles.Heading.GetForeground()).
+					Align(lipgloss.Left).Padding(0, 1)
+			}
+			return lipgloss.NewStyle().Align(lipgloss.Left).Padding(0, 1)
+		}).
+		Headers("Name", "Status", "Avg", "Acc", "Comp", "Rel", "Clar", "Reas", "Tokens", "Duration", "Tools").
+		Rows(rows...)
+
+	return t.String() + "\n\n"
+}
+
+func buildComparisonRow(e EvalComparison, styles help.Styles) []string {
+	name := e.Name
+	if len(name) > 25 {
+		name = name[:22] + "..."
+	}
+
+	status := statusTransition(e)
+	switch e.Change {
+	case ChangeRegressed:
+		status = styles.Error.Render(status)
+	case ChangeFixed:
+		status = styles.Success.Render(status)
+	case ChangeAdded, ChangeRemoved:
+		status = styles.Muted.Render(status)
+	}
+
+	row := []string{name, status, "-", "-", "-", "-", "-", "-", "-", "-", "-"}
+	base, cand := e.Baseline, e.Candidate
+	if base == nil || cand == nil {
+		return row
+	}
+
+	if base.Graded && cand.Graded {
+		row[2] = fmt.Sprintf("%.1f %s", cand.Average, formatDelta(cand.Average-base.Average, styles))
+		for i, d := range dimensionDeltas(base, cand) {
+			row[3+i] = formatDelta(d.delta, styles)
+		}
+	}
+	row[8] = formatPercentDelta(base.Tokens, cand.Tokens, styles)
+	row[9] = formatPercentDelta(float64(base.Duration), float64(cand.Duration), styles)
+	row[10] = formatCountDelta(cand.ToolCalls - base.ToolCalls)
+
+	return row
+}
+
+// statusTransition describes the status of an eval in each run, e.g. "PASS → FAIL"
+func statusTransition(e EvalComparison) string {
+	switch {
+	case e.Baseline == nil:
+		return "new: " + statusLabel(e.Candidate.Status)
+	case e.Candidate == nil:
+		return "was: " + statusLabel(e.Baseline.Status)
+	case e.Baseline.Status == e.Candidate.Status:
+		return statusLabel(e.Candidate.Status)
+	default:
+		return statusLabel(e.Baseline.Status) + " → " + statusLabel(e.Candidate.Status)
+	}
+}
+
+// formatDelta formats a score change, green when it rose and red when it fell
+func formatDelta(delta float64, styles help.Styles) string {
+	switch {
+	case math.Abs(delta) < 0.05:
+		return styles.Muted.Render("±0")
+	case delta > 0:
+		return styles.Success.Render(fmt.Sprintf("+%.1f", delta))
+	default:
+		return styles.Error.Render(fmt.Sprintf("%.1f", delta))
+	}
+}
+
+// formatPercentDelta formats the change in a cost such as tokens or duration, where a rise is worse
+func formatPercentDelta(base, cand float64, styles help.Styles) string {
+	change, ok := percentChange(base, cand)
+	switch {
+	case !ok:
+		return "-"
+	case math.Abs(change) < 0.5:
+		return styles.Muted.Render("±0%")
+	case change > 0:
+		return styles.Error.Render(fmt.Sprintf("+%.0f%%", change))
+	default:
+		return styles.Success.Render(fmt.Sprintf("%.0f%%", change))
+	}
+}
+
+func formatCountDelta(delta float64) string {
+	switch {
+	case math.Abs(delta) < 0.05:
+		return "±0"
+	case delta == math.Trunc(delta):
+		return fmt.Sprintf("%+.0f", delta)
+	default:
+		return fmt.Sprintf("%+.1f", delta)
+	}
+}
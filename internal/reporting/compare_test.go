@@ -0,0 +1,205 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	evaluations "github.com/wolfeidau/mcp-evals"
+)
+
+// compareResult builds a graded result with the same score in every dimension
+func compareResult(name string, score int, tokens int, duration time.Duration) evaluations.EvalRunResult {
+	return evaluations.EvalRunResult{
+		Eval:  evaluations.Eval{Name: name},
+		Grade: &evaluations.GradeResult{Accuracy: score, Completeness: score, Relevance: score, Clarity: score, Reasoning: score},
+		Trace: &evaluations.EvalTrace{TotalInputTokens: tokens, TotalDuration: duration, ToolCallCount: 2},
+	}
+}
+
+func TestCompareResults(t *testing.T) {
+	assert := require.New(t)
+
+	baseline := []evaluations.EvalRunResult{
+		compareResult("stable", 4, 1000, time.Second),
+		compareResult("regressed", 4, 1000, time.Second),
+		compareResult("fixed", 2, 1000, time.Second),
+		compareResult("removed", 4, 1000, time.Second),
+	}
+	candidate := []evaluations.EvalRunResult{
+		compareResult("stable", 4, 1500, 3*time.Second),
+		compareResult("regressed", 2, 1000, time.Second),
+		compareResult("fixed", 5, 1000, time.Second),
+		{Eval: evaluations.Eval{Name: "added"}, Error: errors.New("boom")},
+	}
+
+	comparison := CompareResults(baseline, candidate)
+	assert.Len(comparison.Evals, 5)
+
+	changes := make(map[string]ChangeKind, len(comparison.Evals))
+	for _, e := range comparison.Evals {
+		changes[e.Name] = e.Change
+	}
+	assert.Equal(map[string]ChangeKind{
+		"added":     ChangeAdded,
+		"fixed":     ChangeFixed,
+		"regressed": ChangeRegressed,
+		"removed":   ChangeRemoved,
+		"stable":    ChangeUnchanged,
+	}, changes)
+	assert.Equal("added", comparison.Evals[0].Name)
+	assert.Nil(comparison.Evals[0].Baseline)
+	assert.Equal(StatusError, comparison.Evals[0].Candidate.Status)
+	assert.Equal(1, comparison.Count(ChangeRegressed))
+
+	tests := []struct {
+		name       string
+		thresholds CompareThresholds
+		want       []string
+	}{
+		{
+			name:       "defaults",
+			thresholds: CompareThresholds{MaxScoreDrop: 0.5, MaxDimensionDrop: 1, MaxNewFailures: 0, MaxTokenIncrease: -1, MaxDurationIncrease: -1},
+			want: []string{
+				"1 newly failing evals (allowed 0): regressed",
+				"regressed: average score dropped 2.00 (4.00 → 2.00), allowed 0.50",
+				"regressed: accuracy dropped 2.00, allowed 1.00",
+				"regressed: completeness dropped 2.00, allowed 1.00",
+				"regressed: relevance dropped 2.00, allowed 1.00",
+				"regressed: clarity dropped 2.00, allowed 1.00",
+				"regressed: reasoning dropped 2.00, allowed 1.00",
+			},
+		},
+		{
+			name:       "usage",
+			thresholds: CompareThresholds{MaxScoreDrop: -1, MaxDimensionDrop: -1, MaxNewFailures: 1, MaxTokenIncrease: 25, MaxDurationIncrease: 200},
+			want:       []string{"stable: tokens increased 50%, allowed 25%"},
+		},
+		{
+			name:       "disabled",
+			thresholds: CompareThresholds{MaxScoreDrop: -1, MaxDimensionDrop: -1, MaxNewFailures: -1, MaxTokenIncrease: -1, MaxDurationIncrease: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			got := comparison.Regressions(tt.thresholds)
+			assert.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestNewEvalMetrics_Trials(t *testing.T) {
+	assert := require.New(t)
+
+	result, err := LoadTraceFile(filepath.Join("testdata", "flaky-search.json"))
+	assert.NoError(err)
+
+	m := NewEvalMetrics(result)
+	assert.Equal(StatusPass, m.Status)
+	assert.True(m.Graded)
+	assert.InDelta(result.Aggregate.Scores.Average.Mean, m.Average, 1e-9)
+
+	var tokens float64
+	for _, trial := range result.Trials {
+		tokens += float64(trial.Trace.TotalInputTokens + trial.Trace.TotalOutputTokens)
+	}
+	assert.InDelta(tokens/float64(len(result.Trials)), m.Tokens, 1e-9)
+}
+
+func TestLoadTraceDir(t *testing.T) {
+	assert := require.New(t)
+
+	results, err := LoadTraceDir("testdata")
+	assert.NoError(err)
+	assert.Len(results, 6)
+
+	_, err = LoadTraceDir(t.TempDir())
+	assert.ErrorContains(err, "no trace files found")
+
+	_, err = LoadTraceDir(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(err, os.ErrNotExist)
+
+	// The results document and a cassette written next to the traces aren't loaded as evals
+	dir := t.TempDir()
+	trace, err := os.ReadFile(filepath.Join("testdata", "simple-echo-test.json"))
+	assert.NoError(err)
+	assert.NoError(os.WriteFile(filepath.Join(dir, "simple-echo-test.json"), trace, 0o600))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "results.json"), []byte(`{"version":1,"run":{},"summary":{},"evals":[]}`), 0o600))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "cassette.json"), []byte(`{"version":1,"interactions":[]}`), 0o600))
+
+	results, err = LoadTraceDir(dir)
+	assert.NoError(err)
+	assert.Len(results, 1)
+	assert.Equal("simple-echo-test", results[0].Eval.Name)
+
+	_, err = LoadTraceFile(filepath.Join(dir, "results.json"))
+	assert.ErrorIs(err, ErrNotTraceFile)
+
+	assert.NoError(os.Remove(filepath.Join(dir, "simple-echo-test.json")))
+	_, err = LoadTraceDir(dir)
+	assert.ErrorContains(err, "no trace files found")
+}
+
+func TestPrintComparison(t *testing.T) {
+	assert := require.New(t)
+
+	comparison := CompareResults(
+		[]evaluations.EvalRunResult{compareResult("search", 4, 1000, time.Second)},
+		[]evaluations.EvalRunResult{compareResult("search", 2, 1200, time.Second)},
+	)
+
+	var buf bytes.Buffer
+	assert.NoError(PrintComparison(&buf, comparison, []string{"search: average score dropped 2.00"}))
+
+	out := stripANSI(buf.String())
+	assert.Contains(out, "# Comparison")
+	assert.Contains(out, "PASS → FAIL")
+	assert.Contains(out, "2.0 -2.0")
+	assert.Contains(out, "+20%")
+	assert.Contains(out, "### Newly Failing")
+	assert.Contains(out, "✗ search: average score dropped 2.00")
+}
+
+func TestCompareResults_MinimumScoreRegression(t *testing.T) {
+	assert := require.New(t)
+
+	rubric := &evaluations.GradingRubric{MinimumScores: map[string]int{"accuracy": 4}}
+	writeTrace := func(dir string, grade *evaluations.GradeResult) {
+		result := evaluations.EvalRunResult{
+			Eval:  evaluations.Eval{Name: "rubric", GradingRubric: rubric},
+			Grade: grade,
+			Error: rubric.CheckMinimumScores(grade),
+			Trace: &evaluations.EvalTrace{StepCount: 1},
+		}
+		data, err := json.Marshal(result)
+		assert.NoError(err)
+		assert.NoError(os.WriteFile(filepath.Join(dir, "rubric.json"), data, 0o600))
+	}
+
+	// The candidate's average stays above the passing score while accuracy drops below its minimum
+	baselineDir, candidateDir := t.TempDir(), t.TempDir()
+	writeTrace(baselineDir, &evaluations.GradeResult{Accuracy: 4, Completeness: 4, Relevance: 4, Clarity: 4, Reasoning: 4})
+	writeTrace(candidateDir, &evaluations.GradeResult{Accuracy: 3, Completeness: 5, Relevance: 5, Clarity: 5, Reasoning: 5})
+
+	baseline, err := LoadTraceDir(baselineDir)
+	assert.NoError(err)
+	candidate, err := LoadTraceDir(candidateDir)
+	assert.NoError(err)
+
+	comparison := CompareResults(baseline, candidate)
+	assert.Len(comparison.Evals, 1)
+	assert.Equal(StatusPass, comparison.Evals[0].Baseline.Status)
+	assert.Equal(StatusFail, comparison.Evals[0].Candidate.Status)
+	assert.Equal(ChangeRegressed, comparison.Evals[0].Change)
+
+	regressions := comparison.Regressions(CompareThresholds{MaxScoreDrop: -1, MaxDimensionDrop: -1, MaxNewFailures: 0, MaxTokenIncrease: -1, MaxDurationIncrease: -1})
+	assert.Equal([]string{"1 newly failing evals (allowed 0): rubric"}, regressions)
+}
@@ -0,0 +1,121 @@
+package reporting
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+)
+
+//go:embed templates/report.html.tmpl
+var templateFS embed.FS
+
+var htmlTemplate = template.Must(template.New("report.html.tmpl").Funcs(template.FuncMap{
+	"duration":   formatDuration,
+	"tokens":     formatTokensWithCache,
+	"passK":      formatPassK,
+	"prettyJSON": prettyJSON,
+	"icon":       statusIcon,
+	"passIcon":   passIcon,
+	"trajectory": formatTrajectory,
+	"avg":        (*evaluations.GradeResult).Average,
+}).ParseFS(templateFS, "templates/report.html.tmpl"))
+
+// HTMLRenderer renders a single-file HTML report with embedded CSS. Every eval, step,
+// tool call and grading prompt is a collapsible section so the full traces can be
+// shared without overwhelming the summary.
+type HTMLRenderer struct{}
+
+// htmlReport is the data passed to the HTML template
+type htmlReport struct {
+	GeneratedAt string
+	Summary     ResultsSummary
+	Duration    string
+	Tokens      string
+	Cost        string // Empty when the traces carry no cost
+	Evals       []htmlEval
+}
+
+// htmlEval is a single eval, or a trial of one, prepared for the HTML template
+type htmlEval struct {
+	ID       string // Anchor linking the summary table to the details
+	Name     string
+	Status   string
+	Headline string
+	Message  string
+	Row      []string
+	Response string
+	Cost     string
+	Result   evaluations.EvalRunResult
+	Trials   []htmlEval
+}
+
+// Render writes the HTML report to w
+func (HTMLRenderer) Render(w io.Writer, results []evaluations.EvalRunResult) error {
+	summary := NewResults(RunInfo{}, results).Summary
+
+	report := htmlReport{
+		GeneratedAt: time.Now().UTC().Format(time.RFC1123),
+		Summary:     summary,
+		Duration:    formatDuration(time.Duration(summary.DurationMS) * time.Millisecond),
+		Tokens:      formatTokenCounts(summary.Usage.InputTokens, summary.Usage.OutputTokens),
+		Cost:        formatCostBreakdown(summary.Cost),
+		Evals:       make([]htmlEval, 0, len(results)),
+	}
+	for i, result := range results {
+		e := newHTMLEval(result.Eval.Name, result)
+		e.ID = fmt.Sprintf("eval-%d", i+1)
+		report.Evals = append(report.Evals, e)
+	}
+
+	// Render to a buffer so a template error doesn't leave a partial file behind
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, report); err != nil {
+		return fmt.Errorf("failed to render html report: %w", err)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func newHTMLEval(name string, result evaluations.EvalRunResult) htmlEval {
+	status := resultStatus(result)
+
+	e := htmlEval{
+		Name:     name,
+		Status:   status,
+		Headline: statusLabel(status),
+		Row:      plainResultRow(result),
+		Response: finalResponse(result),
+		Cost:     formatCostBreakdown(resultCost(result)),
+		Result:   result,
+	}
+	if result.Grade != nil {
+		e.Headline += fmt.Sprintf(" %.1f/5", result.Grade.Average())
+	}
+	if status != StatusPass && status != StatusNoGrade {
+		e.Message = failureMessage(result)
+	}
+
+	for i, trial := range result.Trials {
+		e.Trials = append(e.Trials, newHTMLEval(fmt.Sprintf("Trial %d", i+1), trial))
+	}
+
+	return e
+}
+
+// prettyJSON indents raw JSON for display, returning it unchanged when it isn't valid
+func prettyJSON(data json.RawMessage) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
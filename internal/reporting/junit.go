@@ -0,0 +1,222 @@
+package reporting
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Time       string           `xml:"time,attr"`
+	Timestamp  string           `xml:"timestamp,attr,omitempty"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	TestCases  []junitTestCase  `xml:"testcase"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitProblem is the body of a failure or error element
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results as a JUnit XML report with one testcase per eval.
+// Failed evals carry a failure element and evals that errored or timed out an error
+// element; the grade and its comments are written to system-out.
+func WriteJUnit(w io.Writer, info RunInfo, results []evaluations.EvalRunResult) error {
+	suite := junitTestSuite{
+		Name:       junitSuiteName(info),
+		Tests:      len(results),
+		Properties: newJUnitProperties(info),
+	}
+	if !info.StartedAt.IsZero() {
+		suite.Timestamp = info.StartedAt.Format(time.RFC3339)
+	}
+
+	var total time.Duration
+	for _, result := range results {
+		duration := resultDuration(result)
+		total += duration
+
+		testCase := junitTestCase{
+			Name:      result.Eval.Name,
+			ClassName: suite.Name,
+			Time:      junitSeconds(duration),
+			SystemOut: gradeSummary(result),
+		}
+
+		switch status := resultStatus(result); status {
+		case StatusFail:
+			suite.Failures++
+			testCase.Failure = &junitProblem{Message: failureMessage(result), Type: status, Text: result.Eval.Description}
+		case StatusError, StatusTimeout, StatusBudgetExceeded:
+			suite.Errors++
+			testCase.Error = &junitProblem{Message: failureMessage(result), Type: status, Text: result.Eval.Description}
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = junitSeconds(total)
+
+	doc := junitTestSuites{
+		Name:     "mcp-evals",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode junit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitSuiteName names the suite after the config file, falling back to the tool name
+// when the results were loaded from trace files
+func junitSuiteName(info RunInfo) string {
+	if info.ConfigPath == "" {
+		return "mcp-evals"
+	}
+	return strings.TrimSuffix(filepath.Base(info.ConfigPath), filepath.Ext(info.ConfigPath))
+}
+
+// newJUnitProperties records the run info as suite properties, or nil when there is none
+func newJUnitProperties(info RunInfo) *junitProperties {
+	var props []junitProperty
+	add := func(name, value string) {
+		if value != "" {
+			props = append(props, junitProperty{Name: name, Value: value})
+		}
+	}
+	add("provider", info.Provider)
+	add("model", info.Model)
+	add("grader_provider", info.GraderProvider)
+	add("grading_model", info.GradingModel)
+	add("mcp_transport", info.MCPTransport)
+	add("mcp_server", info.MCPServer)
+	add("filter", info.Filter)
+	add("exclude", info.Exclude)
+	add("tags", strings.Join(info.Tags, ","))
+	add("exclude_tags", strings.Join(info.ExcludeTags, ","))
+	add("select", info.Select)
+	if info.Trials > 0 {
+		add("trials", fmt.Sprint(info.Trials))
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	return &junitProperties{Properties: props}
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// resultDuration is the time spent running an eval, summed across its trials
+func resultDuration(result evaluations.EvalRunResult) time.Duration {
+	if result.Trace != nil {
+		return result.Trace.TotalDuration
+	}
+	var total time.Duration
+	for _, trial := range result.Trials {
+		total += resultDuration(trial)
+	}
+	return total
+}
+
+// failureMessage explains why an eval did not pass. The eval error already holds
+// minimum score, assertion and trial policy failures; evals that failed on their
+// average grade alone are described from the grade and rubric.
+func failureMessage(result evaluations.EvalRunResult) string {
+	if result.Error != nil {
+		return result.Error.Error()
+	}
+	if result.Grade == nil {
+		return ""
+	}
+
+	msg := fmt.Sprintf("average score %.1f is below %.1f", result.Grade.Average(), evaluations.PassingScore)
+	if err := result.Eval.GradingRubric.CheckMinimumScores(result.Grade); err != nil {
+		msg += "; " + err.Error()
+	}
+	return msg
+}
+
+// gradeSummary describes the grade of an eval, or of each of its trials, as plain text
+func gradeSummary(result evaluations.EvalRunResult) string {
+	var b strings.Builder
+
+	if agg := result.Aggregate; agg != nil {
+		fmt.Fprintf(&b, "%d of %d trials passed (rule: %s)\n", agg.Passes, agg.Trials, agg.Policy.Rule)
+		for i, trial := range result.Trials {
+			fmt.Fprintf(&b, "\nTrial %d: %s\n", i+1, resultStatus(trial))
+			writeGrade(&b, trial)
+		}
+		return b.String()
+	}
+
+	writeGrade(&b, result)
+	return b.String()
+}
+
+func writeGrade(b *strings.Builder, result evaluations.EvalRunResult) {
+	grade := result.Grade
+	if grade == nil {
+		if result.Error != nil {
+			fmt.Fprintf(b, "Error: %s\n", result.Error)
+		}
+		return
+	}
+
+	fmt.Fprintf(b, "Accuracy: %d, Completeness: %d, Relevance: %d, Clarity: %d, Reasoning: %d, Average: %.1f\n",
+		grade.Accuracy, grade.Completeness, grade.Relevance, grade.Clarity, grade.Reasoning, grade.Average())
+	if grade.OverallComment != "" {
+		fmt.Fprintf(b, "%s\n", grade.OverallComment)
+	}
+}
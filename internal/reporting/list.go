@@ -0,0 +1,120 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/lipgloss/v2/table"
+	evaluations "github.com/wolfeidau/mcp-evals"
+	"github.com/wolfeidau/mcp-evals/internal/help"
+)
+
+// PrintEvalList writes a styled table of the evals selected from config to w, with
+// their tags and the settings they run with once the config defaults are applied
+func PrintEvalList(w io.Writer, config *evaluations.EvalConfig, evals []evaluations.Eval) error {
+	styles := help.DefaultStyles()
+	info := NewRunInfo("", config)
+
+	var content strings.Builder
+	content.WriteString(h1(styles, "Evals"))
+	content.WriteString(fmt.Sprintf("Model:          %s (%s)\n", info.Model, info.Provider))
+	content.WriteString(fmt.Sprintf("Grading Model:  %s (%s)\n", info.GradingModel, info.GraderProvider))
+	content.WriteString(fmt.Sprintf("MCP Server:     %s (%s)\n", info.MCPServer, info.MCPTransport))
+	content.WriteString(fmt.Sprintf("Selected:       %d of %d evals\n\n", len(evals), len(config.Evals)))
+
+	if len(evals) > 0 {
+		content.WriteString(captureEvalListTable(config, evals, styles))
+	}
+
+	marginStyle := lipgloss.NewStyle().
+		MarginTop(1).
+		MarginBottom(1)
+
+	_, err := fmt.Fprintln(w, marginStyle.Render(content.String()))
+	return err
+}
+
+func captureEvalListTable(config *evaluations.EvalConfig, evals []evaluations.Eval, styles help.Styles) string {
+	rows := make([][]string, 0, len(evals))
+	for _, eval := range evals {
+		rows = append(rows, buildEvalListRow(config, eval))
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(styles.Heading).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return lipgloss.NewStyle().
+					Bold(true).
+					Foreground(styles.Heading.GetForeground()).
+					Align(lipgloss.Left).Padding(0, 1)
+			}
+			return lipgloss.NewStyle().Align(lipgloss.Left).Padding(0, 1)
+		}).
+		Headers("Name", "Tags", "Trials", "Timeout", "Thinking", "Turns", "Budget").
+		Rows(rows...)
+
+	return t.String() + "\n\n"
+}
+
+func buildEvalListRow(config *evaluations.EvalConfig, eval evaluations.Eval) []string {
+	row := []string{eval.Name, "-", "1", "-", "-", "1", "-"}
+
+	if len(eval.Tags) > 0 {
+		row[1] = strings.Join(eval.Tags, ", ")
+	}
+
+	switch {
+	case eval.Trials > 0:
+		row[2] = fmt.Sprint(eval.Trials)
+	case config.Trials > 0:
+		row[2] = fmt.Sprint(config.Trials)
+	}
+
+	switch {
+	case eval.Timeout != "":
+		row[3] = eval.Timeout
+	case config.Timeout != "":
+		row[3] = config.Timeout
+	}
+
+	thinking := config.Thinking
+	if eval.Thinking != nil {
+		thinking = eval.Thinking
+	}
+	if thinking != nil && thinking.BudgetTokens > 0 {
+		row[4] = fmt.Sprintf("%d tokens", thinking.BudgetTokens)
+	}
+
+	row[5] = fmt.Sprint(1 + len(eval.Turns))
+	if eval.SimulatedUser != nil {
+		row[5] += " + simulated"
+	}
+
+	if limits := formatBudgetLimits(eval.Budget); limits != "" {
+		row[6] = limits
+	}
+
+	return row
+}
+
+// formatBudgetLimits describes the limits set in a budget, e.g. "20000 tokens, $0.50, 5m"
+func formatBudgetLimits(budget *evaluations.Budget) string {
+	if budget == nil {
+		return ""
+	}
+	var limits []string
+	if budget.MaxTokens > 0 {
+		limits = append(limits, fmt.Sprintf("%d tokens", budget.MaxTokens))
+	}
+	if budget.MaxCostUSD > 0 {
+		limits = append(limits, fmt.Sprintf("$%.2f", budget.MaxCostUSD))
+	}
+	if budget.MaxDuration != "" {
+		limits = append(limits, budget.MaxDuration)
+	}
+	return strings.Join(limits, ", ")
+}
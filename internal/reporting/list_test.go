@@ -0,0 +1,47 @@
+package reporting
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	evaluations "github.com/wolfeidau/mcp-evals"
+)
+
+func TestPrintEvalList(t *testing.T) {
+	assert := require.New(t)
+
+	config := &evaluations.EvalConfig{
+		Model:     "test-model",
+		Timeout:   "2m",
+		Trials:    2,
+		Thinking:  &evaluations.ThinkingConfig{BudgetTokens: 2048},
+		MCPServer: evaluations.MCPServerConfig{Command: "./server"},
+		Evals: []evaluations.Eval{
+			{Name: "add", Tags: []string{"smoke", "math"}},
+			{
+				Name:          "report",
+				Trials:        5,
+				Timeout:       "10m",
+				Thinking:      &evaluations.ThinkingConfig{},
+				Turns:         []string{"and last month?"},
+				SimulatedUser: &evaluations.SimulatedUser{Goal: "get the report"},
+				Budget:        &evaluations.Budget{MaxTokens: 20000, MaxCostUSD: 0.5},
+			},
+			{Name: "skipped"},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(PrintEvalList(&buf, config, config.Evals[:2]))
+
+	// Table cells are padded with non-breaking spaces
+	out := strings.ReplaceAll(stripANSI(buf.String()), "\u00a0", " ")
+	assert.Contains(out, "Model:          test-model (anthropic)")
+	assert.Contains(out, "MCP Server:     ./server (stdio)")
+	assert.Contains(out, "Selected:       2 of 3 evals")
+	assert.Regexp(`add\s+│ smoke, math\s+│ 2\s+│ 2m\s+│ 2048 tokens\s+│ 1\s+│ -`, out)
+	assert.Regexp(`report\s+│ -\s+│ 5\s+│ 10m\s+│ -\s+│ 2 \+ simulated\s+│ 20000 tokens, \$0\.50`, out)
+	assert.NotContains(out, "skipped")
+}
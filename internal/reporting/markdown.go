@@ -0,0 +1,252 @@
+package reporting
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+	"github.com/wolfeidau/mcp-evals/internal/help"
+)
+
+// MarkdownRenderer renders a GitHub-flavored Markdown report suitable for PR comments.
+// Each eval's breakdown is folded into a details block so the summary stays readable.
+type MarkdownRenderer struct{}
+
+// Render writes the Markdown report to w
+func (MarkdownRenderer) Render(w io.Writer, results []evaluations.EvalRunResult) error {
+	summary := NewResults(RunInfo{}, results).Summary
+
+	var b strings.Builder
+	b.WriteString("# Evaluation Summary\n\n")
+	fmt.Fprintf(&b, "**%d evals:** %d passed, %d failed, %d errors, %d timeouts, %d no grade",
+		summary.Total, summary.Passed, summary.Failed, summary.Errors, summary.Timeouts, summary.NoGrade)
+	if summary.BudgetExceeded > 0 {
+		fmt.Fprintf(&b, ", %d over budget", summary.BudgetExceeded)
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString("| Name | Status | Avg | Steps | Tools | Success% | Tool P/R | Tokens (I→O) |\n")
+	b.WriteString("| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: |\n")
+	for _, result := range results {
+		row := plainResultRow(result)
+		row[1] = statusIcon(resultStatus(result)) + " " + row[1]
+		for i, cell := range row {
+			row[i] = markdownCell(cell)
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(row, " | "))
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "Total duration %s, %s tokens",
+		formatDuration(time.Duration(summary.DurationMS)*time.Millisecond),
+		formatTokenCounts(summary.Usage.InputTokens, summary.Usage.OutputTokens))
+	if summary.Cost != nil {
+		fmt.Fprintf(&b, ", cost %s", formatCostBreakdown(summary.Cost))
+	}
+	b.WriteString("\n\n")
+
+	if len(results) == 0 {
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString("## Details\n\n")
+	for _, result := range results {
+		writeMarkdownEval(&b, result)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownEval(b *strings.Builder, result evaluations.EvalRunResult) {
+	status := resultStatus(result)
+	headline := fmt.Sprintf("%s %s", statusIcon(status), statusLabel(status))
+	if result.Grade != nil {
+		headline += fmt.Sprintf(" %.1f/5", result.Grade.Average())
+	}
+
+	b.WriteString("<details>\n")
+	fmt.Fprintf(b, "<summary><b>%s</b>: %s</summary>\n\n", html.EscapeString(result.Eval.Name), headline)
+
+	if result.Eval.Description != "" {
+		fmt.Fprintf(b, "_%s_\n\n", result.Eval.Description)
+	}
+	if status != StatusPass && status != StatusNoGrade {
+		if msg := failureMessage(result); msg != "" {
+			fmt.Fprintf(b, "**Error:** %s\n\n", msg)
+		}
+	}
+	if cost := resultCost(result); cost != nil {
+		fmt.Fprintf(b, "**Cost:** %s\n\n", formatCostBreakdown(cost))
+	}
+
+	if agg := result.Aggregate; agg != nil {
+		writeMarkdownTrials(b, result, agg)
+	} else {
+		writeMarkdownRun(b, result)
+	}
+
+	b.WriteString("</details>\n\n")
+}
+
+func writeMarkdownTrials(b *strings.Builder, result evaluations.EvalRunResult, agg *evaluations.TrialAggregate) {
+	fmt.Fprintf(b, "**Trials:** %d/%d passed (rule: %s)  \n", agg.Passes, agg.Trials, agg.Policy.Rule)
+	fmt.Fprintf(b, "**pass@k:** %s  \n", formatPassK(agg.PassAtK))
+	fmt.Fprintf(b, "**pass^k:** %s\n\n", formatPassK(agg.PassHatK))
+
+	if scores := agg.Scores; scores != nil {
+		fmt.Fprintf(b, "| Dimension (%d graded) | Mean | StdDev | Min | Max |\n", agg.Graded)
+		b.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+		for _, d := range []struct {
+			name  string
+			stats evaluations.ScoreStats
+		}{
+			{"Accuracy", scores.Accuracy},
+			{"Completeness", scores.Completeness},
+			{"Relevance", scores.Relevance},
+			{"Clarity", scores.Clarity},
+			{"Reasoning", scores.Reasoning},
+			{"Average", scores.Average},
+		} {
+			fmt.Fprintf(b, "| %s | %.2f | %.2f | %.1f | %.1f |\n", d.name, d.stats.Mean, d.stats.StdDev, d.stats.Min, d.stats.Max)
+		}
+		b.WriteString("\n")
+	}
+
+	for i, trial := range result.Trials {
+		fmt.Fprintf(b, "%d. %s\n", i+1, strings.ReplaceAll(formatTrialRun(trial, help.Styles{}), "\n    ", " — "))
+	}
+	b.WriteString("\n")
+}
+
+func writeMarkdownRun(b *strings.Builder, result evaluations.EvalRunResult) {
+	if grade := result.Grade; grade != nil {
+		b.WriteString("| Accuracy | Completeness | Relevance | Clarity | Reasoning |\n")
+		b.WriteString("| ---: | ---: | ---: | ---: | ---: |\n")
+		fmt.Fprintf(b, "| %d | %d | %d | %d | %d |\n\n", grade.Accuracy, grade.Completeness, grade.Relevance, grade.Clarity, grade.Reasoning)
+		if grade.OverallComment != "" {
+			fmt.Fprintf(b, "> %s\n\n", strings.ReplaceAll(grade.OverallComment, "\n", "\n> "))
+		}
+	}
+
+	if len(result.Assertions) > 0 {
+		b.WriteString("**Assertions**\n\n")
+		for _, assertion := range result.Assertions {
+			fmt.Fprintf(b, "- %s `%s`: %s\n", passIcon(assertion.Passed), assertion.Assertion.Type, assertion.Message)
+		}
+		b.WriteString("\n")
+	}
+
+	trace := result.Trace
+	if trace == nil {
+		return
+	}
+
+	if trajectory := trace.ToolTrajectory; trajectory != nil {
+		fmt.Fprintf(b, "**Tool trajectory:** %s %s\n\n", passIcon(trajectory.Passed), formatTrajectory(trajectory))
+	}
+
+	if len(trace.Steps) > 0 {
+		b.WriteString("**Steps**\n\n")
+		for _, step := range trace.Steps {
+			fmt.Fprintf(b, "%d. %s, %s tokens", step.StepNumber, formatDuration(step.Duration),
+				formatTokensWithCache(step.InputTokens, step.OutputTokens, step.CacheCreationInputTokens, step.CacheReadInputTokens))
+			if step.StopReason != "" {
+				fmt.Fprintf(b, ", stop: %s", step.StopReason)
+			}
+			b.WriteString("\n")
+			if step.Thinking != "" {
+				for _, line := range strings.Split(strings.TrimSpace(step.Thinking), "\n") {
+					fmt.Fprintf(b, "   > %s\n", line)
+				}
+			}
+			for _, tool := range step.ToolCalls {
+				fmt.Fprintf(b, "   - %s `%s` (%s)", passIcon(tool.Success), tool.ToolName, formatDuration(tool.Duration))
+				if tool.Error != "" {
+					fmt.Fprintf(b, ": %s", tool.Error)
+				}
+				b.WriteString("\n")
+			}
+			if step.Error != "" {
+				fmt.Fprintf(b, "   - ❌ %s\n", step.Error)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if response := finalResponse(result); response != "" {
+		b.WriteString("**Response**\n\n")
+		b.WriteString(codeBlock("text", response))
+		b.WriteString("\n")
+	}
+}
+
+// plainResultRow is the summary table row for a result without terminal styling,
+// using the full eval name
+func plainResultRow(result evaluations.EvalRunResult) []string {
+	row := buildResultRow(result, help.Styles{})
+	row[0] = result.Eval.Name
+	return row
+}
+
+// finalResponse returns the agent's final answer, falling back to the trace for
+// results loaded from trace files
+func finalResponse(result evaluations.EvalRunResult) string {
+	if result.Result != nil && result.Result.RawResponse != "" {
+		return result.Result.RawResponse
+	}
+	if result.Trace == nil {
+		return ""
+	}
+	if result.Trace.Grading != nil && result.Trace.Grading.ModelResponse != "" {
+		return result.Trace.Grading.ModelResponse
+	}
+	if n := len(result.Trace.Steps); n > 0 {
+		return result.Trace.Steps[n-1].ModelResponse
+	}
+	return ""
+}
+
+func statusIcon(status string) string {
+	switch status {
+	case StatusPass:
+		return "✅"
+	case StatusFail:
+		return "❌"
+	case StatusError, StatusTimeout, StatusBudgetExceeded:
+		return "⚠️"
+	default:
+		return "➖"
+	}
+}
+
+// statusLabel formats a status the way the summary table does, e.g. "NO GRADE"
+func statusLabel(status string) string {
+	return strings.ToUpper(strings.ReplaceAll(status, "_", " "))
+}
+
+func passIcon(passed bool) string {
+	if passed {
+		return "✅"
+	}
+	return "❌"
+}
+
+// markdownCell keeps a value on one table row and stops it splitting the columns
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+// codeBlock fences text with enough backticks that fences inside it can't close the block
+func codeBlock(lang, text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + strings.TrimRight(text, "\n") + "\n" + fence + "\n"
+}
@@ -0,0 +1,39 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+)
+
+// Renderer renders a human-readable report of evaluation results
+type Renderer interface {
+	// Render writes the report for results to w
+	Render(w io.Writer, results []evaluations.EvalRunResult) error
+}
+
+// renderFormats lists the supported report formats in the order they are documented
+var renderFormats = []string{"text", "markdown", "html"}
+
+// NewRenderer returns the renderer for a report format: text, markdown or html.
+// Verbose adds the per-eval breakdown to the text report; the markdown and html
+// reports always include it in collapsible sections.
+func NewRenderer(format string, verbose bool) (Renderer, error) {
+	switch format {
+	case "text":
+		return TextRenderer{Verbose: verbose}, nil
+	case "markdown":
+		return MarkdownRenderer{}, nil
+	case "html":
+		return HTMLRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("invalid report format '%s': must be one of: %s", format, strings.Join(RenderFormats(), ", "))
+	}
+}
+
+// RenderFormats returns the names of the supported report formats
+func RenderFormats() []string {
+	return append([]string(nil), renderFormats...)
+}
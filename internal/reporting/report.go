@@ -2,8 +2,10 @@ package reporting
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image/color"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,8 +17,21 @@ import (
 	"github.com/wolfeidau/mcp-evals/internal/help"
 )
 
+// ErrNotTraceFile is returned by LoadTraceFile for JSON files that aren't eval traces
+var ErrNotTraceFile = errors.New("not a trace file")
+
 // PrintStyledReport generates a colorized, styled report from evaluation results
 func PrintStyledReport(results []evaluations.EvalRunResult, verbose bool) error {
+	return TextRenderer{Verbose: verbose}.Render(os.Stdout, results)
+}
+
+// TextRenderer renders the colorized terminal report
+type TextRenderer struct {
+	Verbose bool // Include the detailed per-eval breakdown
+}
+
+// Render writes the styled report to w
+func (r TextRenderer) Render(w io.Writer, results []evaluations.EvalRunResult) error {
 	styles := help.DefaultStyles()
 
 	// Build the complete report content
@@ -28,7 +43,7 @@ func PrintStyledReport(results []evaluations.EvalRunResult, verbose bool) error
 	content.WriteString(captureOverallStats(results, styles))
 
 	// Print detailed view if verbose
-	if verbose {
+	if r.Verbose {
 		content.WriteString(captureDetailedBreakdown(results, styles))
 	}
 
@@ -37,9 +52,8 @@ func PrintStyledReport(results []evaluations.EvalRunResult, verbose bool) error
 		MarginTop(1).
 		MarginBottom(1)
 
-	fmt.Println(marginStyle.Render(content.String()))
-
-	return nil
+	_, err := fmt.Fprintln(w, marginStyle.Render(content.String()))
+	return err
 }
 
 // Heading helpers for consistent spacing
@@ -86,7 +100,7 @@ func captureSummaryTable(results []evaluations.EvalRunResult, styles help.Styles
 			}
 			return lipgloss.NewStyle().Align(lipgloss.Left).Padding(0, 2)
 		}).
-		Headers("Name", "Status", "Avg", "Steps", "Tools", "Success%", "Tokens (I→O)").
+		Headers("Name", "Status", "Avg", "Steps", "Tools", "Success%", "Tool P/R", "Tokens (I→O)").
 		Rows(rows...)
 
 	output.WriteString(t.String() + "\n")
@@ -100,29 +114,48 @@ func buildResultRow(result evaluations.EvalRunResult, styles help.Styles) []stri
 		name = name[:22] + "..."
 	}
 
-	// Handle error case
-	if result.Error != nil {
+	if result.Aggregate != nil {
+		return buildTrialRow(name, result, styles)
+	}
+
+	// Handle timeout case
+	if result.Status == evaluations.EvalStatusTimeout {
+		status := styles.Error.Render("TIMEOUT")
+		return []string{name, status, "-", "-", "-", "-", "-", "-"}
+	}
+
+	// Handle exhausted budget case
+	if result.Status == evaluations.EvalStatusBudgetExceeded {
+		status := styles.Error.Render("BUDGET")
+		return []string{name, status, "-", "-", "-", "-", "-", "-"}
+	}
+
+	// Handle error case, failed deterministic checks and minimum scores are reported as FAIL below
+	if hasRunError(result) {
 		status := styles.Error.Render("ERROR")
-		return []string{name, status, "-", "-", "-", "-", "-"}
+		return []string{name, status, "-", "-", "-", "-", "-", "-"}
 	}
 
 	// Handle no trace case
 	if result.Trace == nil {
 		status := styles.Muted.Render("NO TRACE")
-		return []string{name, status, "-", "-", "-", "-", "-"}
+		return []string{name, status, "-", "-", "-", "-", "-", "-"}
 	}
 
 	// Calculate metrics
 	avgScoreVal := 0.0
 	statusStr := styles.Muted.Render("NO GRADE")
 	if result.Grade != nil {
-		avgScoreVal = avgScore(result.Grade)
-		if avgScoreVal >= 3.0 {
+		avgScoreVal = result.Grade.Average()
+		if avgScoreVal >= evaluations.PassingScore {
 			statusStr = styles.Success.Render("PASS")
 		} else {
 			statusStr = styles.Error.Render("FAIL")
 		}
 	}
+	if hasFailedChecks(result) || result.Error != nil {
+		statusStr = styles.Error.Render("FAIL")
+	}
 
 	trace := result.Trace
 	successRate := calculateToolSuccessRate(trace)
@@ -138,7 +171,65 @@ func buildResultRow(result evaluations.EvalRunResult, styles help.Styles) []stri
 	successStr := fmt.Sprintf("%d%%", int(successRate))
 	tokenStr := formatTokenCounts(trace.TotalInputTokens, trace.TotalOutputTokens)
 
-	return []string{name, statusStr, avgStr, stepsStr, toolsStr, successStr, tokenStr}
+	trajectoryStr := formatTrajectory(trace.ToolTrajectory)
+
+	return []string{name, statusStr, avgStr, stepsStr, toolsStr, successStr, trajectoryStr, tokenStr}
+}
+
+// buildTrialRow summarizes an eval with several trials: the status comes from the
+// trial policy, scores are the mean ± standard deviation, steps, tools and tool P/R
+// are averaged over the trials with a trace, and tokens are totalled
+func buildTrialRow(name string, result evaluations.EvalRunResult, styles help.Styles) []string {
+	aggregate := result.Aggregate
+
+	statusStr := styles.Success.Render(fmt.Sprintf("PASS %d/%d", aggregate.Passes, aggregate.Trials))
+	if !aggregate.Passed {
+		statusStr = styles.Error.Render(fmt.Sprintf("FAIL %d/%d", aggregate.Passes, aggregate.Trials))
+	}
+
+	avgStr := "-"
+	if aggregate.Scores != nil {
+		avgStr = fmt.Sprintf("%.1f±%.1f", aggregate.Scores.Average.Mean, aggregate.Scores.Average.StdDev)
+	}
+
+	traced := 0
+	steps, tools, successfulTools, inputTokens, outputTokens := 0, 0, 0, 0, 0
+	trajectories := 0
+	precision, recall := 0.0, 0.0
+	for _, trial := range result.Trials {
+		trace := trial.Trace
+		if trace == nil {
+			continue
+		}
+		traced++
+		steps += trace.StepCount
+		tools += trace.ToolCallCount
+		successfulTools += countSuccessfulToolCalls(trace)
+		inputTokens += trace.TotalInputTokens
+		outputTokens += trace.TotalOutputTokens
+		if trace.ToolTrajectory != nil {
+			trajectories++
+			precision += trace.ToolTrajectory.Precision
+			recall += trace.ToolTrajectory.Recall
+		}
+	}
+	if traced == 0 {
+		return []string{name, statusStr, avgStr, "-", "-", "-", "-", "-"}
+	}
+
+	stepsStr := fmt.Sprintf("%.1f", float64(steps)/float64(traced))
+	toolsStr := fmt.Sprintf("%.1f", float64(tools)/float64(traced))
+	successStr := "0%"
+	if tools > 0 {
+		successStr = fmt.Sprintf("%d%%", successfulTools*100/tools)
+	}
+	trajectoryStr := "-"
+	if trajectories > 0 {
+		trajectoryStr = fmt.Sprintf("%.2f/%.2f", precision/float64(trajectories), recall/float64(trajectories))
+	}
+	tokenStr := formatTokenCounts(inputTokens, outputTokens)
+
+	return []string{name, statusStr, avgStr, stepsStr, toolsStr, successStr, trajectoryStr, tokenStr}
 }
 
 func captureOverallStats(results []evaluations.EvalRunResult, styles help.Styles) string {
@@ -147,6 +238,8 @@ func captureOverallStats(results []evaluations.EvalRunResult, styles help.Styles
 	// Calculate overall statistics
 	totalEvals := len(results)
 	errorCount := 0
+	timeoutCount := 0
+	budgetCount := 0
 	passCount := 0
 	failCount := 0
 	noGradeCount := 0
@@ -158,11 +251,32 @@ func captureOverallStats(results []evaluations.EvalRunResult, styles help.Styles
 	successfulToolCalls := 0
 	totalCacheCreationTokens := 0
 	totalCacheReadTokens := 0
+	totalRetries := 0
+	var totalRetryDuration time.Duration
+	var totalCost *evaluations.Cost
+	totalAssertions := 0
+	passedAssertions := 0
+	trajectoryCount := 0
+	totalPrecision := 0.0
+	totalRecall := 0.0
+
+	trialEvals := 0
+	totalTrials := 0
+	passedTrials := 0
+
+	// addRunMetrics accumulates the assertion, tool trajectory and trace metrics of a single run
+	addRunMetrics := func(result evaluations.EvalRunResult) {
+		for _, assertion := range result.Assertions {
+			totalAssertions++
+			if assertion.Passed {
+				passedAssertions++
+			}
+		}
 
-	for _, result := range results {
-		if result.Error != nil {
-			errorCount++
-			continue
+		if result.Trace != nil && result.Trace.ToolTrajectory != nil {
+			trajectoryCount++
+			totalPrecision += result.Trace.ToolTrajectory.Precision
+			totalRecall += result.Trace.ToolTrajectory.Recall
 		}
 
 		if result.Trace != nil {
@@ -172,24 +286,61 @@ func captureOverallStats(results []evaluations.EvalRunResult, styles help.Styles
 			totalToolCalls += result.Trace.ToolCallCount
 			totalCacheCreationTokens += result.Trace.TotalCacheCreationTokens
 			totalCacheReadTokens += result.Trace.TotalCacheReadTokens
+			totalCost = totalCost.Add(result.Trace.Cost)
 
-			// Count successful tool calls
-			for _, step := range result.Trace.Steps {
-				for _, tool := range step.ToolCalls {
-					if tool.Success {
-						successfulToolCalls++
-					}
+			retries, retryDuration := calculateRetries(result.Trace)
+			totalRetries += retries
+			totalRetryDuration += retryDuration
+
+			successfulToolCalls += countSuccessfulToolCalls(result.Trace)
+		}
+	}
+
+	for _, result := range results {
+		if result.Aggregate != nil {
+			trialEvals++
+			totalTrials += result.Aggregate.Trials
+			passedTrials += result.Aggregate.Passes
+			if result.Aggregate.Passed {
+				passCount++
+			} else {
+				failCount++
+			}
+
+			for _, trial := range result.Trials {
+				if trial.Status == evaluations.EvalStatusTimeout || trial.Status == evaluations.EvalStatusBudgetExceeded || hasRunError(trial) {
+					continue
 				}
+				addRunMetrics(trial)
 			}
+			continue
+		}
+
+		if result.Status == evaluations.EvalStatusTimeout {
+			timeoutCount++
+			continue
+		}
+		if result.Status == evaluations.EvalStatusBudgetExceeded {
+			budgetCount++
+			continue
+		}
+		if hasRunError(result) {
+			errorCount++
+			continue
 		}
 
-		if result.Grade != nil {
-			if avgScore(result.Grade) >= 3.0 {
+		addRunMetrics(result)
+
+		switch {
+		case hasFailedChecks(result), result.Error != nil:
+			failCount++
+		case result.Grade != nil:
+			if result.Grade.Average() >= evaluations.PassingScore {
 				passCount++
 			} else {
 				failCount++
 			}
-		} else {
+		default:
 			noGradeCount++
 		}
 	}
@@ -213,12 +364,50 @@ func captureOverallStats(results []evaluations.EvalRunResult, styles help.Styles
 		errorStr := styles.Error.Render(fmt.Sprintf("⚠ Error:  %d (%.0f%%)", errorCount, float64(errorCount)/float64(totalEvals)*100))
 		output.WriteString(fmt.Sprintf("  %s\n", errorStr))
 	}
+	if timeoutCount > 0 {
+		timeoutStr := styles.Error.Render(fmt.Sprintf("⏱ Timeout: %d (%.0f%%)", timeoutCount, float64(timeoutCount)/float64(totalEvals)*100))
+		output.WriteString(fmt.Sprintf("  %s\n", timeoutStr))
+	}
+	if budgetCount > 0 {
+		budgetStr := styles.Error.Render(fmt.Sprintf("$ Over Budget: %d (%.0f%%)", budgetCount, float64(budgetCount)/float64(totalEvals)*100))
+		output.WriteString(fmt.Sprintf("  %s\n", budgetStr))
+	}
 	if noGradeCount > 0 {
 		noGradeStr := styles.Muted.Render(fmt.Sprintf("○ No Grade: %d", noGradeCount))
 		output.WriteString(fmt.Sprintf("  %s\n", noGradeStr))
 	}
 	output.WriteString("\n")
 
+	// Trial results across evals that ran more than once
+	if trialEvals > 0 {
+		output.WriteString(h3(styles, "Trials"))
+		output.WriteString(fmt.Sprintf("Evals with Trials:  %d\n", trialEvals))
+		output.WriteString(fmt.Sprintf("Trial Pass Rate:    %.0f%% (%d/%d)\n",
+			float64(passedTrials)/float64(totalTrials)*100, passedTrials, totalTrials))
+		output.WriteString("\n")
+	}
+
+	// Assertion results
+	if totalAssertions > 0 {
+		output.WriteString(h3(styles, "Assertions"))
+		assertionStr := fmt.Sprintf("%d/%d passed", passedAssertions, totalAssertions)
+		if passedAssertions == totalAssertions {
+			assertionStr = styles.Success.Render(assertionStr)
+		} else {
+			assertionStr = styles.Error.Render(assertionStr)
+		}
+		output.WriteString(fmt.Sprintf("Assertions:         %s\n", assertionStr))
+		output.WriteString("\n")
+	}
+
+	// Tool trajectory results
+	if trajectoryCount > 0 {
+		output.WriteString(h3(styles, "Tool Trajectory"))
+		output.WriteString(fmt.Sprintf("Avg Precision:      %.2f\n", totalPrecision/float64(trajectoryCount)))
+		output.WriteString(fmt.Sprintf("Avg Recall:         %.2f\n", totalRecall/float64(trajectoryCount)))
+		output.WriteString("\n")
+	}
+
 	// Performance metrics
 	if totalInputTokens > 0 || totalDuration > 0 {
 		output.WriteString(h3(styles, "Performance Metrics"))
@@ -238,6 +427,26 @@ func captureOverallStats(results []evaluations.EvalRunResult, styles help.Styles
 				formatTokens(avgInput),
 				formatTokens(avgOutput)))
 		}
+
+		if totalRetries > 0 {
+			output.WriteString(fmt.Sprintf("Retries:            %d (%s waiting)\n",
+				totalRetries,
+				formatDuration(totalRetryDuration)))
+		}
+		output.WriteString("\n")
+	}
+
+	// Cost, when the traces were priced
+	if totalCost != nil {
+		output.WriteString(h3(styles, "Cost"))
+		output.WriteString(fmt.Sprintf("Total Cost:         %s\n", formatCost(totalCost.Total)))
+		output.WriteString(fmt.Sprintf("Agent:              %s\n", formatCost(totalCost.Agent)))
+		output.WriteString(fmt.Sprintf("Grading:            %s\n", formatCost(totalCost.Grading)))
+		output.WriteString(fmt.Sprintf("Avg Cost/Eval:      %s\n", formatCost(totalCost.Total/float64(totalEvals))))
+		if len(totalCost.UnpricedModels) > 0 {
+			output.WriteString(fmt.Sprintf("Unpriced Models:    %s\n",
+				styles.Error.Render(strings.Join(totalCost.UnpricedModels, ", "))))
+		}
 		output.WriteString("\n")
 	}
 
@@ -287,12 +496,8 @@ func captureOverallStats(results []evaluations.EvalRunResult, styles help.Styles
 				output.WriteString(fmt.Sprintf("Cache Hit Rate:     %s\n", cacheHitRateStr))
 			}
 
-			// Estimate cost savings (cache reads are 90% cheaper)
-			// This is an approximation based on Anthropic's pricing
-			estimatedSavings := float64(totalCacheReadTokens) * 0.9
-			if estimatedSavings > 1000 {
-				output.WriteString(fmt.Sprintf("Est. Token Savings: ~%s tokens (90%% discount on reads)\n",
-					formatTokens(int(estimatedSavings))))
+			if totalCost != nil && totalCost.CacheSavings > 0 {
+				output.WriteString(fmt.Sprintf("Cache Savings:      %s\n", formatCost(totalCost.CacheSavings)))
 			}
 		}
 		output.WriteString("\n")
@@ -329,20 +534,38 @@ func captureEvalDetail(result evaluations.EvalRunResult, styles help.Styles) str
 		output.WriteString("\n")
 	}
 
+	if result.Aggregate != nil {
+		output.WriteString(captureTrialDetail(result, styles))
+		return output.String()
+	}
+
 	// Status
 	switch {
-	case result.Error != nil:
+	case result.Status == evaluations.EvalStatusTimeout:
+		output.WriteString(fmt.Sprintf("Status: %s\n", styles.Error.Render("TIMEOUT")))
+		if result.Error != nil {
+			output.WriteString(fmt.Sprintf("Error: %s\n", result.Error.Error()))
+		}
+	case result.Status == evaluations.EvalStatusBudgetExceeded:
+		output.WriteString(fmt.Sprintf("Status: %s\n", styles.Error.Render("BUDGET EXCEEDED")))
+		output.WriteString(fmt.Sprintf("Error: %s\n", result.Error.Error()))
+	case hasFailedChecks(result):
+		output.WriteString(fmt.Sprintf("Status: %s (deterministic checks failed)\n", styles.Error.Render("FAIL")))
+	case hasRunError(result):
 		output.WriteString(fmt.Sprintf("Status: %s\n", styles.Error.Render("ERROR")))
 		output.WriteString(fmt.Sprintf("Error: %s\n", result.Error.Error()))
 	case result.Grade != nil:
-		avg := avgScore(result.Grade)
+		avg := result.Grade.Average()
 		statusText := "PASS"
 		statusStyle := styles.Success
-		if avg < 3.0 {
+		if avg < evaluations.PassingScore || result.Error != nil {
 			statusText = "FAIL"
 			statusStyle = styles.Error
 		}
 		output.WriteString(fmt.Sprintf("Status: %s (%.1f/5)\n", statusStyle.Render(statusText), avg))
+		if result.Error != nil {
+			output.WriteString(fmt.Sprintf("Error: %s\n", result.Error.Error()))
+		}
 	default:
 		output.WriteString(fmt.Sprintf("Status: %s\n", styles.Muted.Render("NO GRADE")))
 	}
@@ -375,6 +598,18 @@ func captureEvalDetail(result evaluations.EvalRunResult, styles help.Styles) str
 				formatDuration(step.Duration),
 				tokensStr))
 
+			if retries := len(step.Attempts) - 1; retries > 0 {
+				output.WriteString(fmt.Sprintf("  %s\n", styles.Muted.Render(fmt.Sprintf("↻ %d retries (%s)", retries, formatDuration(step.RetryDuration)))))
+			}
+
+			// Show the model's reasoning before the tools it chose
+			if step.Thinking != "" {
+				output.WriteString("  Thinking:\n")
+				for _, line := range strings.Split(strings.TrimSpace(step.Thinking), "\n") {
+					output.WriteString("    " + styles.Muted.Render(line) + "\n")
+				}
+			}
+
 			// Show tool calls
 			for _, tool := range step.ToolCalls {
 				if tool.Success {
@@ -409,10 +644,47 @@ func captureEvalDetail(result evaluations.EvalRunResult, styles help.Styles) str
 			formatDuration(llmTime),
 			formatDuration(toolTime),
 			tokensStr)
+		if result.Trace.Cost != nil {
+			summaryInfo += " | Cost: " + formatCost(result.Trace.Cost.Total)
+		}
 
 		output.WriteString(summaryStyle.Render(summaryInfo) + "\n")
 	}
 
+	// Assertion results
+	if len(result.Assertions) > 0 {
+		output.WriteString(h4(styles, "Assertions"))
+		for _, assertion := range result.Assertions {
+			if assertion.Passed {
+				output.WriteString(fmt.Sprintf("%s %s\n", styles.Success.Render("✓"), assertion.Assertion))
+			} else {
+				output.WriteString(fmt.Sprintf("%s %s\n", styles.Error.Render("✗"), assertion.Assertion))
+			}
+			output.WriteString(fmt.Sprintf("    %s\n", styles.Muted.Render(assertion.Message)))
+		}
+		output.WriteString("\n")
+	}
+
+	// Tool trajectory
+	if trace := result.Trace; trace != nil && trace.ToolTrajectory != nil {
+		trajectory := trace.ToolTrajectory
+		output.WriteString(h4(styles, "Tool Trajectory"))
+		matchStr := styles.Success.Render("✓ matched")
+		if !trajectory.Passed {
+			matchStr = styles.Error.Render("✗ did not match")
+		}
+		output.WriteString(fmt.Sprintf("Expected Tools: %s (%s, %d/%d expected calls, %d calls made)\n",
+			matchStr, trajectory.Match, trajectory.Matched, trajectory.Expected, trajectory.Actual))
+		output.WriteString(fmt.Sprintf("Precision: %.2f  Recall: %.2f\n", trajectory.Precision, trajectory.Recall))
+		for _, missing := range trajectory.Missing {
+			output.WriteString(fmt.Sprintf("  %s missing: %s\n", styles.Error.Render("✗"), missing))
+		}
+		for _, unexpected := range trajectory.Unexpected {
+			output.WriteString(fmt.Sprintf("  %s unexpected: %s\n", styles.Muted.Render("•"), unexpected))
+		}
+		output.WriteString("\n")
+	}
+
 	// Grading details
 	if result.Grade != nil {
 		output.WriteString(h4(styles, "Grading Details"))
@@ -476,8 +748,13 @@ func captureEvalDetail(result evaluations.EvalRunResult, styles help.Styles) str
 				Foreground(styles.Argument.GetForeground()).
 				Padding(0, 0, 1, 0)
 
-			perfInfo := fmt.Sprintf("Duration: %s | Tokens: %s%s",
-				durationStr, tokensStr, cacheInfo)
+			retryInfo := ""
+			if retries := len(grading.Attempts) - 1; retries > 0 {
+				retryInfo = fmt.Sprintf(" | Retries: %d (%s)", retries, formatDuration(grading.RetryDuration))
+			}
+
+			perfInfo := fmt.Sprintf("Duration: %s | Tokens: %s%s%s",
+				durationStr, tokensStr, cacheInfo, retryInfo)
 
 			output.WriteString(perfStyle.Render(perfInfo) + "\n")
 		}
@@ -487,6 +764,108 @@ func captureEvalDetail(result evaluations.EvalRunResult, styles help.Styles) str
 	return output.String()
 }
 
+// captureTrialDetail renders the outcome of an eval with several trials: the
+// pass rate, the score distribution of each dimension and a line per trial
+func captureTrialDetail(result evaluations.EvalRunResult, styles help.Styles) string {
+	var output strings.Builder
+	aggregate := result.Aggregate
+
+	statusStr := styles.Success.Render("PASS")
+	if !aggregate.Passed {
+		statusStr = styles.Error.Render("FAIL")
+	}
+	output.WriteString(fmt.Sprintf("Status: %s (%d/%d trials passed, rule: %s)\n",
+		statusStr, aggregate.Passes, aggregate.Trials, aggregate.Policy.Rule))
+	if result.Error != nil {
+		output.WriteString(fmt.Sprintf("Error: %s\n", result.Error.Error()))
+	}
+	output.WriteString("\n")
+
+	output.WriteString(h4(styles, "Trials"))
+	output.WriteString(fmt.Sprintf("Pass Rate:     %.0f%% (%d/%d)\n", aggregate.PassRate*100, aggregate.Passes, aggregate.Trials))
+	output.WriteString(fmt.Sprintf("pass@k:        %s\n", formatPassK(aggregate.PassAtK)))
+	output.WriteString(fmt.Sprintf("pass^k:        %s\n", formatPassK(aggregate.PassHatK)))
+	output.WriteString("\n")
+
+	if scores := aggregate.Scores; scores != nil {
+		output.WriteString(h4(styles, fmt.Sprintf("Score Distribution (%d graded)", aggregate.Graded)))
+		output.WriteString(styles.Muted.Render(fmt.Sprintf("%-13s %5s %7s %5s %5s", "", "Mean", "StdDev", "Min", "Max")) + "\n")
+
+		dimensions := []struct {
+			name  string
+			stats evaluations.ScoreStats
+		}{
+			{"Accuracy", scores.Accuracy},
+			{"Completeness", scores.Completeness},
+			{"Relevance", scores.Relevance},
+			{"Clarity", scores.Clarity},
+			{"Reasoning", scores.Reasoning},
+			{"Average", scores.Average},
+		}
+		for _, d := range dimensions {
+			output.WriteString(fmt.Sprintf("%-13s %5.2f %7.2f %5.1f %5.1f\n",
+				d.name+":", d.stats.Mean, d.stats.StdDev, d.stats.Min, d.stats.Max))
+		}
+		output.WriteString("\n")
+	}
+
+	output.WriteString(h4(styles, "Runs"))
+	for i, trial := range result.Trials {
+		output.WriteString(fmt.Sprintf("Trial %d: %s\n", i+1, formatTrialRun(trial, styles)))
+	}
+	output.WriteString("\n")
+
+	return output.String()
+}
+
+// formatTrialRun summarizes a single trial on one line
+func formatTrialRun(trial evaluations.EvalRunResult, styles help.Styles) string {
+	var details []string
+	if trial.Trace != nil {
+		details = append(details,
+			fmt.Sprintf("%d steps", trial.Trace.StepCount),
+			fmt.Sprintf("%d tools", trial.Trace.ToolCallCount),
+			formatDuration(trial.Trace.TotalDuration))
+	}
+
+	var status string
+	switch {
+	case trial.Status == evaluations.EvalStatusTimeout:
+		status = styles.Error.Render("TIMEOUT")
+	case trial.Status == evaluations.EvalStatusBudgetExceeded:
+		status = styles.Error.Render("BUDGET")
+	case hasRunError(trial):
+		status = styles.Error.Render("ERROR")
+	case hasFailedChecks(trial), trial.Error != nil:
+		status = styles.Error.Render("FAIL")
+	case trial.Grade != nil && trial.Grade.Average() < evaluations.PassingScore:
+		status = styles.Error.Render("FAIL")
+	default:
+		status = styles.Success.Render("PASS")
+	}
+	if trial.Grade != nil {
+		status += fmt.Sprintf(" %.1f/5", trial.Grade.Average())
+	}
+
+	line := status
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+	if trial.Error != nil {
+		line += "\n    " + styles.Muted.Render(trial.Error.Error())
+	}
+	return line
+}
+
+// formatPassK formats pass@k style estimates for every k, e.g. "1: 0.80  2: 0.96  3: 1.00"
+func formatPassK(values []float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%d: %.2f", i+1, v)
+	}
+	return strings.Join(parts, "  ")
+}
+
 // LoadTraceFile loads a trace file and reconstructs an EvalRunResult
 func LoadTraceFile(path string) (evaluations.EvalRunResult, error) {
 	data, err := os.ReadFile(path)
@@ -494,27 +873,37 @@ func LoadTraceFile(path string) (evaluations.EvalRunResult, error) {
 		return evaluations.EvalRunResult{}, err
 	}
 
-	// Try to unmarshal as full EvalRunResult first (new format)
-	var fullResult struct {
-		Eval  evaluations.Eval         `json:"eval"`
-		Grade *evaluations.GradeResult `json:"grade,omitempty"`
-		Trace *evaluations.EvalTrace   `json:"trace"`
-	}
-
-	if err := json.Unmarshal(data, &fullResult); err == nil && fullResult.Eval.Name != "" {
-		// New format with full result
-		return evaluations.EvalRunResult{
-			Eval:  fullResult.Eval,
-			Grade: fullResult.Grade,
-			Trace: fullResult.Trace,
-		}, nil
+	// Try to unmarshal as full EvalRunResult first (new format). Files written before
+	// results were saved whole lack the statuses, trial evals and aggregate error.
+	var result evaluations.EvalRunResult
+	if err := json.Unmarshal(data, &result); err == nil && result.Eval.Name != "" {
+		restore := func(run *evaluations.EvalRunResult) {
+			if run.Eval.Name == "" {
+				run.Eval = result.Eval
+			}
+			if run.Status == "" && run.Trace != nil {
+				run.Status = run.Trace.Status
+			}
+		}
+		restore(&result)
+		for i := range result.Trials {
+			restore(&result.Trials[i])
+		}
+		if result.Aggregate != nil && result.Error == nil {
+			result.Error = result.Aggregate.Err()
+		}
+		return result, nil
 	}
 
-	// Fall back to old format (just trace)
+	// Fall back to old format (just trace). Other JSON files, such as results.json or a
+	// cassette, decode as neither format.
 	var trace evaluations.EvalTrace
 	if err := json.Unmarshal(data, &trace); err != nil {
 		return evaluations.EvalRunResult{}, fmt.Errorf("failed to parse trace file: %w", err)
 	}
+	if len(trace.Steps) == 0 {
+		return evaluations.EvalRunResult{}, fmt.Errorf("%w: no eval name or steps found", ErrNotTraceFile)
+	}
 
 	// Extract eval name from filename
 	evalName := strings.TrimSuffix(filepath.Base(path), ".json")
@@ -523,7 +912,8 @@ func LoadTraceFile(path string) (evaluations.EvalRunResult, error) {
 		Eval: evaluations.Eval{
 			Name: evalName,
 		},
-		Trace: &trace,
+		Status: trace.Status,
+		Trace:  &trace,
 	}, nil
 }
 
@@ -534,6 +924,10 @@ func calculateToolSuccessRate(trace *evaluations.EvalTrace) float64 {
 		return 0.0
 	}
 
+	return float64(countSuccessfulToolCalls(trace)) / float64(trace.ToolCallCount) * 100
+}
+
+func countSuccessfulToolCalls(trace *evaluations.EvalTrace) int {
 	successful := 0
 	for _, step := range trace.Steps {
 		for _, tool := range step.ToolCalls {
@@ -542,8 +936,48 @@ func calculateToolSuccessRate(trace *evaluations.EvalTrace) float64 {
 			}
 		}
 	}
+	return successful
+}
+
+// hasFailedChecks reports whether any assertion or the tool trajectory failed
+func hasFailedChecks(result evaluations.EvalRunResult) bool {
+	return result.CheckFailures() != nil
+}
 
-	return float64(successful) / float64(trace.ToolCallCount) * 100
+// hasRunError reports whether the eval errored before it could be graded, e.g. on a
+// transport or grading failure. An error alongside a grade is a failed minimum score.
+func hasRunError(result evaluations.EvalRunResult) bool {
+	return result.Error != nil && result.Grade == nil && !hasFailedChecks(result)
+}
+
+// formatTrajectory formats tool trajectory precision and recall for the summary table
+func formatTrajectory(trajectory *evaluations.ToolTrajectoryResult) string {
+	if trajectory == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f/%.2f", trajectory.Precision, trajectory.Recall)
+}
+
+// calculateRetries counts retried model API requests and the time they cost across agent steps and grading
+func calculateRetries(trace *evaluations.EvalTrace) (int, time.Duration) {
+	retries := 0
+	var duration time.Duration
+
+	for _, step := range trace.Steps {
+		if len(step.Attempts) > 1 {
+			retries += len(step.Attempts) - 1
+		}
+		duration += step.RetryDuration
+	}
+
+	if trace.Grading != nil {
+		if len(trace.Grading.Attempts) > 1 {
+			retries += len(trace.Grading.Attempts) - 1
+		}
+		duration += trace.Grading.RetryDuration
+	}
+
+	return retries, duration
 }
 
 func formatDuration(d time.Duration) string {
@@ -611,11 +1045,6 @@ func formatTokensWithCache(input, output, cacheCreated, cacheRead int) string {
 	return baseFormat
 }
 
-func avgScore(grade *evaluations.GradeResult) float64 {
-	sum := grade.Accuracy + grade.Completeness + grade.Relevance + grade.Clarity + grade.Reasoning
-	return float64(sum) / 5.0
-}
-
 func getScoreColor(score int, styles help.Styles) color.Color {
 	switch {
 	case score >= 4:
@@ -2,6 +2,8 @@ package reporting
 
 import (
 	"bytes"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -162,6 +164,25 @@ func TestPrintStyledReport(t *testing.T) {
 		// Check for comments (wrapped text may be on different lines)
 		assert.Contains(plainOutput, "weather API tools")
 		assert.Contains(plainOutput, "Failed to properly authenticate")
+		assert.NotContains(plainOutput, "### Cost")
+	})
+
+	t.Run("with cost", func(t *testing.T) {
+		priced := loadTestFixtures(t)
+		priced[0].Trace.Cost = &evaluations.Cost{Agent: 0.02, Grading: 0.01, Total: 0.03, CacheSavings: 0.005}
+		priced[1].Trace.Cost = &evaluations.Cost{Agent: 0.01, Total: 0.01, UnpricedModels: []string{"mystery-model"}}
+
+		output := captureOutput(func() {
+			err := PrintStyledReport(priced, true)
+			assert.NoError(err)
+		})
+		plainOutput := stripANSI(output)
+
+		assert.Contains(plainOutput, "### Cost")
+		assert.Contains(plainOutput, "Total Cost:         $0.0400")
+		assert.Contains(plainOutput, "Avg Cost/Eval:      $0.0080")
+		assert.Contains(plainOutput, "Unpriced Models:    mystery-model")
+		assert.Contains(plainOutput, "| Cost: $0.0300")
 	})
 }
 
@@ -174,21 +195,22 @@ func TestBuildResultRow(t *testing.T) {
 	t.Run("successful eval with high score", func(t *testing.T) {
 		row := buildResultRow(results[0], styles)
 
-		assert.Len(row, 7)
+		assert.Len(row, 8)
 		assert.Equal("weather-forecast", row[0])
 		assert.Contains(row[1], "PASS")
 		assert.Equal("4.8", row[2])     // Average of 5,5,5,4,5
 		assert.Equal("3", row[3])       // Steps
 		assert.Equal("2", row[4])       // Tools
 		assert.Equal("100%", row[5])    // Success rate
-		assert.Contains(row[6], "1.2k") // Input tokens
-		assert.Contains(row[6], "552")  // Output tokens
+		assert.Equal("-", row[6])       // No expected tools
+		assert.Contains(row[7], "1.2k") // Input tokens
+		assert.Contains(row[7], "552")  // Output tokens
 	})
 
 	t.Run("failed eval with low score", func(t *testing.T) {
 		row := buildResultRow(results[2], styles)
 
-		assert.Len(row, 7)
+		assert.Len(row, 8)
 		assert.Equal("api-integration-test", row[0])
 		assert.Contains(row[1], "FAIL")
 		assert.Equal("1.6", row[2]) // Average of 1,2,2,2,1
@@ -197,7 +219,7 @@ func TestBuildResultRow(t *testing.T) {
 	t.Run("error case", func(t *testing.T) {
 		row := buildResultRow(results[3], styles)
 
-		assert.Len(row, 7)
+		assert.Len(row, 8)
 		assert.Equal("connection-timeout", row[0])
 		assert.Contains(row[1], "ERROR")
 		assert.Equal("-", row[2])
@@ -205,10 +227,59 @@ func TestBuildResultRow(t *testing.T) {
 		assert.Equal("-", row[4])
 	})
 
+	t.Run("timeout case", func(t *testing.T) {
+		row := buildResultRow(evaluations.EvalRunResult{
+			Eval:   evaluations.Eval{Name: "slow-eval"},
+			Status: evaluations.EvalStatusTimeout,
+			Trace:  &evaluations.EvalTrace{StepCount: 2, Status: evaluations.EvalStatusTimeout},
+		}, styles)
+
+		assert.Len(row, 8)
+		assert.Equal("slow-eval", row[0])
+		assert.Contains(row[1], "TIMEOUT")
+		assert.Equal("-", row[2])
+	})
+
+	t.Run("failed assertion case", func(t *testing.T) {
+		assertions := []evaluations.AssertionResult{
+			{Assertion: evaluations.Assertion{Type: evaluations.AssertionToolCalled, Tool: "add"}, Passed: false, Message: "add was not called"},
+		}
+		row := buildResultRow(evaluations.EvalRunResult{
+			Eval:       evaluations.Eval{Name: "assert-eval"},
+			Grade:      &evaluations.GradeResult{Accuracy: 5, Completeness: 5, Relevance: 5, Clarity: 5, Reasoning: 5},
+			Assertions: assertions,
+			Error:      evaluations.AssertionFailures(assertions),
+			Trace:      &evaluations.EvalTrace{StepCount: 1},
+		}, styles)
+
+		assert.Len(row, 8)
+		assert.Equal("assert-eval", row[0])
+		assert.Contains(row[1], "FAIL")
+		assert.Equal("5.0", row[2])
+	})
+
+	t.Run("tool trajectory case", func(t *testing.T) {
+		row := buildResultRow(evaluations.EvalRunResult{
+			Eval:  evaluations.Eval{Name: "trajectory-eval"},
+			Grade: &evaluations.GradeResult{Accuracy: 4, Completeness: 4, Relevance: 4, Clarity: 4, Reasoning: 4},
+			Trace: &evaluations.EvalTrace{
+				StepCount: 2,
+				ToolTrajectory: &evaluations.ToolTrajectoryResult{
+					Match: evaluations.ToolMatchSubset, Passed: false, Expected: 2, Actual: 2, Matched: 1,
+					Precision: 0.5, Recall: 0.5, Missing: []string{"list_teams"},
+				},
+			},
+		}, styles)
+
+		assert.Len(row, 8)
+		assert.Contains(row[1], "FAIL")
+		assert.Equal("0.50/0.50", row[6])
+	})
+
 	t.Run("no grade case", func(t *testing.T) {
 		row := buildResultRow(results[4], styles)
 
-		assert.Len(row, 7)
+		assert.Len(row, 8)
 		assert.Equal("simple-echo-test", row[0])
 		assert.Contains(row[1], "NO GRADE")
 		assert.Equal("-", row[2])
@@ -309,7 +380,15 @@ func TestFormatHelpers(t *testing.T) {
 		assert.Equal("100 → 50", formatTokenCounts(100, 50))
 	})
 
-	t.Run("avgScore", func(t *testing.T) {
+	t.Run("formatCost", func(t *testing.T) {
+		assert.Equal("$0.0123", formatCost(0.01234))
+		assert.Equal("$12.35", formatCost(12.345))
+		assert.Equal("$0.0150 (agent $0.0100, grading $0.0050), no price for mystery-model",
+			formatCostBreakdown(&evaluations.Cost{Agent: 0.01, Grading: 0.005, Total: 0.015, UnpricedModels: []string{"mystery-model"}}))
+		assert.Empty(formatCostBreakdown(nil))
+	})
+
+	t.Run("grade average", func(t *testing.T) {
 		grade := &evaluations.GradeResult{
 			Accuracy:     5,
 			Completeness: 4,
@@ -317,7 +396,7 @@ func TestFormatHelpers(t *testing.T) {
 			Clarity:      4,
 			Reasoning:    5,
 		}
-		assert.InDelta(4.6, avgScore(grade), 0.01)
+		assert.InDelta(4.6, grade.Average(), 0.01)
 	})
 }
 
@@ -339,3 +418,371 @@ func TestWrapText(t *testing.T) {
 		assert.Greater(len(lines), 1)
 	})
 }
+
+func TestTrialReport(t *testing.T) {
+	assert := require.New(t)
+
+	result, err := LoadTraceFile(filepath.Join("testdata", "flaky-search.json"))
+	assert.NoError(err)
+	assert.NoError(result.Error)
+	assert.NotNil(result.Aggregate)
+	assert.Len(result.Trials, 3)
+	assert.Equal("flaky-search", result.Trials[0].Eval.Name)
+	assert.Equal(2, result.Trials[1].Trace.StepCount)
+
+	styles := help.DefaultStyles()
+
+	t.Run("summary row", func(t *testing.T) {
+		row := buildResultRow(result, styles)
+
+		assert.Len(row, 8)
+		assert.Equal("flaky-search", row[0])
+		assert.Contains(row[1], "PASS 2/3")
+		assert.Equal("3.7±0.7", row[2])
+		assert.Equal("1.7", row[3])     // Mean steps
+		assert.Equal("0.7", row[4])     // Mean tools
+		assert.Equal("100%", row[5])    // Both tool calls succeeded
+		assert.Equal("-", row[6])       // No expected tools
+		assert.Contains(row[7], "2.2k") // Total input tokens
+	})
+
+	t.Run("failed aggregate row", func(t *testing.T) {
+		failed := result
+		aggregate := *result.Aggregate
+		aggregate.Passed = false
+		failed.Aggregate = &aggregate
+
+		row := buildResultRow(failed, styles)
+		assert.Contains(row[1], "FAIL 2/3")
+	})
+
+	t.Run("report", func(t *testing.T) {
+		output := captureOutput(func() {
+			err := PrintStyledReport([]evaluations.EvalRunResult{result}, true)
+			assert.NoError(err)
+		})
+
+		plainOutput := stripANSI(output)
+
+		assert.Contains(plainOutput, "### Trials")
+		assert.Contains(plainOutput, "Trial Pass Rate:    67% (2/3)")
+		assert.Contains(plainOutput, "✓ Pass:   1 (100%)")
+		assert.Contains(plainOutput, "Status: PASS (2/3 trials passed, rule: rate)")
+		assert.Contains(plainOutput, "pass@k:        1: 0.67  2: 1.00  3: 1.00")
+		assert.Contains(plainOutput, "pass^k:        1: 0.67  2: 0.33  3: 0.00")
+		assert.Contains(plainOutput, "#### Score Distribution (3 graded)")
+		assert.Contains(plainOutput, "Average:       3.73    0.74   2.8   4.6")
+		assert.Contains(plainOutput, "Trial 3: FAIL 2.8/5 (1 steps, 0 tools, 700ms)")
+	})
+}
+
+func TestNewResults(t *testing.T) {
+	assert := require.New(t)
+
+	results := loadTestFixtures(t)
+	trial, err := LoadTraceFile(filepath.Join("testdata", "flaky-search.json"))
+	assert.NoError(err)
+	results = append(results, trial)
+	results[0].Trace.Cost = &evaluations.Cost{Agent: 0.02, Grading: 0.01, Total: 0.03}
+	for i := range results[5].Trials {
+		results[5].Trials[i].Trace.Cost = &evaluations.Cost{Agent: 0.01, Total: 0.01}
+	}
+
+	info := NewRunInfo("evals.yaml", &evaluations.EvalConfig{
+		Model:     "test-model",
+		Grader:    &evaluations.GraderConfig{Provider: evaluations.ProviderOpenAI, Model: "judge-model", APIKey: "secret"},
+		MCPServer: evaluations.MCPServerConfig{Command: "./server", Env: []string{"TOKEN=secret"}},
+	})
+	doc := NewResults(info, results)
+
+	assert.Equal(ResultsVersion, doc.Version)
+	assert.Equal("anthropic", doc.Run.Provider)
+	assert.Equal("openai", doc.Run.GraderProvider)
+	assert.Equal("judge-model", doc.Run.GradingModel)
+	assert.Equal("stdio", doc.Run.MCPTransport)
+	assert.Equal("./server", doc.Run.MCPServer)
+
+	assert.Len(doc.Evals, 6)
+	statuses := make(map[string]string, len(doc.Evals))
+	for _, entry := range doc.Evals {
+		statuses[entry.Name] = entry.Status
+	}
+	assert.Equal(StatusPass, statuses["weather-forecast"])
+	assert.Equal(StatusFail, statuses["api-integration-test"])
+	assert.Equal(StatusError, statuses["connection-timeout"])
+	assert.Equal(StatusNoGrade, statuses["simple-echo-test"])
+	assert.Equal(StatusPass, statuses["flaky-search"])
+
+	assert.Equal(6, doc.Summary.Total)
+	assert.Equal(doc.Summary.Total, doc.Summary.Passed+doc.Summary.Failed+doc.Summary.Errors+doc.Summary.Timeouts+doc.Summary.NoGrade)
+
+	weather := doc.Evals[0]
+	assert.Equal(3, weather.Steps)
+	assert.Equal(2, weather.ToolCalls)
+	assert.Equal(0, weather.FailedToolCalls)
+	assert.NotNil(weather.AverageScore)
+	assert.InDelta(4.8, *weather.AverageScore, 1e-9)
+	assert.Positive(weather.DurationMS)
+	assert.Positive(weather.Usage.InputTokens)
+
+	errored := doc.Evals[3]
+	assert.NotEmpty(errored.Error)
+
+	flaky := doc.Evals[5]
+	assert.NotNil(flaky.Aggregate)
+	assert.Len(flaky.Trials, 3)
+	assert.Equal(StatusFail, flaky.Trials[2].Status)
+	assert.Equal(0, flaky.Steps)
+
+	assert.InDelta(0.03, weather.Cost.Total, 1e-9)
+	assert.InDelta(0.03, flaky.Cost.Total, 1e-9)
+	assert.Nil(errored.Cost)
+	assert.InDelta(0.06, doc.Summary.Cost.Total, 1e-9)
+	assert.InDelta(0.01, doc.Summary.Cost.Grading, 1e-9)
+
+	var buf bytes.Buffer
+	assert.NoError(WriteJSON(&buf, doc))
+	assert.Contains(buf.String(), `"version": 1`)
+	assert.Contains(buf.String(), `"status": "error"`)
+	assert.NotContains(buf.String(), "secret")
+}
+
+func TestWriteJUnit(t *testing.T) {
+	assert := require.New(t)
+
+	results := loadTestFixtures(t)
+	trial, err := LoadTraceFile(filepath.Join("testdata", "flaky-search.json"))
+	assert.NoError(err)
+	results = append(results, trial)
+
+	info := RunInfo{ConfigPath: "examples/evals.yaml", Model: "test-model"}
+
+	var buf bytes.Buffer
+	assert.NoError(WriteJUnit(&buf, info, results))
+	assert.True(strings.HasPrefix(buf.String(), xml.Header))
+
+	var doc junitTestSuites
+	assert.NoError(xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(6, doc.Tests)
+	assert.Len(doc.Suites, 1)
+
+	suite := doc.Suites[0]
+	assert.Equal("evals", suite.Name)
+	assert.NotNil(suite.Properties)
+	assert.Contains(suite.Properties.Properties, junitProperty{Name: "model", Value: "test-model"})
+	assert.Len(suite.TestCases, 6)
+
+	cases := make(map[string]junitTestCase, len(suite.TestCases))
+	for _, tc := range suite.TestCases {
+		cases[tc.Name] = tc
+	}
+
+	weather := cases["weather-forecast"]
+	assert.Nil(weather.Failure)
+	assert.Nil(weather.Error)
+	assert.Equal(junitSeconds(results[0].Trace.TotalDuration), weather.Time)
+	assert.Contains(weather.SystemOut, "Average: 4.8")
+
+	failed := cases["api-integration-test"]
+	assert.NotNil(failed.Failure)
+	assert.Equal(StatusFail, failed.Failure.Type)
+	assert.Equal("average score 1.6 is below 3.0", failed.Failure.Message)
+	assert.Contains(failed.SystemOut, "Failed to properly authenticate")
+
+	errored := cases["connection-timeout"]
+	assert.NotNil(errored.Error)
+	assert.Equal(results[3].Error.Error(), errored.Error.Message)
+
+	flaky := cases["flaky-search"]
+	assert.Nil(flaky.Failure)
+	assert.Equal(junitSeconds(resultDuration(trial)), flaky.Time)
+	assert.Contains(flaky.SystemOut, "2 of 3 trials passed")
+	assert.Contains(flaky.SystemOut, "Trial 3: fail")
+}
+
+func TestFailureMessage_MinimumScores(t *testing.T) {
+	assert := require.New(t)
+
+	result := evaluations.EvalRunResult{
+		Eval: evaluations.Eval{
+			Name:          "rubric",
+			GradingRubric: &evaluations.GradingRubric{MinimumScores: map[string]int{"accuracy": 3}},
+		},
+		Grade: &evaluations.GradeResult{Accuracy: 2, Completeness: 2, Relevance: 2, Clarity: 2, Reasoning: 2},
+	}
+	assert.Equal("average score 2.0 is below 3.0; eval failed minimum score requirements: accuracy: got 2, required 3. Review grading criteria or adjust rubric thresholds", failureMessage(result))
+
+	result.Error = errors.New("assertion failed")
+	assert.Equal("assertion failed", failureMessage(result))
+}
+
+func TestWriteTAP(t *testing.T) {
+	assert := require.New(t)
+
+	results := loadTestFixtures(t)
+
+	var buf bytes.Buffer
+	assert.NoError(WriteTAP(&buf, RunInfo{}, results))
+
+	out := buf.String()
+	assert.True(strings.HasPrefix(out, "TAP version 13\n1..5\n"))
+	assert.Contains(out, "ok 1 - weather-forecast\n  ---\n  status: pass\n")
+	assert.Contains(out, "not ok 3 - api-integration-test\n  ---\n  status: fail\n  message: average score 1.6 is below 3.0\n")
+	assert.Contains(out, "not ok 4 - connection-timeout\n")
+	assert.Contains(out, "ok 5 - simple-echo-test\n  ---\n  status: no_grade\n")
+	assert.Equal(5, strings.Count(out, "  ...\n"))
+}
+
+func TestNewRenderer(t *testing.T) {
+	assert := require.New(t)
+
+	for _, format := range RenderFormats() {
+		renderer, err := NewRenderer(format, true)
+		assert.NoError(err)
+		assert.NotNil(renderer)
+	}
+
+	_, err := NewRenderer("pdf", false)
+	assert.EqualError(err, "invalid report format 'pdf': must be one of: text, markdown, html")
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	assert := require.New(t)
+
+	results := loadTestFixtures(t)
+	trial, err := LoadTraceFile(filepath.Join("testdata", "flaky-search.json"))
+	assert.NoError(err)
+	results = append(results, trial)
+	results[0].Trace.Steps[0].Thinking = "Need the forecast first.\nThen summarize it."
+	results[0].Trace.Cost = &evaluations.Cost{Agent: 0.02, Grading: 0.01, Total: 0.03}
+
+	var buf bytes.Buffer
+	assert.NoError(MarkdownRenderer{}.Render(&buf, results))
+	out := buf.String()
+
+	assert.True(strings.HasPrefix(out, "# Evaluation Summary\n\n**6 evals:** 3 passed, 1 failed, 1 errors, 0 timeouts, 1 no grade\n"))
+	assert.NotContains(out, "\x1b[")
+	assert.Contains(out, "| weather-forecast | ✅ PASS | 4.8 | 3 | 2 | 100% | - | 1.2k → 552 |\n")
+	assert.Contains(out, "| api-integration-test | ❌ FAIL | 1.6 |")
+	assert.Contains(out, "| flaky-search | ✅ PASS 2/3 | 3.7±0.7 |")
+	assert.Equal(6, strings.Count(out, "<details>"))
+	assert.Contains(out, "<summary><b>api-integration-test</b>: ❌ FAIL 1.6/5</summary>")
+	assert.Contains(out, "**Error:** average score 1.6 is below 3.0")
+	assert.Contains(out, "   - ❌ `api_authenticate` (500ms): invalid credentials\n")
+	assert.Contains(out, "**pass@k:** 1: 0.67  2: 1.00  3: 1.00")
+	assert.Contains(out, "   > Need the forecast first.\n   > Then summarize it.\n")
+	assert.Contains(out, "**Cost:** $0.0300 (agent $0.0200, grading $0.0100)\n")
+	assert.Contains(out, ", cost $0.0300 (agent $0.0200, grading $0.0100)\n")
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	assert := require.New(t)
+
+	results := loadTestFixtures(t)
+	results[0].Eval.Name = "weather <forecast>"
+	results[0].Trace.Steps[0].ToolCalls[0].Input = []byte(`{"city":"San Francisco"}`)
+	results[0].Trace.Grading = &evaluations.GradingTrace{Model: "judge-model", GradingPrompt: "Grade this answer"}
+	results[0].Trace.Steps[0].Thinking = "Need the forecast first."
+
+	var buf bytes.Buffer
+	assert.NoError(HTMLRenderer{}.Render(&buf, results))
+	out := buf.String()
+
+	assert.True(strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Contains(out, "<style>")
+	assert.NotContains(out, "<script")
+	assert.NotContains(out, "weather <forecast>")
+	assert.Contains(out, "weather &lt;forecast&gt;")
+	assert.Contains(out, `<td><a href="#eval-1">weather &lt;forecast&gt;</a></td>`)
+	assert.Contains(out, `<details class="eval" id="eval-1">`)
+	assert.Contains(out, `<td class="status fail">FAIL</td>`)
+	assert.Contains(out, "<code>get_forecast</code>")
+	assert.Contains(out, "<p><b>Input</b></p><pre>{\n  &#34;city&#34;: &#34;San Francisco&#34;\n}</pre>")
+	assert.Equal(1, strings.Count(out, "<p><b>Input</b></p>"))
+	assert.Contains(out, "<p><b>Grading prompt</b></p><pre>Grade this answer</pre>")
+	assert.Contains(out, "<p><b>Thinking</b></p><pre>Need the forecast first.</pre>")
+	assert.Contains(out, "judge-model")
+	assert.Contains(out, "➖ NO GRADE")
+	assert.Contains(out, "invalid credentials")
+}
+
+func TestBudgetExceededReport(t *testing.T) {
+	assert := require.New(t)
+
+	results := []evaluations.EvalRunResult{{
+		Eval:   evaluations.Eval{Name: "runaway"},
+		Status: evaluations.EvalStatusBudgetExceeded,
+		Error:  fmt.Errorf("eval %w: max_tokens 50 reached (60 used)", evaluations.ErrBudgetExceeded),
+		Trace:  &evaluations.EvalTrace{Status: evaluations.EvalStatusBudgetExceeded, StepCount: 1},
+	}}
+
+	doc := NewResults(RunInfo{}, results)
+	assert.Equal(StatusBudgetExceeded, doc.Evals[0].Status)
+	assert.Equal(1, doc.Summary.BudgetExceeded)
+	assert.Equal(0, doc.Summary.Errors)
+
+	var buf bytes.Buffer
+	assert.NoError(MarkdownRenderer{}.Render(&buf, results))
+	assert.Contains(buf.String(), "0 no grade, 1 over budget\n")
+	assert.Contains(buf.String(), "| runaway | ⚠️ BUDGET |")
+
+	output := stripANSI(captureOutput(func() {
+		assert.NoError(PrintStyledReport(results, true))
+	}))
+	assert.Contains(output, "Over Budget: 1 (100%)")
+	assert.Contains(output, "Status: BUDGET EXCEEDED")
+	assert.Contains(output, "max_tokens 50 reached (60 used)")
+}
+
+func TestMinimumScoreFailureReport(t *testing.T) {
+	assert := require.New(t)
+
+	eval := evaluations.Eval{
+		Name:          "rubric",
+		GradingRubric: &evaluations.GradingRubric{MinimumScores: map[string]int{"accuracy": 3}},
+	}
+	grade := &evaluations.GradeResult{Accuracy: 2, Completeness: 5, Relevance: 5, Clarity: 5, Reasoning: 5}
+	scoreErr := eval.GradingRubric.CheckMinimumScores(grade)
+	assert.Error(scoreErr)
+
+	results := []evaluations.EvalRunResult{{
+		Eval:  eval,
+		Grade: grade,
+		Error: scoreErr,
+		Trace: &evaluations.EvalTrace{StepCount: 1},
+	}}
+
+	doc := NewResults(RunInfo{}, results)
+	assert.Equal(StatusFail, doc.Evals[0].Status)
+	assert.Equal(1, doc.Summary.Failed)
+	assert.Equal(0, doc.Summary.Errors)
+
+	var buf bytes.Buffer
+	assert.NoError(WriteJUnit(&buf, RunInfo{}, results))
+	var junit junitTestSuites
+	assert.NoError(xml.Unmarshal(buf.Bytes(), &junit))
+	testCase := junit.Suites[0].TestCases[0]
+	assert.Nil(testCase.Error)
+	assert.NotNil(testCase.Failure)
+	assert.Equal(scoreErr.Error(), testCase.Failure.Message)
+	assert.Equal(1, junit.Suites[0].Failures)
+	assert.Equal(0, junit.Suites[0].Errors)
+
+	buf.Reset()
+	assert.NoError(WriteTAP(&buf, RunInfo{}, results))
+	assert.Contains(buf.String(), "not ok 1 - rubric\n  ---\n  status: fail\n")
+
+	output := stripANSI(captureOutput(func() {
+		assert.NoError(PrintStyledReport(results, true))
+	}))
+	assert.Contains(output, "Status: FAIL (4.4/5)")
+	assert.Contains(output, "accuracy: got 2, required 3")
+	assert.NotContains(output, "ERROR")
+}
+
+func TestCodeBlock(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal("```text\nhello\n```\n", codeBlock("text", "hello\n"))
+	assert.Equal("````\nuse ```go\n````\n", codeBlock("", "use ```go"))
+}
@@ -0,0 +1,53 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+)
+
+// Reporter writes the results of a run in a machine-readable format
+type Reporter interface {
+	// Write writes every result to w, info describes the run that produced them
+	Write(w io.Writer, info RunInfo, results []evaluations.EvalRunResult) error
+}
+
+// ReporterFunc adapts a function to the Reporter interface
+type ReporterFunc func(w io.Writer, info RunInfo, results []evaluations.EvalRunResult) error
+
+func (f ReporterFunc) Write(w io.Writer, info RunInfo, results []evaluations.EvalRunResult) error {
+	return f(w, info, results)
+}
+
+// reporters lists the supported formats in the order they are documented
+var reporters = []struct {
+	format   string
+	reporter Reporter
+}{
+	{"json", ReporterFunc(func(w io.Writer, info RunInfo, results []evaluations.EvalRunResult) error {
+		return WriteJSON(w, NewResults(info, results))
+	})},
+	{"junit", ReporterFunc(WriteJUnit)},
+	{"tap", ReporterFunc(WriteTAP)},
+}
+
+// NewReporter returns the reporter for a format name such as json, junit or tap
+func NewReporter(format string) (Reporter, error) {
+	for _, r := range reporters {
+		if r.format == format {
+			return r.reporter, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid output format '%s': must be one of: %s", format, strings.Join(Formats(), ", "))
+}
+
+// Formats returns the names of the supported output formats
+func Formats() []string {
+	formats := make([]string, len(reporters))
+	for i, r := range reporters {
+		formats[i] = r.format
+	}
+	return formats
+}
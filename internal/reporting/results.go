@@ -0,0 +1,316 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+)
+
+// ResultsVersion is the version of the results document format. It is incremented
+// when a field is removed or changes meaning; new fields may be added at any time.
+const ResultsVersion = 1
+
+// Eval statuses used in the results document
+const (
+	StatusPass    = "pass"     // Graded at or above the passing score and every deterministic check passed
+	StatusFail    = "fail"     // Graded below the passing score, a deterministic check failed or the trials failed their policy
+	StatusError   = "error"    // The eval could not run or be graded
+	StatusTimeout = "timeout"  // The eval or suite deadline was exceeded
+	StatusNoGrade = "no_grade" // The eval completed without a grade
+
+	StatusBudgetExceeded = "budget_exceeded" // The eval or suite budget ran out before the eval finished
+)
+
+// Results is the machine-readable document describing a complete run
+type Results struct {
+	Version int            `json:"version"`
+	Run     RunInfo        `json:"run"`
+	Summary ResultsSummary `json:"summary"`
+	Evals   []EvalEntry    `json:"evals"`
+}
+
+// RunInfo records the configuration and timing of a run. Secrets such as API keys,
+// MCP server environment variables and headers are never included.
+type RunInfo struct {
+	ConfigPath     string    `json:"config_path,omitempty"`
+	Provider       string    `json:"provider"`
+	Model          string    `json:"model"`
+	GraderProvider string    `json:"grader_provider"`
+	GradingModel   string    `json:"grading_model"`
+	MCPTransport   string    `json:"mcp_transport"`
+	MCPServer      string    `json:"mcp_server"` // Command for stdio, URL for the HTTP transports
+	Trials         int       `json:"trials,omitempty"`
+	Timeout        string    `json:"timeout,omitempty"`
+	SuiteTimeout   string    `json:"suite_timeout,omitempty"`
+	Filter         string    `json:"filter,omitempty"`
+	Exclude        string    `json:"exclude,omitempty"`
+	Tags           []string  `json:"tags,omitempty"`
+	ExcludeTags    []string  `json:"exclude_tags,omitempty"`
+	Select         string    `json:"select,omitempty"`
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+}
+
+// NewRunInfo describes a run of config, resolving the defaults the client applies
+func NewRunInfo(configPath string, config *evaluations.EvalConfig) RunInfo {
+	info := RunInfo{
+		ConfigPath:   configPath,
+		Provider:     string(config.Provider),
+		Model:        config.Model,
+		GradingModel: config.GradingModel,
+		MCPTransport: string(config.MCPServer.Transport),
+		MCPServer:    config.MCPServer.Command,
+		Trials:       config.Trials,
+		Timeout:      config.Timeout,
+		SuiteTimeout: config.SuiteTimeout,
+	}
+
+	if info.Provider == "" {
+		info.Provider = string(evaluations.ProviderAnthropic)
+	}
+	info.GraderProvider = info.Provider
+	if info.GradingModel == "" {
+		info.GradingModel = config.Model
+	}
+	if grader := config.Grader; grader != nil {
+		if grader.Provider != "" {
+			info.GraderProvider = string(grader.Provider)
+		}
+		if grader.Model != "" {
+			info.GradingModel = grader.Model
+		}
+	}
+
+	if info.MCPTransport == "" {
+		info.MCPTransport = string(evaluations.TransportStdio)
+	}
+	if info.MCPTransport != string(evaluations.TransportStdio) {
+		info.MCPServer = config.MCPServer.URL
+	}
+
+	return info
+}
+
+// ResultsSummary totals the evals of a run by status along with their usage
+type ResultsSummary struct {
+	Total          int               `json:"total"`
+	Passed         int               `json:"passed"`
+	Failed         int               `json:"failed"`
+	Errors         int               `json:"errors"`
+	Timeouts       int               `json:"timeouts"`
+	BudgetExceeded int               `json:"budget_exceeded"`
+	NoGrade        int               `json:"no_grade"`
+	DurationMS     int64             `json:"duration_ms"`    // Sum of eval durations, which exceeds the wall time of a parallel run
+	Usage          TokenUsage        `json:"usage"`          // Agent and grading tokens across every eval and trial
+	Cost           *evaluations.Cost `json:"cost,omitempty"` // USD cost across every eval and trial, when the traces were priced
+}
+
+// TokenUsage records the model tokens consumed
+type TokenUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+func (u *TokenUsage) add(other TokenUsage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheCreationInputTokens += other.CacheCreationInputTokens
+	u.CacheReadInputTokens += other.CacheReadInputTokens
+}
+
+// EvalEntry is the outcome of a single eval. For evals with several trials the
+// trace derived fields are left empty and each trial is listed in Trials.
+type EvalEntry struct {
+	Name            string                            `json:"name"`
+	Description     string                            `json:"description,omitempty"`
+	Tags            []string                          `json:"tags,omitempty"`
+	Status          string                            `json:"status"`
+	Error           string                            `json:"error,omitempty"`
+	Scores          *evaluations.GradeResult          `json:"scores,omitempty"`
+	AverageScore    *float64                          `json:"average_score,omitempty"`
+	Assertions      []evaluations.AssertionResult     `json:"assertions,omitempty"`
+	ToolTrajectory  *evaluations.ToolTrajectoryResult `json:"tool_trajectory,omitempty"`
+	Steps           int                               `json:"steps"`
+	ToolCalls       int                               `json:"tool_calls"`
+	FailedToolCalls int                               `json:"failed_tool_calls"`
+	Retries         int                               `json:"retries"`
+	DurationMS      int64                             `json:"duration_ms"`
+	Usage           TokenUsage                        `json:"usage"`                   // Agent steps; cache totals include grading
+	GradingUsage    *TokenUsage                       `json:"grading_usage,omitempty"` // The grading request, when the eval was graded
+	Cost            *evaluations.Cost                 `json:"cost,omitempty"`          // USD cost, summed over the trials of an eval that ran several
+	Aggregate       *evaluations.TrialAggregate       `json:"aggregate,omitempty"`
+	Trials          []EvalEntry                       `json:"trials,omitempty"`
+}
+
+// NewResults builds the results document for a run
+func NewResults(info RunInfo, results []evaluations.EvalRunResult) Results {
+	doc := Results{
+		Version: ResultsVersion,
+		Run:     info,
+		Evals:   make([]EvalEntry, 0, len(results)),
+	}
+
+	for _, result := range results {
+		entry := newEvalEntry(result)
+		doc.Evals = append(doc.Evals, entry)
+
+		doc.Summary.Total++
+		switch entry.Status {
+		case StatusPass:
+			doc.Summary.Passed++
+		case StatusFail:
+			doc.Summary.Failed++
+		case StatusError:
+			doc.Summary.Errors++
+		case StatusTimeout:
+			doc.Summary.Timeouts++
+		case StatusBudgetExceeded:
+			doc.Summary.BudgetExceeded++
+		case StatusNoGrade:
+			doc.Summary.NoGrade++
+		}
+
+		runs := []EvalEntry{entry}
+		if len(entry.Trials) > 0 {
+			runs = entry.Trials
+		}
+		for _, run := range runs {
+			doc.Summary.DurationMS += run.DurationMS
+			doc.Summary.Usage.add(run.Usage)
+			if run.GradingUsage != nil {
+				doc.Summary.Usage.InputTokens += run.GradingUsage.InputTokens
+				doc.Summary.Usage.OutputTokens += run.GradingUsage.OutputTokens
+			}
+		}
+		doc.Summary.Cost = doc.Summary.Cost.Add(entry.Cost)
+	}
+
+	return doc
+}
+
+// WriteJSON writes the results document as indented JSON
+func WriteJSON(w io.Writer, doc Results) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+	return nil
+}
+
+func newEvalEntry(result evaluations.EvalRunResult) EvalEntry {
+	entry := EvalEntry{
+		Name:        result.Eval.Name,
+		Description: result.Eval.Description,
+		Tags:        result.Eval.Tags,
+		Status:      resultStatus(result),
+		Scores:      result.Grade,
+		Assertions:  result.Assertions,
+		Aggregate:   result.Aggregate,
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+	if result.Grade != nil {
+		avg := result.Grade.Average()
+		entry.AverageScore = &avg
+	}
+
+	for _, trial := range result.Trials {
+		entry.Trials = append(entry.Trials, newEvalEntry(trial))
+	}
+	entry.Cost = resultCost(result)
+
+	if trace := result.Trace; trace != nil {
+		entry.ToolTrajectory = trace.ToolTrajectory
+		entry.Steps = trace.StepCount
+		entry.ToolCalls = trace.ToolCallCount
+		entry.FailedToolCalls = trace.ToolCallCount - countSuccessfulToolCalls(trace)
+		entry.Retries, _ = calculateRetries(trace)
+		entry.DurationMS = trace.TotalDuration.Milliseconds()
+		entry.Usage = TokenUsage{
+			InputTokens:              trace.TotalInputTokens,
+			OutputTokens:             trace.TotalOutputTokens,
+			CacheCreationInputTokens: trace.TotalCacheCreationTokens,
+			CacheReadInputTokens:     trace.TotalCacheReadTokens,
+		}
+		if grading := trace.Grading; grading != nil {
+			entry.GradingUsage = &TokenUsage{
+				InputTokens:              grading.InputTokens,
+				OutputTokens:             grading.OutputTokens,
+				CacheCreationInputTokens: grading.CacheCreationInputTokens,
+				CacheReadInputTokens:     grading.CacheReadInputTokens,
+			}
+		}
+	}
+
+	return entry
+}
+
+// resultCost returns the cost of a result, summing the trials of an eval that ran several.
+// It is nil when the traces carry no cost.
+func resultCost(result evaluations.EvalRunResult) *evaluations.Cost {
+	if len(result.Trials) > 0 {
+		var total *evaluations.Cost
+		for _, trial := range result.Trials {
+			total = total.Add(resultCost(trial))
+		}
+		return total
+	}
+	if result.Trace == nil {
+		return nil
+	}
+	return result.Trace.Cost
+}
+
+// formatCost formats a USD amount, with more precision for amounts under a dollar
+func formatCost(usd float64) string {
+	if usd < 1 {
+		return fmt.Sprintf("$%.4f", usd)
+	}
+	return fmt.Sprintf("$%.2f", usd)
+}
+
+// formatCostBreakdown formats a cost with its agent and grading parts
+func formatCostBreakdown(cost *evaluations.Cost) string {
+	if cost == nil {
+		return ""
+	}
+	out := fmt.Sprintf("%s (agent %s, grading %s)", formatCost(cost.Total), formatCost(cost.Agent), formatCost(cost.Grading))
+	if len(cost.UnpricedModels) > 0 {
+		out += fmt.Sprintf(", no price for %s", strings.Join(cost.UnpricedModels, ", "))
+	}
+	return out
+}
+
+// resultStatus classifies a result the same way as the summary table
+func resultStatus(result evaluations.EvalRunResult) string {
+	switch {
+	case result.Aggregate != nil:
+		if result.Aggregate.Passed {
+			return StatusPass
+		}
+		return StatusFail
+	case result.Status == evaluations.EvalStatusTimeout:
+		return StatusTimeout
+	case result.Status == evaluations.EvalStatusBudgetExceeded:
+		return StatusBudgetExceeded
+	case hasRunError(result):
+		return StatusError
+	case hasFailedChecks(result), result.Error != nil:
+		return StatusFail
+	case result.Grade != nil:
+		if result.Grade.Average() >= evaluations.PassingScore {
+			return StatusPass
+		}
+		return StatusFail
+	default:
+		return StatusNoGrade
+	}
+}
@@ -0,0 +1,77 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	evaluations "github.com/wolfeidau/mcp-evals"
+	"gopkg.in/yaml.v3"
+)
+
+// tapDiagnostic is the YAML block written below each TAP test line
+type tapDiagnostic struct {
+	Status       string   `yaml:"status"`
+	Message      string   `yaml:"message,omitempty"`
+	AverageScore *float64 `yaml:"average_score,omitempty"`
+	Passes       string   `yaml:"passes,omitempty"` // "k/n" for evals with trials
+	DurationMS   int64    `yaml:"duration_ms"`
+	Comment      string   `yaml:"comment,omitempty"`
+}
+
+// WriteTAP writes the results as a TAP version 13 stream with one test per eval.
+// Evals that pass or completed without a grade are ok; each test is followed by
+// a YAML diagnostic block with its status, score and failure message.
+func WriteTAP(w io.Writer, info RunInfo, results []evaluations.EvalRunResult) error {
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(results))
+
+	for i, result := range results {
+		status := resultStatus(result)
+		ok := "ok"
+		if status != StatusPass && status != StatusNoGrade {
+			ok = "not ok"
+		}
+		fmt.Fprintf(&b, "%s %d - %s\n", ok, i+1, tapDescription(result.Eval.Name))
+
+		diag := tapDiagnostic{
+			Status:     status,
+			DurationMS: resultDuration(result).Milliseconds(),
+		}
+		if ok == "not ok" {
+			diag.Message = failureMessage(result)
+		}
+		if result.Grade != nil {
+			avg := result.Grade.Average()
+			diag.AverageScore = &avg
+			diag.Comment = result.Grade.OverallComment
+		}
+		if agg := result.Aggregate; agg != nil {
+			diag.Passes = fmt.Sprintf("%d/%d", agg.Passes, agg.Trials)
+			if agg.Scores != nil {
+				mean := agg.Scores.Average.Mean
+				diag.AverageScore = &mean
+			}
+		}
+
+		data, err := yaml.Marshal(diag)
+		if err != nil {
+			return fmt.Errorf("failed to encode tap diagnostics: %w", err)
+		}
+		b.WriteString("  ---\n")
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			b.WriteString("  " + line + "\n")
+		}
+		b.WriteString("  ...\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// tapDescription keeps a test description on one line and escapes the directive marker
+func tapDescription(name string) string {
+	name = strings.ReplaceAll(name, "\n", " ")
+	return strings.ReplaceAll(name, "#", `\#`)
+}
@@ -0,0 +1,331 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// Root is a location the eval client shares with the MCP server through roots/list
+type Root struct {
+	URI  string `yaml:"uri" json:"uri" jsonschema:"URI of the root, e.g. file:///home/user/project"`
+	Name string `yaml:"name,omitempty" json:"name,omitempty" jsonschema:"Optional display name for the root"`
+}
+
+// ElicitAction is the answer to an elicitation request
+type ElicitAction string
+
+const (
+	ElicitAccept  ElicitAction = "accept"  // Submit Content as the user's answer
+	ElicitDecline ElicitAction = "decline" // The user declined to answer
+	ElicitCancel  ElicitAction = "cancel"  // The user dismissed the request
+)
+
+var elicitActions = []ElicitAction{ElicitAccept, ElicitDecline, ElicitCancel}
+
+// ElicitationResponse is a scripted answer to an elicitation request from the MCP server.
+// Each response answers at most one request; requests without a matching response are declined.
+type ElicitationResponse struct {
+	Match   string         `yaml:"match,omitempty" json:"match,omitempty" jsonschema:"Regular expression matched against the elicitation message; empty matches any request"`
+	Action  ElicitAction   `yaml:"action,omitempty" json:"action,omitempty" jsonschema:"How to answer: accept (default), decline or cancel"`
+	Content map[string]any `yaml:"content,omitempty" json:"content,omitempty" jsonschema:"Form values returned when the action is accept"`
+}
+
+// Validate checks that the action is known and the match pattern compiles
+func (r ElicitationResponse) Validate() error {
+	switch r.Action {
+	case "", ElicitAccept:
+	case ElicitDecline, ElicitCancel:
+		if len(r.Content) > 0 {
+			return fmt.Errorf("content is only allowed with action accept")
+		}
+	default:
+		return fmt.Errorf("invalid action '%s': must be one of: %s", r.Action, joinEnum(elicitActions))
+	}
+	if _, err := regexp.Compile(r.Match); err != nil {
+		return fmt.Errorf("invalid match pattern: %w", err)
+	}
+	return nil
+}
+
+// ServerRequest records a request the MCP server sent to the eval client, such as
+// sampling/createMessage, elicitation/create or roots/list
+type ServerRequest struct {
+	Method       string          `json:"method"`                  // MCP method name
+	StartTime    time.Time       `json:"start_time"`              // When the request arrived
+	Duration     time.Duration   `json:"duration"`                // How long the client took to answer
+	Params       json.RawMessage `json:"params,omitempty"`        // Request parameters as JSON
+	Result       json.RawMessage `json:"result,omitempty"`        // Response sent to the server as JSON
+	Error        string          `json:"error,omitempty"`         // Error returned to the server, if any
+	Model        string          `json:"model,omitempty"`         // Model that answered a sampling request
+	InputTokens  int             `json:"input_tokens,omitempty"`  // Input tokens used by a sampling request
+	OutputTokens int             `json:"output_tokens,omitempty"` // Output tokens used by a sampling request
+}
+
+// requestRouter answers the requests an MCP server makes back to the eval client and
+// records each one on the eval run that caused it. A pooled session serves many runs,
+// so runs attach to the router while they use the session.
+type requestRouter struct {
+	ec *EvalClient
+
+	mu       sync.Mutex
+	handlers []*serverRequestHandler // attached runs, in the order they attached
+	warnOnce sync.Once
+}
+
+func newRequestRouter(ec *EvalClient) *requestRouter {
+	return &requestRouter{ec: ec}
+}
+
+func (r *requestRouter) attach(h *serverRequestHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, h)
+}
+
+func (r *requestRouter) detach(h *serverRequestHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, handler := range r.handlers {
+		if handler == h {
+			r.handlers = append(r.handlers[:i], r.handlers[i+1:]...)
+			return
+		}
+	}
+}
+
+// current returns the runs a server request may belong to. Servers make requests while
+// handling a tool call, so the runs with a tool call in flight are preferred; more than
+// one means the request can't be attributed, which happens when runs share a session.
+func (r *requestRouter) current() []*serverRequestHandler {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var active []*serverRequestHandler
+	for _, h := range r.handlers {
+		if h.inFlight.Load() > 0 {
+			active = append(active, h)
+		}
+	}
+	if len(active) > 0 {
+		return active
+	}
+	return slices.Clone(r.handlers)
+}
+
+// clientOptions advertises the sampling and elicitation capabilities
+func (r *requestRouter) clientOptions() *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		CreateMessageHandler: r.createMessage,
+		ElicitationHandler:   r.elicit,
+	}
+}
+
+type serverRequestKey struct{}
+
+// serverRequestContext is passed to the request handlers through the context
+type serverRequestContext struct {
+	event   *ServerRequest
+	handler *serverRequestHandler
+}
+
+// middleware records every request from the server, notifications and pings aside
+func (r *requestRouter) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method == "ping" || strings.HasPrefix(method, "notifications/") {
+			return next(ctx, method, req)
+		}
+
+		candidates := r.current()
+		rc := &serverRequestContext{
+			event: &ServerRequest{Method: method, StartTime: time.Now()},
+		}
+		if len(candidates) > 0 {
+			rc.handler = candidates[0]
+		}
+		if params, err := json.Marshal(req.GetParams()); err == nil {
+			rc.event.Params = params
+		}
+
+		// Sampling and elicitation are answered for a specific run, so refuse them rather
+		// than answer with another run's model budget or script
+		if len(candidates) > 1 && (method == "sampling/createMessage" || method == "elicitation/create") {
+			r.warnOnce.Do(func() {
+				log.Warn().
+					Str("method", method).
+					Int("runs", len(candidates)).
+					Msg("Refusing server request made while several evals share the MCP session, use session_mode per_eval or per_worker with parallel evals")
+			})
+			err := fmt.Errorf("%s refused: %d eval runs share the session and the request can't be attributed to one of them", method, len(candidates))
+			rc.event.Duration = time.Since(rc.event.StartTime)
+			rc.event.Error = err.Error()
+			for _, h := range candidates {
+				h.record(*rc.event)
+			}
+			return nil, err
+		}
+
+		result, err := next(context.WithValue(ctx, serverRequestKey{}, rc), method, req)
+
+		rc.event.Duration = time.Since(rc.event.StartTime)
+		if err != nil {
+			rc.event.Error = err.Error()
+		} else if data, marshalErr := json.Marshal(result); marshalErr == nil {
+			rc.event.Result = data
+		}
+		if rc.handler != nil {
+			rc.handler.record(*rc.event)
+		}
+
+		return result, err
+	}
+}
+
+// createMessage answers a sampling request with the model under evaluation
+func (r *requestRouter) createMessage(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	params := req.Params
+	ec := r.ec
+
+	messages := make([]Message, 0, len(params.Messages))
+	for _, message := range params.Messages {
+		text, _ := flattenContent([]mcp.Content{message.Content})
+		role := RoleUser
+		if message.Role == "assistant" {
+			role = RoleAssistant
+		}
+		messages = append(messages, Message{Role: role, Content: []ContentBlock{TextBlock(text)}})
+	}
+
+	maxTokens := ec.config.MaxTokens
+	if params.MaxTokens > 0 && int(params.MaxTokens) < maxTokens {
+		maxTokens = int(params.MaxTokens)
+	}
+	providerReq := ProviderRequest{
+		Model:     ec.config.Model,
+		System:    params.SystemPrompt,
+		Messages:  messages,
+		MaxTokens: maxTokens,
+	}
+	if params.Temperature > 0 {
+		providerReq.Temperature = &params.Temperature
+	}
+
+	var resp *ProviderResponse
+	_, err := ec.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = ec.provider.CreateMessage(ctx, providerReq)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sampling request failed: %w", err)
+	}
+
+	if rc, ok := ctx.Value(serverRequestKey{}).(*serverRequestContext); ok {
+		rc.event.Model = ec.config.Model
+		rc.event.InputTokens = resp.Usage.InputTokens + resp.Usage.CacheCreationInputTokens + resp.Usage.CacheReadInputTokens
+		rc.event.OutputTokens = resp.Usage.OutputTokens
+	}
+
+	stopReason := string(resp.StopReason)
+	switch resp.StopReason {
+	case StopReasonEndTurn:
+		stopReason = "endTurn"
+	case StopReasonMaxTokens:
+		stopReason = "maxTokens"
+	}
+
+	return &mcp.CreateMessageResult{
+		Content:    &mcp.TextContent{Text: resp.Text()},
+		Model:      ec.config.Model,
+		Role:       "assistant",
+		StopReason: stopReason,
+	}, nil
+}
+
+// elicit answers an elicitation request from the script of the run it belongs to
+func (r *requestRouter) elicit(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	if rc, ok := ctx.Value(serverRequestKey{}).(*serverRequestContext); ok && rc.handler != nil {
+		return rc.handler.answerElicitation(req.Params.Message), nil
+	}
+	return &mcp.ElicitResult{Action: string(ElicitDecline)}, nil
+}
+
+// serverRequestHandler holds one eval run's elicitation script and the server requests
+// recorded for it
+type serverRequestHandler struct {
+	elicitation []ElicitationResponse
+	inFlight    atomic.Int32 // tool calls in progress
+
+	mu       sync.Mutex
+	used     []bool
+	requests []ServerRequest
+}
+
+func newServerRequestHandler(eval Eval) *serverRequestHandler {
+	return &serverRequestHandler{
+		elicitation: eval.Elicitation,
+		used:        make([]bool, len(eval.Elicitation)),
+	}
+}
+
+// Requests returns the server requests recorded so far
+func (h *serverRequestHandler) Requests() []ServerRequest {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]ServerRequest(nil), h.requests...)
+}
+
+func (h *serverRequestHandler) record(event ServerRequest) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests = append(h.requests, event)
+}
+
+// answerElicitation returns the first unused scripted response that matches the message,
+// declining when there is none
+func (h *serverRequestHandler) answerElicitation(message string) *mcp.ElicitResult {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, response := range h.elicitation {
+		if h.used[i] {
+			continue
+		}
+		if response.Match != "" {
+			re, err := regexp.Compile(response.Match)
+			if err != nil || !re.MatchString(message) {
+				continue
+			}
+		}
+		h.used[i] = true
+
+		action := response.Action
+		if action == "" {
+			action = ElicitAccept
+		}
+		result := &mcp.ElicitResult{Action: string(action)}
+		if action == ElicitAccept {
+			result.Content = response.Content
+		}
+		return result
+	}
+
+	return &mcp.ElicitResult{Action: string(ElicitDecline)}
+}
+
+// mcpRoots converts the configured roots for the MCP client
+func mcpRoots(roots []Root) []*mcp.Root {
+	result := make([]*mcp.Root, 0, len(roots))
+	for _, root := range roots {
+		result = append(result, &mcp.Root{URI: root.URI, Name: root.Name})
+	}
+	return result
+}
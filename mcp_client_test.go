@@ -0,0 +1,161 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalClient_RunEval_Sampling(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamToolUseResponse("toolu_1", "summarize", `{"text":"A very long report about the api-gateway."}`),
+		streamTextResponse("The gateway report"), // answers the server's sampling request
+		streamTextResponse("done"),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{Name: "summarize", Prompt: "Summarize the report"})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+
+	call := result.Trace.Steps[0].ToolCalls[0]
+	assert.True(call.Success)
+	assert.Contains(string(call.Output), "Summary: The gateway report")
+
+	// The sampling request went to the configured model with the server's system prompt
+	sampling := api.Requests()[1]
+	assert.Equal("test-model", sampling["model"])
+	assert.Equal(float64(100), sampling["max_tokens"])
+	system := sampling["system"].([]any)
+	assert.Equal("Summarize the text in one sentence.", system[0].(map[string]any)["text"])
+	assert.Nil(sampling["tools"])
+
+	requests := result.Trace.ServerRequests
+	assert.Len(requests, 1)
+	assert.Equal("sampling/createMessage", requests[0].Method)
+	assert.Equal("test-model", requests[0].Model)
+	assert.Positive(requests[0].OutputTokens)
+	assert.Contains(string(requests[0].Params), "A very long report")
+	assert.Contains(string(requests[0].Result), "The gateway report")
+}
+
+func TestEvalClient_RunEval_ElicitationAndRoots(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamToolUseResponse("toolu_1", "delete_service", `{"service":"api-gateway"}`),
+		streamToolUseResponse("toolu_2", "delete_service", `{"service":"database"}`),
+		streamToolUseResponse("toolu_3", "list_workspace", `{}`),
+		streamTextResponse("done"),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		Roots:     []Root{{URI: "file:///workspace/project", Name: "project"}},
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:   "cleanup",
+		Prompt: "Delete the old services",
+		Elicitation: []ElicitationResponse{
+			{Match: "api-gateway", Content: map[string]any{"reason": "retired"}},
+		},
+	})
+	assert.NoError(err)
+
+	steps := result.Trace.Steps
+	assert.Contains(string(steps[0].ToolCalls[0].Output), "Deleted api-gateway because retired")
+	assert.Contains(string(steps[1].ToolCalls[0].Output), "Deletion not confirmed: decline")
+	assert.Contains(string(steps[2].ToolCalls[0].Output), "Roots: file:///workspace/project")
+
+	var methods []string
+	for _, request := range result.Trace.ServerRequests {
+		methods = append(methods, request.Method)
+	}
+	assert.Equal([]string{"elicitation/create", "elicitation/create", "roots/list"}, methods)
+
+	var answer map[string]any
+	assert.NoError(json.Unmarshal(result.Trace.ServerRequests[1].Result, &answer))
+	assert.Equal("decline", answer["action"])
+}
+
+func TestRequestRouter_ConcurrentRunsOnSharedSession(t *testing.T) {
+	assert := require.New(t)
+
+	router := newRequestRouter(&EvalClient{})
+	first := newServerRequestHandler(Eval{Elicitation: []ElicitationResponse{{Content: map[string]any{"run": "first"}}}})
+	second := newServerRequestHandler(Eval{Elicitation: []ElicitationResponse{{Content: map[string]any{"run": "second"}}}})
+	router.attach(first)
+	router.attach(second)
+
+	handle := router.middleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return router.elicit(ctx, req.(*mcp.ElicitRequest))
+	})
+	elicit := func() (mcp.Result, error) {
+		return handle(context.Background(), "elicitation/create", &mcp.ElicitRequest{Params: &mcp.ElicitParams{Message: "Confirm?"}})
+	}
+
+	// A request made while only the second run has a tool call in flight is answered from its script
+	second.inFlight.Add(1)
+	result, err := elicit()
+	assert.NoError(err)
+	assert.Equal(map[string]any{"run": "second"}, result.(*mcp.ElicitResult).Content)
+	assert.Len(second.Requests(), 1)
+	assert.Empty(first.Requests())
+
+	// With tool calls in flight for both runs the request can't be attributed and is refused
+	first.inFlight.Add(1)
+	_, err = elicit()
+	assert.EqualError(err, "elicitation/create refused: 2 eval runs share the session and the request can't be attributed to one of them")
+	for _, h := range []*serverRequestHandler{first, second} {
+		requests := h.Requests()
+		assert.Contains(requests[len(requests)-1].Error, "refused")
+	}
+}
+
+func TestElicitationResponse_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		response ElicitationResponse
+		wantErr  string
+	}{
+		{name: "accept by default", response: ElicitationResponse{Content: map[string]any{"ok": true}}},
+		{name: "decline", response: ElicitationResponse{Match: "^Really", Action: ElicitDecline}},
+		{name: "unknown action", response: ElicitationResponse{Action: "ignore"}, wantErr: "invalid action 'ignore'"},
+		{name: "content with cancel", response: ElicitationResponse{Action: ElicitCancel, Content: map[string]any{"a": 1}}, wantErr: "only allowed with action accept"},
+		{name: "bad pattern", response: ElicitationResponse{Match: "("}, wantErr: "invalid match pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			err := tt.response.Validate()
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
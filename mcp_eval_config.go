@@ -6,18 +6,160 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"gopkg.in/yaml.v3"
 	"mvdan.cc/sh/v3/shell"
 )
 
-// MCPServerConfig defines how to start the MCP server
+// MCPTransport selects how the eval client connects to the MCP server
+type MCPTransport string
+
+const (
+	TransportStdio          MCPTransport = "stdio"           // Launch the server as a subprocess and talk over stdin/stdout (default)
+	TransportSSE            MCPTransport = "sse"             // Connect to a legacy HTTP+SSE endpoint
+	TransportStreamableHTTP MCPTransport = "streamable-http" // Connect to a streamable HTTP endpoint
+)
+
+// MCPServerConfig defines how to start or connect to the MCP server
 type MCPServerConfig struct {
-	Command string   `yaml:"command" json:"command" jsonschema:"Command to start the MCP server"`
-	Args    []string `yaml:"args,omitempty" json:"args,omitempty" jsonschema:"Arguments to pass to the command"`
-	Env     []string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"Environment variables to set for the MCP server"`
+	Transport   MCPTransport      `yaml:"transport,omitempty" json:"transport,omitempty" jsonschema:"Transport used to connect to the MCP server: stdio (default), sse or streamable-http"`
+	Command     string            `yaml:"command,omitempty" json:"command,omitempty" jsonschema:"Command to start the MCP server (required for stdio transport)"`
+	Args        []string          `yaml:"args,omitempty" json:"args,omitempty" jsonschema:"Arguments to pass to the command"`
+	Env         []string          `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"Environment variables to set for the MCP server"`
+	URL         string            `yaml:"url,omitempty" json:"url,omitempty" jsonschema:"Endpoint URL of the MCP server (required for sse and streamable-http transports)"`
+	Headers     map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"HTTP headers sent with every request to the MCP server (sse and streamable-http transports)"`
+	Roots       []Root            `yaml:"roots,omitempty" json:"roots,omitempty" jsonschema:"Roots the client shares with the MCP server when it calls roots/list"`
+	SessionMode SessionMode       `yaml:"session_mode,omitempty" json:"session_mode,omitempty" jsonschema:"How MCP sessions are shared between evals: per_eval (default) starts a fresh session for every eval, shared runs all evals against one session and per_worker gives each parallel worker its own session"`
+}
+
+// Validate checks that the fields required by the selected transport are set
+func (c MCPServerConfig) Validate() error {
+	switch c.Transport {
+	case "", TransportStdio:
+		if c.Command == "" {
+			return fmt.Errorf("mcp_server.command is required in config")
+		}
+	case TransportSSE, TransportStreamableHTTP:
+		if c.URL == "" {
+			return fmt.Errorf("mcp_server.url is required for %s transport", c.Transport)
+		}
+	default:
+		return fmt.Errorf("invalid mcp_server.transport '%s': must be one of: stdio, sse, streamable-http", c.Transport)
+	}
+	switch c.SessionMode {
+	case "", SessionPerEval, SessionShared, SessionPerWorker:
+	default:
+		return fmt.Errorf("invalid mcp_server.session_mode '%s': must be one of: %s", c.SessionMode, joinEnum(sessionModes))
+	}
+	for i, root := range c.Roots {
+		if root.URI == "" {
+			return fmt.Errorf("mcp_server.roots[%d].uri is required", i)
+		}
+	}
+	return nil
+}
+
+// RetryConfig configures retries of model API requests that fail with rate-limit or overload errors
+type RetryConfig struct {
+	MaxAttempts int      `yaml:"max_attempts,omitempty" json:"max_attempts,omitempty" jsonschema:"Total attempts per request including the first (default 3)"`
+	BaseBackoff string   `yaml:"base_backoff,omitempty" json:"base_backoff,omitempty" jsonschema:"Delay before the first retry, doubled for each retry after that (default '1s'). A retry-after header from the API takes precedence"`
+	MaxBackoff  string   `yaml:"max_backoff,omitempty" json:"max_backoff,omitempty" jsonschema:"Maximum computed delay between attempts (default '30s')"`
+	Jitter      *float64 `yaml:"jitter,omitempty" json:"jitter,omitempty" jsonschema:"Fraction of each delay that is randomized, between 0 and 1 (default 0.2)"`
+}
+
+// Policy converts the retry configuration into a RetryPolicy for EvalClientConfig.
+// A nil config returns the zero policy, which uses the defaults.
+func (r *RetryConfig) Policy() (RetryPolicy, error) {
+	var policy RetryPolicy
+	if r == nil {
+		return policy, nil
+	}
+
+	if r.MaxAttempts < 0 {
+		return policy, fmt.Errorf("retry.max_attempts must be at least 1, got %d", r.MaxAttempts)
+	}
+	policy.MaxAttempts = r.MaxAttempts
+
+	if r.BaseBackoff != "" {
+		d, err := time.ParseDuration(r.BaseBackoff)
+		if err != nil {
+			return policy, fmt.Errorf("invalid retry.base_backoff '%s': %w", r.BaseBackoff, err)
+		}
+		policy.BaseBackoff = d
+	}
+
+	if r.MaxBackoff != "" {
+		d, err := time.ParseDuration(r.MaxBackoff)
+		if err != nil {
+			return policy, fmt.Errorf("invalid retry.max_backoff '%s': %w", r.MaxBackoff, err)
+		}
+		policy.MaxBackoff = d
+	}
+
+	if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+		return policy, fmt.Errorf("retry.jitter must be between 0 and 1, got %g", *r.Jitter)
+	}
+	policy.Jitter = r.Jitter
+
+	return policy, nil
+}
+
+// MinThinkingBudget is the smallest extended thinking budget the Anthropic API accepts
+const MinThinkingBudget = 1024
+
+// ThinkingConfig enables extended thinking for the agent under test
+type ThinkingConfig struct {
+	BudgetTokens int `yaml:"budget_tokens" json:"budget_tokens" jsonschema:"Tokens the model may spend thinking before it answers; at least 1024 and less than max_tokens, or 0 to disable thinking"`
+}
+
+// Validate checks the budget is either disabled or within the API limits for maxTokens
+func (t *ThinkingConfig) Validate(maxTokens int) error {
+	if t == nil || t.BudgetTokens == 0 {
+		return nil
+	}
+	if t.BudgetTokens < MinThinkingBudget {
+		return fmt.Errorf("thinking.budget_tokens must be at least %d, got %d", MinThinkingBudget, t.BudgetTokens)
+	}
+	if t.BudgetTokens >= maxTokens {
+		return fmt.Errorf("thinking.budget_tokens must be less than max_tokens (%d), got %d", maxTokens, t.BudgetTokens)
+	}
+	return nil
+}
+
+// GraderConfig configures the judge model used to grade eval responses independently
+// of the agent under test. Unset fields fall back to the agent's settings.
+type GraderConfig struct {
+	Provider     ProviderName `yaml:"provider,omitempty" json:"provider,omitempty" jsonschema:"LLM API used for grading: anthropic or openai (defaults to the agent's provider)"`
+	Model        string       `yaml:"model,omitempty" json:"model,omitempty" jsonschema:"Model ID used for grading (defaults to grading_model, then model)"`
+	BaseURL      string       `yaml:"base_url,omitempty" json:"base_url,omitempty" jsonschema:"API endpoint used for grading (defaults to the agent's endpoint when the provider is the same)"`
+	APIKey       string       `yaml:"api_key,omitempty" json:"api_key,omitempty" jsonschema:"API key used for grading, e.g. '${JUDGE_API_KEY}' (defaults to the agent's key when the provider is the same, then the provider's environment variable)"`
+	MaxTokens    int          `yaml:"max_tokens,omitempty" json:"max_tokens,omitempty" jsonschema:"Maximum tokens in the grading response (default 1000)"`
+	Temperature  *float64     `yaml:"temperature,omitempty" json:"temperature,omitempty" jsonschema:"Sampling temperature for grading; 0 gives the most repeatable grades (defaults to the provider's default)"`
+	SystemPrompt string       `yaml:"system_prompt,omitempty" json:"system_prompt,omitempty" jsonschema:"Replaces the default grading system prompt; it must still ask for the JSON score format"`
+}
+
+// Validate checks the grader provider and numeric settings
+func (g *GraderConfig) Validate() error {
+	if g == nil {
+		return nil
+	}
+
+	switch g.Provider {
+	case "", ProviderAnthropic, ProviderOpenAI:
+	default:
+		return fmt.Errorf("invalid grader.provider '%s': must be one of: %s", g.Provider, joinEnum(providerNames))
+	}
+	if g.MaxTokens < 0 {
+		return fmt.Errorf("grader.max_tokens must be positive, got %d", g.MaxTokens)
+	}
+	if g.Temperature != nil && *g.Temperature < 0 {
+		return fmt.Errorf("grader.temperature must not be negative, got %g", *g.Temperature)
+	}
+	return nil
 }
 
 type MaxTokens int
@@ -25,19 +167,31 @@ type MaxSteps int
 
 // EvalConfig represents the top-level configuration for running evaluations
 type EvalConfig struct {
-	Model                string          `yaml:"model" json:"model" jsonschema:"Anthropic model ID to use for evaluations"`
-	GradingModel         string          `yaml:"grading_model,omitempty" json:"grading_model,omitempty" jsonschema:"Anthropic model ID to use for grading (defaults to same as model)"`
+	Provider             ProviderName    `yaml:"provider,omitempty" json:"provider,omitempty" jsonschema:"LLM API used to run and grade evals: anthropic (default) or openai (any OpenAI-compatible chat completions API)"`
+	Model                string          `yaml:"model" json:"model" jsonschema:"Model ID to use for evaluations"`
+	GradingModel         string          `yaml:"grading_model,omitempty" json:"grading_model,omitempty" jsonschema:"Model ID to use for grading (defaults to same as model)"`
+	Grader               *GraderConfig   `yaml:"grader,omitempty" json:"grader,omitempty" jsonschema:"Optional judge model configuration, independent of the agent under test"`
 	AgentSystemPrompt    string          `yaml:"agent_system_prompt,omitempty" json:"agent_system_prompt,omitempty" jsonschema:"Default system prompt for the agent being evaluated (can be overridden per-eval)"`
 	Timeout              string          `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"Timeout duration for each evaluation (e.g., '2m', '30s')"`
-	MaxSteps             MaxSteps        `yaml:"max_steps,omitempty" json:"max_steps,omitempty" jsonschema:"Maximum number of agentic loop iterations"`
+	SuiteTimeout         string          `yaml:"suite_timeout,omitempty" json:"suite_timeout,omitempty" jsonschema:"Optional timeout for the whole run; evals still running when it expires are marked as timed out (e.g., '30m')"`
+	MaxSteps             MaxSteps        `yaml:"max_steps,omitempty" json:"max_steps,omitempty" jsonschema:"Maximum number of agentic loop iterations, across all turns of a conversation"`
 	MaxTokens            MaxTokens       `yaml:"max_tokens,omitempty" json:"max_tokens,omitempty" jsonschema:"Maximum tokens per LLM request"`
 	EnablePromptCaching  *bool           `yaml:"enable_prompt_caching,omitempty" json:"enable_prompt_caching,omitempty" jsonschema:"Enable Anthropic prompt caching for tool definitions and system prompts (defaults to true for cost savings)"`
 	CacheTTL             string          `yaml:"cache_ttl,omitempty" json:"cache_ttl,omitempty" jsonschema:"Cache time-to-live: '5m' (default, free) or '1h' (premium). Requires enable_prompt_caching=true"`
 	EnforceMinimumScores *bool           `yaml:"enforce_minimum_scores,omitempty" json:"enforce_minimum_scores,omitempty" jsonschema:"Enforce minimum scores from grading rubrics (defaults to true; set to false to disable)"`
+	Trials               int             `yaml:"trials,omitempty" json:"trials,omitempty" jsonschema:"Number of times to run each eval; results are aggregated across trials (default 1)"`
+	TrialPass            *TrialPolicy    `yaml:"trial_pass,omitempty" json:"trial_pass,omitempty" jsonschema:"How an eval's trials decide pass/fail when trials is greater than 1"`
+	Thinking             *ThinkingConfig `yaml:"thinking,omitempty" json:"thinking,omitempty" jsonschema:"Enable extended thinking for the agent under test (can be overridden per-eval)"`
+	Pricing              Pricing         `yaml:"pricing,omitempty" json:"pricing,omitempty" jsonschema:"Model prices in USD per million tokens keyed by model ID, added to or replacing the built-in prices"`
+	Budget               *Budget         `yaml:"budget,omitempty" json:"budget,omitempty" jsonschema:"Token, cost and time limits for the whole run; once one is reached running evals stop and the rest are skipped"`
+	Retry                *RetryConfig    `yaml:"retry,omitempty" json:"retry,omitempty" jsonschema:"Retry policy for rate-limited (429) or overloaded (529) model API requests"`
 	MCPServer            MCPServerConfig `yaml:"mcp_server" json:"mcp_server" jsonschema:"Configuration for the MCP server to evaluate"`
 	Evals                []Eval          `yaml:"evals" json:"evals" jsonschema:"List of evaluation test cases to run"`
 }
 
+// validTag matches eval tags, which must not contain the operators of selection expressions
+var validTag = regexp.MustCompile(`^[A-Za-z0-9_.:/-]+$`)
+
 // LoadConfig loads an evaluation configuration from a YAML or JSON file.
 // The file format is detected by the file extension (.yaml, .yml, or .json).
 // Environment variables in the config file are expanded using ${VAR} or $VAR syntax.
@@ -74,28 +228,177 @@ func LoadConfig(filePath string) (*EvalConfig, error) {
 	if config.Model == "" {
 		return nil, fmt.Errorf("model is required in config")
 	}
-	if config.MCPServer.Command == "" {
-		return nil, fmt.Errorf("mcp_server.command is required in config")
+	switch config.Provider {
+	case "", ProviderAnthropic, ProviderOpenAI:
+	default:
+		return nil, fmt.Errorf("invalid provider '%s': must be one of: %s", config.Provider, joinEnum(providerNames))
+	}
+	if err := config.Grader.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.MCPServer.Validate(); err != nil {
+		return nil, err
 	}
 	if len(config.Evals) == 0 {
 		return nil, fmt.Errorf("at least one eval is required in config")
 	}
 
-	// Validate grading rubrics for each eval
+	if err := validateDuration("timeout", config.Timeout); err != nil {
+		return nil, err
+	}
+	if err := validateDuration("suite_timeout", config.SuiteTimeout); err != nil {
+		return nil, err
+	}
+
+	if _, err := config.Retry.Policy(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Pricing.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Budget.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid budget: %w", err)
+	}
+
+	maxTokens := int(config.MaxTokens)
+	if maxTokens <= 0 {
+		maxTokens = 4096
+	}
+	if err := config.Thinking.Validate(maxTokens); err != nil {
+		return nil, err
+	}
+
+	if config.Trials < 0 {
+		return nil, fmt.Errorf("trials must be at least 1, got %d", config.Trials)
+	}
+	if err := config.TrialPass.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Validate tags, grading rubrics, timeouts, assertions, expected tools and trials for each eval
 	for i, eval := range config.Evals {
+		if eval.Prompt == "" && eval.PromptRef == nil {
+			return nil, fmt.Errorf("eval[%d] '%s' needs a prompt or prompt_ref", i, eval.Name)
+		}
+		for _, tag := range eval.Tags {
+			if !validTag.MatchString(tag) {
+				return nil, fmt.Errorf("eval[%d] '%s' has invalid tag '%s': tags may only contain letters, digits and _ - . : /", i, eval.Name, tag)
+			}
+		}
+		if eval.PromptRef != nil && eval.PromptRef.Name == "" {
+			return nil, fmt.Errorf("eval[%d] '%s' has invalid prompt_ref: name is required", i, eval.Name)
+		}
+		for j, turn := range eval.Turns {
+			if strings.TrimSpace(turn) == "" {
+				return nil, fmt.Errorf("eval[%d] '%s' has invalid turns[%d]: message is empty", i, eval.Name, j)
+			}
+		}
+		if err := eval.SimulatedUser.Validate(); err != nil {
+			return nil, fmt.Errorf("eval[%d] '%s' has invalid simulated_user: %w", i, eval.Name, err)
+		}
+		for j, response := range eval.Elicitation {
+			if err := response.Validate(); err != nil {
+				return nil, fmt.Errorf("eval[%d] '%s' has invalid elicitation[%d]: %w", i, eval.Name, j, err)
+			}
+		}
+		if err := eval.Thinking.Validate(maxTokens); err != nil {
+			return nil, fmt.Errorf("eval[%d] '%s' has invalid thinking: %w", i, eval.Name, err)
+		}
 		if err := eval.GradingRubric.Validate(); err != nil {
 			return nil, fmt.Errorf("eval[%d] '%s' has invalid rubric: %w", i, eval.Name, err)
 		}
+		if err := validateDuration("timeout", eval.Timeout); err != nil {
+			return nil, fmt.Errorf("eval[%d] '%s' has invalid timeout: %w", i, eval.Name, err)
+		}
+		if err := eval.Budget.Validate(); err != nil {
+			return nil, fmt.Errorf("eval[%d] '%s' has invalid budget: %w", i, eval.Name, err)
+		}
+		for j, assertion := range eval.Assertions {
+			if err := assertion.Validate(); err != nil {
+				return nil, fmt.Errorf("eval[%d] '%s' has invalid assertion[%d]: %w", i, eval.Name, j, err)
+			}
+		}
+		if err := eval.ExpectedTools.Validate(); err != nil {
+			return nil, fmt.Errorf("eval[%d] '%s' has invalid expected_tools: %w", i, eval.Name, err)
+		}
+		if eval.Trials < 0 {
+			return nil, fmt.Errorf("eval[%d] '%s' has invalid trials: must be at least 1, got %d", i, eval.Name, eval.Trials)
+		}
+		if err := eval.TrialPass.Validate(); err != nil {
+			return nil, fmt.Errorf("eval[%d] '%s' has invalid trial_pass: %w", i, eval.Name, err)
+		}
 	}
 
 	return &config, nil
 }
 
+// validateDuration checks that an optional duration field parses with time.ParseDuration
+func validateDuration(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	if _, err := time.ParseDuration(value); err != nil {
+		return fmt.Errorf("invalid %s '%s': %w", field, value, err)
+	}
+	return nil
+}
+
+// enumValues lists the values of a string enum for a JSON schema
+func enumValues[T ~string](values []T) []any {
+	enum := make([]any, len(values))
+	for i, value := range values {
+		enum[i] = string(value)
+	}
+	return enum
+}
+
+// joinEnum lists the values of a string enum for an error message, e.g. "exact, subset"
+func joinEnum[T ~string](values []T) string {
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = string(value)
+	}
+	return strings.Join(names, ", ")
+}
+
 // generateSchema creates a jsonschema.Schema for EvalConfig with custom metadata
 func generateSchema() (*jsonschema.Schema, error) {
 	customSchemas := map[reflect.Type]*jsonschema.Schema{
 		reflect.TypeFor[MaxTokens](): {Type: "integer", Minimum: jsonschema.Ptr(1.0), Maximum: jsonschema.Ptr(20000.0), Default: json.RawMessage("4096")},
 		reflect.TypeFor[MaxSteps]():  {Type: "integer", Minimum: jsonschema.Ptr(1.0), Maximum: jsonschema.Ptr(100.0), Default: json.RawMessage("10")},
+		reflect.TypeFor[MCPTransport](): {
+			Type:    "string",
+			Enum:    []any{string(TransportStdio), string(TransportSSE), string(TransportStreamableHTTP)},
+			Default: json.RawMessage(`"stdio"`),
+		},
+		reflect.TypeFor[SessionMode](): {
+			Type:    "string",
+			Enum:    enumValues(sessionModes),
+			Default: json.RawMessage(`"per_eval"`),
+		},
+		reflect.TypeFor[ProviderName](): {
+			Type:    "string",
+			Enum:    enumValues(providerNames),
+			Default: json.RawMessage(`"anthropic"`),
+		},
+		reflect.TypeFor[AssertionType](): {Type: "string", Enum: enumValues(assertionTypes)},
+		reflect.TypeFor[ElicitAction](): {
+			Type:    "string",
+			Enum:    enumValues(elicitActions),
+			Default: json.RawMessage(`"accept"`),
+		},
+		reflect.TypeFor[ToolMatchMode](): {
+			Type:    "string",
+			Enum:    enumValues(toolMatchModes),
+			Default: json.RawMessage(`"subset"`),
+		},
+		reflect.TypeFor[TrialPassRule](): {
+			Type:    "string",
+			Enum:    enumValues(trialPassRules),
+			Default: json.RawMessage(`"all"`),
+		},
 	}
 
 	opts := &jsonschema.ForOptions{TypeSchemas: customSchemas}
@@ -105,6 +408,26 @@ func generateSchema() (*jsonschema.Schema, error) {
 		return nil, fmt.Errorf("failed to generate JSON schema: %w", err)
 	}
 
+	// The stdio transport needs a command, the HTTP transports need a URL
+	if serverSchema, ok := schema.Properties["mcp_server"]; ok {
+		serverSchema.If = &jsonschema.Schema{
+			Properties: map[string]*jsonschema.Schema{
+				"transport": {Enum: []any{string(TransportSSE), string(TransportStreamableHTTP)}},
+			},
+			Required: []string{"transport"},
+		}
+		serverSchema.Then = &jsonschema.Schema{Required: []string{"url"}}
+		serverSchema.Else = &jsonschema.Schema{Required: []string{"command"}}
+	}
+
+	// Each eval needs its own prompt, a server prompt, or both
+	if evalsSchema, ok := schema.Properties["evals"]; ok && evalsSchema.Items != nil {
+		evalsSchema.Items.AnyOf = []*jsonschema.Schema{
+			{Required: []string{"prompt"}},
+			{Required: []string{"prompt_ref"}},
+		}
+	}
+
 	schema.Title = "MCP Evaluation Configuration"
 	schema.Description = "Configuration schema for running evaluations against Model Context Protocol (MCP) servers"
 	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
@@ -3,6 +3,7 @@ package evaluations
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -114,6 +115,7 @@ func TestEvalClientConfig_Defaults(t *testing.T) {
 
 			assert.Equal(tt.expectedSteps, client.config.MaxSteps)
 			assert.Equal(tt.expectedTokens, client.config.MaxTokens)
+			assert.Equal(1000, client.config.Grader.MaxTokens)
 		})
 	}
 }
@@ -163,7 +165,7 @@ func TestSchemaForEvalConfig(t *testing.T) {
 		path        []string
 		description string
 	}{
-		{[]string{"model", "description"}, "Anthropic model ID"},
+		{[]string{"model", "description"}, "Model ID to use for evaluations"},
 		{[]string{"timeout", "description"}, "Timeout duration"},
 		{[]string{"mcp_server", "description"}, "Configuration for the MCP server"},
 		{[]string{"evals", "description"}, "List of evaluation test cases"},
@@ -529,3 +531,471 @@ func TestLoadConfig_EnvironmentVariableInJSON(t *testing.T) {
 	assert.Equal("claude-3-5-sonnet-20241022", config.Model)
 	assert.Equal("/path/to/server", config.MCPServer.Command)
 }
+
+func TestLoadConfig_HTTPTransport(t *testing.T) {
+	assert := require.New(t)
+
+	t.Setenv("MCP_TOKEN", "secret-token")
+
+	configContent := `
+model: claude-3-5-sonnet-20241022
+mcp_server:
+  transport: streamable-http
+  url: https://mcp.example.com/mcp
+  headers:
+    Authorization: Bearer ${MCP_TOKEN}
+evals:
+  - name: test
+    prompt: "test"
+`
+
+	tmpFile, err := os.CreateTemp("", "config-http-*.yaml")
+	assert.NoError(err)
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString(configContent)
+	assert.NoError(err)
+	tmpFile.Close()
+
+	config, err := LoadConfig(tmpFile.Name())
+	assert.NoError(err)
+	assert.Equal(TransportStreamableHTTP, config.MCPServer.Transport)
+	assert.Equal("https://mcp.example.com/mcp", config.MCPServer.URL)
+	assert.Equal("Bearer secret-token", config.MCPServer.Headers["Authorization"])
+	assert.Empty(config.MCPServer.Command)
+}
+
+func TestMCPServerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      MCPServerConfig
+		errContains string
+	}{
+		{
+			name:   "stdio by default",
+			config: MCPServerConfig{Command: "server"},
+		},
+		{
+			name:        "stdio missing command",
+			config:      MCPServerConfig{Transport: TransportStdio},
+			errContains: "mcp_server.command is required",
+		},
+		{
+			name:   "sse with url",
+			config: MCPServerConfig{Transport: TransportSSE, URL: "http://localhost:8080/sse"},
+		},
+		{
+			name:        "streamable http missing url",
+			config:      MCPServerConfig{Transport: TransportStreamableHTTP},
+			errContains: "mcp_server.url is required",
+		},
+		{
+			name:        "unknown transport",
+			config:      MCPServerConfig{Transport: "websocket", URL: "ws://localhost"},
+			errContains: "invalid mcp_server.transport",
+		},
+		{
+			name:   "shared sessions",
+			config: MCPServerConfig{Command: "server", SessionMode: SessionShared},
+		},
+		{
+			name:        "unknown session mode",
+			config:      MCPServerConfig{Command: "server", SessionMode: "per_suite"},
+			errContains: "invalid mcp_server.session_mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := require.New(t)
+
+			err := tt.config.Validate()
+			if tt.errContains == "" {
+				assert.NoError(err)
+				return
+			}
+			assert.Error(err)
+			assert.Contains(err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestValidateConfigFile_Transports(t *testing.T) {
+	tests := []struct {
+		name      string
+		mcpServer string
+		valid     bool
+	}{
+		{
+			name: "streamable http with url",
+			mcpServer: `
+  transport: streamable-http
+  url: http://localhost:8080/mcp
+  headers:
+    Authorization: Bearer token`,
+			valid: true,
+		},
+		{
+			name: "sse missing url",
+			mcpServer: `
+  transport: sse`,
+			valid: false,
+		},
+		{
+			name: "stdio missing command",
+			mcpServer: `
+  transport: stdio
+  url: http://localhost:8080/mcp`,
+			valid: false,
+		},
+		{
+			name: "invalid transport",
+			mcpServer: `
+  transport: websocket
+  url: ws://localhost:8080`,
+			valid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			configContent := `
+model: claude-3-5-sonnet-20241022
+mcp_server:` + tt.mcpServer + `
+evals:
+  - name: test
+    prompt: "test prompt"
+`
+
+			tmpFile, err := os.CreateTemp("", "config-transport-*.yaml")
+			assert.NoError(err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(configContent)
+			assert.NoError(err)
+			tmpFile.Close()
+
+			result, err := ValidateConfigFile(tmpFile.Name())
+			assert.NoError(err)
+			assert.Equal(tt.valid, result.Valid, "errors: %v", result.Errors)
+		})
+	}
+}
+
+func TestLoadConfig_Grader(t *testing.T) {
+	tests := []struct {
+		name    string
+		grader  string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			grader: `
+grader:
+  provider: openai
+  model: judge-model
+  base_url: http://localhost:8000/v1
+  max_tokens: 500
+  temperature: 0
+  system_prompt: "Grade strictly"
+`,
+		},
+		{
+			name: "invalid provider",
+			grader: `
+grader:
+  provider: gemini
+`,
+			wantErr: "invalid grader.provider 'gemini'",
+		},
+		{
+			name: "negative max tokens",
+			grader: `
+grader:
+  max_tokens: -1
+`,
+			wantErr: "grader.max_tokens must be positive",
+		},
+		{
+			name: "negative temperature",
+			grader: `
+grader:
+  temperature: -0.5
+`,
+			wantErr: "grader.temperature must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			configContent := `
+model: test-model
+mcp_server:
+  command: echo
+evals:
+  - name: test
+    prompt: "test"
+` + tt.grader
+
+			tmpFile, err := os.CreateTemp("", "config-grader-*.yaml")
+			assert.NoError(err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(configContent)
+			assert.NoError(err)
+			tmpFile.Close()
+
+			config, err := LoadConfig(tmpFile.Name())
+			if tt.wantErr != "" {
+				assert.Error(err)
+				assert.Contains(err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.NotNil(config.Grader)
+			assert.Equal(ProviderOpenAI, config.Grader.Provider)
+			assert.Equal("judge-model", config.Grader.Model)
+			assert.Equal("http://localhost:8000/v1", config.Grader.BaseURL)
+			assert.Equal(500, config.Grader.MaxTokens)
+			assert.NotNil(config.Grader.Temperature)
+			assert.Equal(0.0, *config.Grader.Temperature)
+			assert.Equal("Grade strictly", config.Grader.SystemPrompt)
+		})
+	}
+}
+
+func TestLoadConfig_Thinking(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "global budget with per-eval override",
+			config: `
+thinking:
+  budget_tokens: 2048
+evals:
+  - name: test
+    prompt: "test"
+    thinking:
+      budget_tokens: 0
+`,
+		},
+		{
+			name: "budget below minimum",
+			config: `
+thinking:
+  budget_tokens: 500
+evals:
+  - name: test
+    prompt: "test"
+`,
+			wantErr: "thinking.budget_tokens must be at least 1024",
+		},
+		{
+			name: "budget not below max tokens",
+			config: `
+max_tokens: 2000
+evals:
+  - name: test
+    prompt: "test"
+    thinking:
+      budget_tokens: 2000
+`,
+			wantErr: "eval[0] 'test' has invalid thinking: thinking.budget_tokens must be less than max_tokens (2000)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			content := "model: test-model\nmcp_server:\n  command: echo\n" + tt.config
+			assert.NoError(os.WriteFile(path, []byte(content), 0o600))
+
+			config, err := LoadConfig(path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(2048, config.Thinking.BudgetTokens)
+			assert.Equal(0, config.Evals[0].Thinking.BudgetTokens)
+		})
+	}
+}
+
+func TestLoadConfig_Pricing(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "custom model price",
+			config: `
+pricing:
+  test-model:
+    input: 2
+    output: 8
+    cache_read: 0.5
+`,
+		},
+		{
+			name: "negative price",
+			config: `
+pricing:
+  test-model:
+    input: -2
+    output: 8
+`,
+			wantErr: "invalid pricing for model 'test-model': input must not be negative, got -2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			content := "model: test-model\nmcp_server:\n  command: echo\nevals:\n  - name: test\n    prompt: \"test\"\n" + tt.config
+			assert.NoError(os.WriteFile(path, []byte(content), 0o600))
+
+			config, err := LoadConfig(path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(ModelPrice{Input: 2, Output: 8, CacheRead: 0.5}, config.Pricing["test-model"])
+		})
+	}
+}
+
+func TestLoadConfig_Budget(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "suite and eval budgets",
+			config: `
+budget:
+  max_cost_usd: 5
+  max_duration: 30m
+evals:
+  - name: test
+    prompt: "test"
+    budget:
+      max_tokens: 20000
+`,
+		},
+		{
+			name: "invalid suite budget",
+			config: `
+budget:
+  max_duration: soon
+evals:
+  - name: test
+    prompt: "test"
+`,
+			wantErr: "invalid budget: invalid max_duration 'soon'",
+		},
+		{
+			name: "invalid eval budget",
+			config: `
+evals:
+  - name: test
+    prompt: "test"
+    budget:
+      max_tokens: -5
+`,
+			wantErr: "eval[0] 'test' has invalid budget: max_tokens must not be negative, got -5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			content := "model: test-model\nmcp_server:\n  command: echo\n" + tt.config
+			assert.NoError(os.WriteFile(path, []byte(content), 0o600))
+
+			config, err := LoadConfig(path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(&Budget{MaxCostUSD: 5, MaxDuration: "30m"}, config.Budget)
+			assert.Equal(&Budget{MaxTokens: 20000}, config.Evals[0].Budget)
+		})
+	}
+}
+
+func TestLoadConfig_Tags(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "valid tags",
+			config: `
+evals:
+  - name: test
+    prompt: "test"
+    tags: [smoke, team:payments, long-running, v1.2]
+`,
+			want: []string{"smoke", "team:payments", "long-running", "v1.2"},
+		},
+		{
+			name: "operator in tag",
+			config: `
+evals:
+  - name: test
+    prompt: "test"
+    tags: ["smoke&&fast"]
+`,
+			wantErr: "eval[0] 'test' has invalid tag 'smoke&&fast'",
+		},
+		{
+			name: "empty tag",
+			config: `
+evals:
+  - name: test
+    prompt: "test"
+    tags: [""]
+`,
+			wantErr: "eval[0] 'test' has invalid tag ''",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			content := "model: test-model\nmcp_server:\n  command: echo\n" + tt.config
+			assert.NoError(os.WriteFile(path, []byte(content), 0o600))
+
+			config, err := LoadConfig(path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.want, config.Evals[0].Tags)
+		})
+	}
+}
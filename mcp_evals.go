@@ -1,17 +1,14 @@
 package evaluations
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
-	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rs/zerolog/log"
 )
@@ -43,19 +40,36 @@ Use this exact format:
 )
 
 type EvalClientConfig struct {
-	APIKey               string
-	BaseURL              string // Optional: if set, override the default Anthropic API endpoint
-	Command              string
-	Args                 []string
-	Env                  []string
+	Provider             ProviderName      // Optional: LLM API used to run and grade evals, one of anthropic (default) or openai
+	APIKey               string            // Optional: API key for the provider, defaults to ANTHROPIC_API_KEY or OPENAI_API_KEY
+	BaseURL              string            // Optional: if set, override the default API endpoint of the provider
+	Transport            MCPTransport      // Optional: how to connect to the MCP server, one of stdio (default), sse or streamable-http
+	Command              string            // Command to start the MCP server (stdio transport)
+	Args                 []string          // Arguments to pass to Command (stdio transport)
+	Env                  []string          // Extra environment variables for Command (stdio transport)
+	URL                  string            // Endpoint URL of the MCP server (sse and streamable-http transports)
+	Headers              map[string]string // Optional: HTTP headers sent with every MCP request (sse and streamable-http transports)
+	Roots                []Root            // Optional: roots returned to the MCP server's roots/list requests
+	SessionMode          SessionMode       // Optional: how MCP sessions are shared between eval runs, one of per_eval (default), shared or per_worker
 	Model                string
-	GradingModel         string // Optional: if set, use this model for grading instead of Model
-	AgentSystemPrompt    string // Optional: custom system prompt for the agent being evaluated
+	GradingModel         string       // Optional: if set, use this model for grading instead of Model
+	Grader               GraderConfig // Optional: separate provider, endpoint and settings for grading
+	AgentSystemPrompt    string       // Optional: custom system prompt for the agent being evaluated
+	ThinkingBudget       int          // Optional: extended thinking token budget for the agent, overridden by Eval.Thinking. Default: disabled
 	MaxSteps             int
 	MaxTokens            int
 	EnablePromptCaching  *bool             // Optional: enable Anthropic prompt caching for tool definitions and system prompts. Default: true
 	CacheTTL             string            // Optional: cache time-to-live, either "5m" (default) or "1h". Requires EnablePromptCaching=true
 	EnforceMinimumScores *bool             // Optional: enforce minimum scores from grading rubrics. Default: true
+	Concurrency          int               // Optional: number of evals RunEvals executes in parallel. Default: 1
+	Timeout              time.Duration     // Optional: deadline applied to each RunEval call, overridden by Eval.Timeout. Default: none
+	Trials               int               // Optional: number of times RunEvals runs each eval, overridden by Eval.Trials. Default: 1
+	TrialPass            TrialPolicy       // Optional: how trials decide pass/fail, overridden by Eval.TrialPass. Default: every trial passes
+	Retry                RetryPolicy       // Optional: retry policy for rate-limited or overloaded model API requests
+	Pricing              Pricing           // Optional: model prices in USD, merged over DefaultPricing
+	Budget               *Budget           // Optional: limits on everything the client runs; once reached remaining evals are skipped. Default: none
+	Cassette             *Cassette         // Optional: records agent, grading and other model requests to, or replays them from, this cassette
+	CassetteMode         CassetteMode      // Optional: how Cassette is used, one of replay (default) or record
 	StderrCallback       func(line string) // Optional: called for each line written to stderr by the MCP server subprocess
 }
 
@@ -68,6 +82,26 @@ func (c *EvalClientConfig) ApplyDefaults() *EvalClientConfig {
 	if c.MaxTokens <= 0 {
 		c.MaxTokens = 4096
 	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = 1
+	}
+	c.Retry.ApplyDefaults()
+	c.Pricing = DefaultPricing.Merge(c.Pricing)
+	if c.Transport == "" {
+		c.Transport = TransportStdio
+	}
+	if c.SessionMode == "" {
+		c.SessionMode = SessionPerEval
+	}
+	if c.Provider == "" {
+		c.Provider = ProviderAnthropic
+	}
+	if c.Cassette != nil && c.CassetteMode == "" {
+		c.CassetteMode = CassetteReplay
+	}
+	if c.Grader.MaxTokens <= 0 {
+		c.Grader.MaxTokens = 1000
+	}
 	if c.CacheTTL == "" {
 		c.CacheTTL = "5m" // Default to 5-minute cache (free)
 	}
@@ -81,63 +115,122 @@ func (c *EvalClientConfig) ApplyDefaults() *EvalClientConfig {
 }
 
 type EvalClient struct {
-	client anthropic.Client
-	config EvalClientConfig
+	provider    Provider
+	grader      Provider
+	providerErr error // set when the configured agent or grader provider is invalid, returned by RunEval
+	config      EvalClientConfig
+	sessions    *sessionPool
+	spend       suiteSpend // usage counted against config.Budget
 }
 
 func NewEvalClient(config EvalClientConfig) *EvalClient {
 	// Apply defaults for optional fields
 	config.ApplyDefaults()
 
-	opts := []option.RequestOption{}
-	if config.APIKey != "" {
-		opts = append(opts, option.WithAPIKey(config.APIKey))
+	provider, err := newProvider(config)
+	grader, graderErr := newProvider(config.graderClientConfig())
+	ec := &EvalClient{
+		provider:    provider,
+		grader:      grader,
+		providerErr: errors.Join(err, graderErr),
+		config:      config,
 	}
-	if config.BaseURL != "" {
-		opts = append(opts, option.WithBaseURL(config.BaseURL))
+	ec.useCassette()
+	ec.sessions = newSessionPool(ec)
+	return ec
+}
+
+// NewEvalClientWithProvider creates an EvalClient that sends both agent and
+// grading requests to provider instead of the ones selected by config
+func NewEvalClientWithProvider(config EvalClientConfig, provider Provider) *EvalClient {
+	config.ApplyDefaults()
+
+	ec := &EvalClient{
+		provider: provider,
+		grader:   provider,
+		config:   config,
 	}
+	ec.useCassette()
+	ec.sessions = newSessionPool(ec)
+	return ec
+}
 
-	// enable 1m tokens beta for sonnet models
-	opts = append(opts, option.WithHeader("anthropic-beta", anthropic.AnthropicBetaContext1m2025_08_07))
+// useCassette routes the agent and grading providers through the configured cassette
+func (ec *EvalClient) useCassette() {
+	if ec.config.Cassette == nil {
+		return
+	}
+	switch ec.config.CassetteMode {
+	case "":
+		ec.config.CassetteMode = CassetteReplay
+	case CassetteRecord, CassetteReplay:
+	default:
+		ec.providerErr = errors.Join(ec.providerErr,
+			fmt.Errorf("invalid cassette mode '%s': must be one of: %s, %s", ec.config.CassetteMode, CassetteReplay, CassetteRecord))
+		return
+	}
 
-	return &EvalClient{
-		client: anthropic.NewClient(opts...), // uses ANTHROPIC_API_KEY from env
-		config: config,
+	if ec.provider != nil {
+		ec.provider = newCassetteProvider(ec.provider, ec.config.Cassette, ec.config.CassetteMode)
+	}
+	if ec.grader != nil {
+		ec.grader = newCassetteProvider(ec.grader, ec.config.Cassette, ec.config.CassetteMode)
 	}
 }
 
-// loadMCPSession creates an MCP client, connects to the server, and retrieves available tools
-func (ec *EvalClient) loadMCPSession(ctx context.Context) (*mcp.ClientSession, *mcp.ListToolsResult, error) {
-	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "mcp-client", Version: "v1.0.0"}, nil)
-	// #nosec G204 - Command and args are provided by the library caller as part of EvalClientConfig
-	cmd := exec.Command(ec.config.Command, ec.config.Args...)
+// Close shuts down the MCP sessions kept open by the shared and per_worker session modes.
+// It should be called once no evals are running.
+func (ec *EvalClient) Close() error {
+	return ec.sessions.Close()
+}
 
-	// Handle stderr based on whether a callback is provided
-	if ec.config.StderrCallback != nil {
-		stderrPipe, err := cmd.StderrPipe()
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create stderr pipe: %w", err)
-		}
+// graderClientConfig returns the config used to build the grading provider. The
+// agent's API key and endpoint are only reused when the grader uses the same provider.
+func (c EvalClientConfig) graderClientConfig() EvalClientConfig {
+	grader := c
+	if c.Grader.Provider != "" && c.Grader.Provider != c.Provider {
+		grader.Provider = c.Grader.Provider
+		grader.APIKey = ""
+		grader.BaseURL = ""
+	}
+	if c.Grader.APIKey != "" {
+		grader.APIKey = c.Grader.APIKey
+	}
+	if c.Grader.BaseURL != "" {
+		grader.BaseURL = c.Grader.BaseURL
+	}
+	return grader
+}
 
-		// Spawn goroutine to read stderr line-by-line and invoke callback
-		go func() {
-			scanner := bufio.NewScanner(stderrPipe)
-			for scanner.Scan() {
-				ec.config.StderrCallback(scanner.Text())
-			}
-			// Ignore scanner errors as they typically occur when the process exits
-		}()
-	} else {
-		cmd.Stderr = os.Stderr // forward subprocess stderr for visibility (backward compatible)
+// gradingModel returns the model used for grading
+// Precedence: grader config > GradingModel > Model
+func (c EvalClientConfig) gradingModel() string {
+	switch {
+	case c.Grader.Model != "":
+		return c.Grader.Model
+	case c.GradingModel != "":
+		return c.GradingModel
+	default:
+		return c.Model
 	}
+}
 
-	// If custom env vars are provided, append them to the parent environment
-	if len(ec.config.Env) > 0 {
-		cmd.Env = append(os.Environ(), ec.config.Env...)
+// loadMCPSession creates an MCP client, connects to the server, and retrieves available tools.
+// When router is set it answers the server's sampling and elicitation requests and records them.
+func (ec *EvalClient) loadMCPSession(ctx context.Context, router *requestRouter) (*mcp.ClientSession, *mcp.ListToolsResult, error) {
+	var opts *mcp.ClientOptions
+	if router != nil {
+		opts = router.clientOptions()
 	}
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "mcp-client", Version: "v1.0.0"}, opts)
+	if router != nil {
+		mcpClient.AddReceivingMiddleware(router.middleware)
+	}
+	mcpClient.AddRoots(mcpRoots(ec.config.Roots)...)
 
-	transport := &mcp.CommandTransport{
-		Command: cmd,
+	transport, err := ec.newMCPTransport()
+	if err != nil {
+		return nil, nil, err
 	}
 
 	session, err := mcpClient.Connect(ctx, transport, nil)
@@ -155,28 +248,36 @@ func (ec *EvalClient) loadMCPSession(ctx context.Context) (*mcp.ClientSession, *
 	return session, toolsResp, nil
 }
 
-// executeAndTraceToolCall executes a single MCP tool call and captures complete trace data
+// executeAndTraceToolCall executes a single MCP tool call and captures complete trace data.
+// It also returns the tool result block to send back to the model.
 func (ec *EvalClient) executeAndTraceToolCall(
 	ctx context.Context,
-	toolUseBlock anthropic.ToolUseBlock,
+	toolUse ContentBlock,
 	session *mcp.ClientSession,
-) ToolCall {
+	resourceTools map[string]bool,
+) (ToolCall, ContentBlock) {
 	toolCall := ToolCall{
-		ToolID:    toolUseBlock.ID,
-		ToolName:  toolUseBlock.Name,
+		ToolID:    toolUse.ToolUseID,
+		ToolName:  toolUse.ToolName,
 		StartTime: time.Now(),
 	}
 
 	// Capture input
-	if inputJSON, err := json.Marshal(toolUseBlock.Input); err == nil {
+	if inputJSON, err := json.Marshal(toolUse.Input); err == nil {
 		toolCall.Input = inputJSON
 	}
 
-	// Execute MCP tool call
-	result, err := session.CallTool(ctx, &mcp.CallToolParams{
-		Name:      toolUseBlock.Name,
-		Arguments: toolUseBlock.Input,
-	})
+	// Execute the MCP tool call, resource tools are served from the resources API
+	var result *mcp.CallToolResult
+	var err error
+	if resourceTools[toolUse.ToolName] {
+		result, err = callResourceTool(ctx, session, toolUse.ToolName, toolUse.Input)
+	} else {
+		result, err = session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      toolUse.ToolName,
+			Arguments: toolUse.Input,
+		})
+	}
 
 	toolCall.EndTime = time.Now()
 	toolCall.Duration = toolCall.EndTime.Sub(toolCall.StartTime)
@@ -189,33 +290,94 @@ func (ec *EvalClient) executeAndTraceToolCall(
 		if outputJSON, marshalErr := json.Marshal(errorOutput); marshalErr == nil {
 			toolCall.Output = outputJSON
 		}
-	} else {
-		toolCall.Success = true
-		// Convert MCP result to structured output
-		var contentParts []string
-		for _, content := range result.Content {
-			switch c := content.(type) {
-			case *mcp.TextContent:
-				contentParts = append(contentParts, c.Text)
-			case *mcp.ImageContent:
-				contentParts = append(contentParts, fmt.Sprintf("[Image: %s]", c.MIMEType))
-			case *mcp.EmbeddedResource:
-				contentParts = append(contentParts, fmt.Sprintf("[Resource: %s]", c.Resource.URI))
+		return toolCall, ToolResultBlock(toolUse.ToolUseID, fmt.Sprintf("Error calling tool: %s", toolCall.Error), true)
+	}
+
+	// Convert MCP result to structured output
+	resultContent, images := flattenContent(result.Content)
+
+	if result.StructuredContent != nil {
+		if structured, marshalErr := json.Marshal(result.StructuredContent); marshalErr == nil {
+			toolCall.StructuredContent = structured
+			// Servers should mirror structured content as text, fall back to it when they don't
+			if resultContent == "" {
+				resultContent = string(structured)
 			}
 		}
-		resultContent := strings.Join(contentParts, "\n")
+	}
 
-		// Store as JSON string for trace output
-		outputData := map[string]string{"result": resultContent}
-		if outputJSON, marshalErr := json.Marshal(outputData); marshalErr == nil {
-			toolCall.Output = outputJSON
-		}
+	// Store as JSON string for trace output
+	outputData := map[string]string{"result": resultContent}
+	if outputJSON, marshalErr := json.Marshal(outputData); marshalErr == nil {
+		toolCall.Output = outputJSON
 	}
 
-	return toolCall
+	// The server reports tool-level failures in the result rather than as a protocol error
+	if result.IsError {
+		toolCall.Success = false
+		toolCall.Error = resultContent
+		block := ToolResultBlock(toolUse.ToolUseID, fmt.Sprintf("Error calling tool: %s", resultContent), true)
+		block.Images = images
+		return toolCall, block
+	}
+
+	toolCall.Success = true
+	block := ToolResultBlock(toolUse.ToolUseID, string(toolCall.Output), false)
+	block.Images = images
+	return toolCall, block
+}
+
+// flattenContent converts MCP content to text for the model and the trace. Images are
+// returned separately so providers that support them can pass them on.
+func flattenContent(contents []mcp.Content) (string, []Image) {
+	var contentParts []string
+	var images []Image
+	for _, content := range contents {
+		switch c := content.(type) {
+		case *mcp.TextContent:
+			contentParts = append(contentParts, c.Text)
+		case *mcp.ImageContent:
+			contentParts = append(contentParts, fmt.Sprintf("[Image: %s]", c.MIMEType))
+			images = append(images, Image{MIMEType: c.MIMEType, Data: c.Data})
+		case *mcp.AudioContent:
+			contentParts = append(contentParts, fmt.Sprintf("[Audio: %s]", c.MIMEType))
+		case *mcp.ResourceLink:
+			contentParts = append(contentParts, fmt.Sprintf("[Resource: %s]", c.URI))
+		case *mcp.EmbeddedResource:
+			if c.Resource != nil && c.Resource.Text != "" {
+				contentParts = append(contentParts, c.Resource.Text)
+			} else if c.Resource != nil {
+				contentParts = append(contentParts, fmt.Sprintf("[Resource: %s]", c.Resource.URI))
+			}
+		}
+	}
+	return strings.Join(contentParts, "\n"), images
 }
 
 func (ec *EvalClient) RunEval(ctx context.Context, eval Eval) (*EvalRunResult, error) {
+	if ec.providerErr != nil {
+		return nil, ec.providerErr
+	}
+
+	timeout, err := ec.evalTimeout(eval)
+	if err != nil {
+		return nil, err
+	}
+	if err := ec.config.Budget.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid budget: %w", err)
+	}
+	if err := eval.Budget.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid budget for eval '%s': %w", eval.Name, err)
+	}
+
+	// Apply the deadline to this eval only so slow evals don't starve the rest of the suite
+	suiteCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	overallStart := time.Now()
 	trace := &EvalTrace{
 		Steps: make([]AgenticStep, 0, ec.config.MaxSteps),
@@ -226,221 +388,269 @@ func (ec *EvalClient) RunEval(ctx context.Context, eval Eval) (*EvalRunResult, e
 		Trace: trace,
 	}
 
-	session, toolsResp, err := ec.loadMCPSession(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = session.Close() }()
-
-	// convert the tools to the format expected by the anthropic model
-	toolParams := make([]anthropic.ToolParam, 0, len(toolsResp.Tools))
-	for _, tool := range toolsResp.Tools {
-		// Convert the MCP tool input schema to Anthropic format
-		var properties map[string]any
-		if tool.InputSchema != nil {
-			// MCP uses JSON Schema, convert to map
-			schemaBytes, _ := json.Marshal(tool.InputSchema)
-			var schema map[string]any
-			if err = json.Unmarshal(schemaBytes, &schema); err == nil {
-				if props, ok := schema["properties"].(map[string]any); ok {
-					properties = props
-				}
+	// failed records a timeout or an exhausted budget as a partial result, any other
+	// error aborts the eval
+	failed := func(err error) (*EvalRunResult, error) {
+		switch {
+		case errors.Is(err, ErrBudgetExceeded):
+			result.Status = EvalStatusBudgetExceeded
+			result.Error = err
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			result.Status = EvalStatusTimeout
+			if suiteCtx.Err() != nil {
+				result.Error = fmt.Errorf("suite timeout exceeded: %w", err)
+			} else {
+				result.Error = fmt.Errorf("eval timed out after %s: %w", timeout, err)
 			}
+		default:
+			return nil, err
 		}
 
-		toolParam := anthropic.ToolParam{
-			Name:        tool.Name,
-			Description: anthropic.String(tool.Description),
-			InputSchema: anthropic.ToolInputSchemaParam{
-				Properties: properties,
-			},
-		}
-		toolParams = append(toolParams, toolParam)
+		trace.Status = result.Status
+		trace.summarize()
+		trace.TotalDuration = time.Since(overallStart)
+		return result, nil
 	}
 
-	// Add cache control to the last tool definition if caching is enabled
-	// This creates a cache breakpoint after all tools, maximizing cache reuse
-	if ec.config.EnablePromptCaching != nil && *ec.config.EnablePromptCaching && len(toolParams) > 0 {
-		lastIdx := len(toolParams) - 1
-		toolParams[lastIdx].CacheControl = anthropic.NewCacheControlEphemeralParam()
-		// Set TTL if specified (5m or 1h)
-		if ec.config.CacheTTL == "1h" {
-			toolParams[lastIdx].CacheControl.TTL = "1h"
-		}
+	// Skip the eval without starting a session once the suite budget is used up
+	meter := ec.newEvalMeter(eval, overallStart)
+	if err := meter.check(); err != nil {
+		return failed(fmt.Errorf("eval skipped: %w", err))
 	}
 
-	tools := make([]anthropic.ToolUnionParam, len(toolParams))
-	for i, toolParam := range toolParams {
-		tools[i] = anthropic.ToolUnionParam{OfTool: &toolParam}
-	}
+	// Requests the server makes back to the client are recorded however the eval ends,
+	// the cost is worked out once they are in the trace and counted against the budget
+	handler := newServerRequestHandler(eval)
+	defer func() {
+		trace.ServerRequests = handler.Requests()
+		trace.Cost = ec.traceCost(trace)
+		meter.update(trace, trace.ServerRequests)
+	}()
 
-	// Initialize message history
-	messages := []anthropic.MessageParam{
-		anthropic.NewUserMessage(anthropic.NewTextBlock(eval.Prompt)),
+	mcpSession, err := ec.sessions.acquire(ctx, handler)
+	if err != nil {
+		return failed(err)
+	}
+	defer ec.sessions.release(mcpSession, handler)
+	session, toolsResp := mcpSession.session, mcpSession.tools
+
+	// Convert the MCP tools to provider-neutral definitions
+	tools, schemaWarnings := toolDefinitions(toolsResp.Tools)
+	for _, warning := range schemaWarnings {
+		log.Warn().
+			Str("eval", eval.Name).
+			Str("warning", warning).
+			Msg("Tool input schema could not be translated exactly")
+	}
+	trace.SchemaWarnings = schemaWarnings
+	trace.Tools = toolsResp.Tools
+
+	// Expose the server's resources through synthetic tools
+	resourceTools := make(map[string]bool)
+	for _, tool := range resourceToolDefinitions(session, toolsResp.Tools) {
+		resourceTools[tool.Name] = true
+		tools = append(tools, tool)
 	}
 
-	var finalText strings.Builder
+	// Build system prompt
+	// Precedence: per-eval > client config > default constant
+	systemPrompt := AgentSystemPrompt
+	if ec.config.AgentSystemPrompt != "" {
+		systemPrompt = ec.config.AgentSystemPrompt
+	}
+	if eval.AgentSystemPrompt != "" {
+		systemPrompt = eval.AgentSystemPrompt
+	}
 
-	// Agentic loop with tracing
-	stepNumber := 0
-	for range ec.config.MaxSteps {
-		stepNumber++
-		stepStart := time.Now()
-		step := AgenticStep{
-			StepNumber: stepNumber,
-			StartTime:  stepStart,
-			ToolCalls:  make([]ToolCall, 0),
+	// Initialize message history, starting from the server prompt if the eval references one
+	var messages []Message
+	if eval.PromptRef != nil {
+		promptMessages, promptTrace, err := fetchPrompt(ctx, session, eval.PromptRef)
+		trace.Prompt = promptTrace
+		if err != nil {
+			return failed(err)
 		}
+		messages = promptMessages
+	}
+	if eval.Prompt != "" {
+		messages = appendUserText(messages, eval.Prompt)
+	}
+	if trace.Prompt != nil {
+		// The grader sees the rendered prompt rather than just the eval's own text
+		eval.Prompt = strings.TrimSpace(trace.Prompt.Text() + "\n\n" + eval.Prompt)
+	}
 
-		// Build system prompt with optional cache control
-		// Precedence: per-eval > client config > default constant
-		promptText := AgentSystemPrompt
-		if ec.config.AgentSystemPrompt != "" {
-			promptText = ec.config.AgentSystemPrompt
-		}
-		if eval.AgentSystemPrompt != "" {
-			promptText = eval.AgentSystemPrompt
-		}
+	thinkingBudget := ec.config.ThinkingBudget
+	if eval.Thinking != nil {
+		thinkingBudget = eval.Thinking.BudgetTokens
+	}
 
-		systemPrompt := anthropic.TextBlockParam{
-			Text: promptText,
-		}
-		if ec.config.EnablePromptCaching != nil && *ec.config.EnablePromptCaching {
-			systemPrompt.CacheControl = anthropic.NewCacheControlEphemeralParam()
-			if ec.config.CacheTTL == "1h" {
-				systemPrompt.CacheControl.TTL = "1h"
+	// The agent's answer to the last user turn, which assertions and grading judge
+	var finalText string
+
+	// Each user turn runs the agentic loop until the model answers. Conversational evals
+	// then continue with the next scripted or simulated user message while steps remain,
+	// max_steps caps the steps of the whole conversation.
+	turn := TurnTrace{Source: TurnSourcePrompt, UserMessage: eval.Prompt}
+	stepNumber := 0
+	for {
+		turn.TurnNumber = len(trace.Turns) + 1
+		turn.StartTime = time.Now()
+		turn.FirstStep = stepNumber + 1
+		var turnText strings.Builder
+
+		// Agentic loop with tracing
+		for stepNumber < ec.config.MaxSteps {
+			meter.update(trace, handler.Requests())
+			if err := meter.check(); err != nil {
+				return failed(err)
 			}
-		}
 
-		stream := ec.client.Messages.NewStreaming(ctx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(ec.config.Model),
-			MaxTokens: int64(ec.config.MaxTokens),
-			System: []anthropic.TextBlockParam{
-				systemPrompt,
-			},
-			Messages: messages,
-			Tools:    tools,
-		})
+			stepNumber++
+			stepStart := time.Now()
+			step := AgenticStep{
+				StepNumber: stepNumber,
+				StartTime:  stepStart,
+				ToolCalls:  make([]ToolCall, 0),
+			}
 
-		message := anthropic.Message{}
+			req := ProviderRequest{
+				Model:          ec.config.Model,
+				System:         systemPrompt,
+				Messages:       messages,
+				Tools:          tools,
+				MaxTokens:      ec.config.MaxTokens,
+				ThinkingBudget: thinkingBudget,
+			}
 
-		// Process the stream
-		for stream.Next() {
-			event := stream.Current()
-			if err = message.Accumulate(event); err != nil {
+			var resp *ProviderResponse
+			step.Attempts, err = ec.withRetry(ctx, func(ctx context.Context) error {
+				var err error
+				resp, err = ec.provider.CreateMessage(ctx, req)
+				return err
+			})
+			step.RetryDuration = retryDuration(step.Attempts)
+			if err != nil {
 				step.Error = err.Error()
 				trace.Steps = append(trace.Steps, step)
-				return nil, fmt.Errorf("failed to accumulate event: %w", err)
+				return failed(err)
 			}
 
-			if evt, ok := event.AsAny().(anthropic.ContentBlockDeltaEvent); ok {
-				finalText.WriteString(evt.Delta.Text)
-			}
-		}
+			// Record step data from the response
+			step.StopReason = string(resp.StopReason)
+			step.InputTokens = resp.Usage.InputTokens
+			step.OutputTokens = resp.Usage.OutputTokens
 
-		if err = stream.Err(); err != nil {
-			step.Error = err.Error()
-			trace.Steps = append(trace.Steps, step)
-			return nil, fmt.Errorf("streaming error: %w", err)
-		}
+			// Capture cache metrics from API response
+			step.CacheCreationInputTokens = resp.Usage.CacheCreationInputTokens
+			step.CacheReadInputTokens = resp.Usage.CacheReadInputTokens
 
-		// Record step data from message
-		step.StopReason = string(message.StopReason)
-		step.InputTokens = int(message.Usage.InputTokens)
-		step.OutputTokens = int(message.Usage.OutputTokens)
+			// Extract text and thinking content, thinking blocks stay in the history below
+			step.ModelResponse = resp.Text()
+			step.Thinking = resp.Thinking()
+			turnText.WriteString(step.ModelResponse)
 
-		// Capture cache metrics from API response
-		step.CacheCreationInputTokens = int(message.Usage.CacheCreationInputTokens)
-		step.CacheReadInputTokens = int(message.Usage.CacheReadInputTokens)
+			// Add assistant message to history
+			messages = append(messages, Message{Role: RoleAssistant, Content: resp.Content})
 
-		// Extract text content
-		for _, block := range message.Content {
-			if textBlock, ok := block.AsAny().(anthropic.TextBlock); ok {
-				step.ModelResponse += textBlock.Text
+			// Check stop reason
+			if resp.StopReason == StopReasonEndTurn {
+				step.EndTime = time.Now()
+				step.Duration = step.EndTime.Sub(stepStart)
+				trace.Steps = append(trace.Steps, step)
+				// Model finished without tool use
+				break
 			}
-		}
 
-		// Add assistant message to history
-		messages = append(messages, message.ToParam())
+			if resp.StopReason != StopReasonToolUse {
+				step.EndTime = time.Now()
+				step.Duration = step.EndTime.Sub(stepStart)
+				trace.Steps = append(trace.Steps, step)
+				// Unexpected stop reason
+				break
+			}
 
-		// Check stop reason
-		if message.StopReason == anthropic.StopReasonEndTurn {
-			step.EndTime = time.Now()
-			step.Duration = step.EndTime.Sub(stepStart)
-			trace.Steps = append(trace.Steps, step)
-			// Model finished without tool use
-			break
-		}
+			// Execute tools and collect results
+			var toolResults []ContentBlock
+			for _, toolUse := range resp.ToolUses() {
+				// Execute and trace tool call, server requests made meanwhile belong to this eval
+				handler.inFlight.Add(1)
+				toolCall, toolResult := ec.executeAndTraceToolCall(ctx, toolUse, session, resourceTools)
+				handler.inFlight.Add(-1)
+				step.ToolCalls = append(step.ToolCalls, toolCall)
+				toolResults = append(toolResults, toolResult)
+			}
 
-		if message.StopReason != anthropic.StopReasonToolUse {
 			step.EndTime = time.Now()
 			step.Duration = step.EndTime.Sub(stepStart)
 			trace.Steps = append(trace.Steps, step)
-			// Unexpected stop reason
-			break
-		}
-
-		// Execute tools and collect results
-		var toolResults []anthropic.ContentBlockParamUnion
-		for _, block := range message.Content {
-			if variant, ok := block.AsAny().(anthropic.ToolUseBlock); ok {
-				// Execute and trace tool call
-				toolCall := ec.executeAndTraceToolCall(ctx, variant, session)
-				step.ToolCalls = append(step.ToolCalls, toolCall)
 
-				// Build result block for message history
-				var resultContent string
-				if toolCall.Success {
-					resultContent = string(toolCall.Output)
-				} else {
-					resultContent = fmt.Sprintf("Error calling tool: %s", toolCall.Error)
-				}
-
-				toolResults = append(toolResults, anthropic.NewToolResultBlock(
-					block.ID,
-					resultContent,
-					!toolCall.Success,
-				))
+			// If no tool results, we're done
+			if len(toolResults) == 0 {
+				break
 			}
+
+			// Add tool results to message history
+			messages = append(messages, Message{Role: RoleUser, Content: toolResults})
 		}
 
-		step.EndTime = time.Now()
-		step.Duration = step.EndTime.Sub(stepStart)
-		trace.Steps = append(trace.Steps, step)
+		finalText = turnText.String()
 
-		// If no tool results, we're done
-		if len(toolResults) == 0 {
+		if !eval.conversational() {
+			break
+		}
+		turn.LastStep = stepNumber
+		turn.Response = turnText.String()
+		turn.Duration = time.Since(turn.StartTime)
+		trace.Turns = append(trace.Turns, turn)
+		if stepNumber >= ec.config.MaxSteps {
+			log.Warn().
+				Str("eval", eval.Name).
+				Int("max_steps", ec.config.MaxSteps).
+				Msg("Conversation ended early, max_steps reached")
 			break
 		}
 
-		// Add tool results to message history
-		messages = append(messages, anthropic.NewUserMessage(toolResults...))
+		next, ok, err := ec.nextUserTurn(ctx, eval, trace.Turns)
+		if err != nil {
+			return failed(err)
+		}
+		if !ok {
+			break
+		}
+		turn = next
+		messages = appendUserText(messages, turn.UserMessage)
 	}
 
 	// Calculate trace metrics
-	trace.StepCount = len(trace.Steps)
-	for _, step := range trace.Steps {
-		trace.TotalInputTokens += step.InputTokens
-		trace.TotalOutputTokens += step.OutputTokens
-		trace.ToolCallCount += len(step.ToolCalls)
-
-		// Aggregate cache metrics
-		trace.TotalCacheCreationTokens += step.CacheCreationInputTokens
-		trace.TotalCacheReadTokens += step.CacheReadInputTokens
-	}
+	trace.summarize()
 
 	evalResult := &EvalResult{
 		Prompt:      eval.Prompt,
-		RawResponse: finalText.String(),
+		RawResponse: finalText,
 	}
 	result.Result = evalResult
 
+	// Run deterministic checks before grading, they don't need a model call
+	result.Assertions = CheckAssertions(eval.Assertions, evalResult.RawResponse, trace)
+	trace.ToolTrajectory = MatchToolCalls(eval.ExpectedTools, trace)
+
+	// Grading is skipped when the agent used up the budget
+	meter.update(trace, handler.Requests())
+	if err := meter.check(); err != nil {
+		return failed(err)
+	}
+
 	// Auto-grade the result with tracing
 	grade, gradingTrace, err := ec.gradeWithTrace(ctx, eval, evalResult, trace)
 	if err != nil {
 		// Don't fail the entire eval if grading fails, just log it
 		result.Error = fmt.Errorf("grading failed: %w", err)
 		trace.Grading = gradingTrace // Still include partial trace if available
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.Status = EvalStatusTimeout
+			trace.Status = EvalStatusTimeout
+		}
 	} else {
 		result.Grade = grade
 		trace.Grading = gradingTrace
@@ -457,6 +667,15 @@ func (ec *EvalClient) RunEval(ctx context.Context, eval Eval) (*EvalRunResult, e
 		}
 	}
 
+	// A failed assertion or tool trajectory fails the eval even when the grade passes
+	if checkErr := result.CheckFailures(); checkErr != nil {
+		log.Warn().
+			Str("eval", eval.Name).
+			Err(checkErr).
+			Msg("Eval failed deterministic checks")
+		result.Error = errors.Join(result.Error, checkErr)
+	}
+
 	// Include grading cache metrics in totals
 	if trace.Grading != nil {
 		trace.TotalCacheCreationTokens += trace.Grading.CacheCreationInputTokens
@@ -469,25 +688,87 @@ func (ec *EvalClient) RunEval(ctx context.Context, eval Eval) (*EvalRunResult, e
 	return result, nil
 }
 
+// traceCost prices the model requests recorded in trace
+func (ec *EvalClient) traceCost(trace *EvalTrace) *Cost {
+	return ec.config.Pricing.traceCost(trace, ec.config.Model, ec.config.CacheTTL)
+}
+
+// evalTimeout returns the deadline for a single eval, preferring the eval's own
+// timeout over the client default. Zero means no timeout.
+func (ec *EvalClient) evalTimeout(eval Eval) (time.Duration, error) {
+	if eval.Timeout == "" {
+		return ec.config.Timeout, nil
+	}
+	timeout, err := time.ParseDuration(eval.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout for eval '%s': %w", eval.Name, err)
+	}
+	return timeout, nil
+}
+
+// EvalProgressFunc receives progress notifications from RunEvalsWithProgress.
+// It is called with a nil result when an eval starts and with the final result
+// when it completes. Calls are serialized, so implementations may write to
+// shared output without additional locking.
+type EvalProgressFunc func(index int, eval Eval, result *EvalRunResult)
+
 // RunEvals executes multiple evaluations and returns all results.
-// Each eval reuses the same MCP session for efficiency.
+// Up to Concurrency evals run in parallel and the trials of an eval run one after
+// another. SessionMode decides whether each run starts its own MCP session or
+// reuses one from the client's pool.
 // Individual eval failures are captured in EvalRunResult.Error and don't stop the batch.
 func (ec *EvalClient) RunEvals(ctx context.Context, evals []Eval) ([]EvalRunResult, error) {
+	return ec.RunEvalsWithProgress(ctx, evals, nil)
+}
+
+// RunEvalsWithProgress is like RunEvals but reports each eval's start and
+// completion to progress. Results are returned in the same order as evals
+// regardless of the order in which they complete.
+func (ec *EvalClient) RunEvalsWithProgress(ctx context.Context, evals []Eval, progress EvalProgressFunc) ([]EvalRunResult, error) {
 	results := make([]EvalRunResult, len(evals))
 
-	for i, eval := range evals {
-		result, err := ec.RunEval(ctx, eval)
-		if err != nil {
-			// Capture error but continue with other evals
-			results[i] = EvalRunResult{
-				Eval:  eval,
-				Error: err,
-			}
-			continue
+	var progressMu sync.Mutex
+	notify := func(i int, result *EvalRunResult) {
+		if progress == nil {
+			return
 		}
-		results[i] = *result
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		progress(i, evals[i], result)
+	}
+
+	workers := min(max(ec.config.Concurrency, 1), len(evals))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				notify(i, nil)
+
+				result, err := ec.RunEvalTrials(ctx, evals[i])
+				if err != nil {
+					// Capture error but continue with other evals
+					result = &EvalRunResult{
+						Eval:  evals[i],
+						Error: err,
+					}
+				}
+				results[i] = *result
+
+				notify(i, &results[i])
+			}
+		}()
 	}
 
+	for i := range evals {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
 	return results, nil
 }
 
@@ -532,9 +813,15 @@ func (ec *EvalClient) formatDimensionCriteria(dimension string, criteria *Dimens
 func (ec *EvalClient) buildGradingPrompt(eval Eval, evalResult *EvalResult, execTrace *EvalTrace) string {
 	var prompt strings.Builder
 
-	// Standard context
-	prompt.WriteString(fmt.Sprintf("Here is the user input: %s\n", evalResult.Prompt))
-	prompt.WriteString(fmt.Sprintf("Here is the LLM's answer: %s\n", evalResult.RawResponse))
+	// Standard context, conversational evals are graded on the whole conversation
+	if execTrace != nil && len(execTrace.Turns) > 1 {
+		prompt.WriteString("Here is the conversation between the user and the LLM:\n\n")
+		prompt.WriteString(formatTranscript(execTrace.Turns))
+		prompt.WriteString("\n\nGrade the LLM's answers across the whole conversation, including how it handled the user's follow-up messages.\n")
+	} else {
+		prompt.WriteString(fmt.Sprintf("Here is the user input: %s\n", evalResult.Prompt))
+		prompt.WriteString(fmt.Sprintf("Here is the LLM's answer: %s\n", evalResult.RawResponse))
+	}
 
 	// Add tool execution context
 	if execTrace != nil && execTrace.ToolCallCount > 0 {
@@ -602,37 +889,34 @@ func (ec *EvalClient) gradeWithTrace(ctx context.Context, eval Eval, evalResult
 	gradingPrompt := ec.buildGradingPrompt(eval, evalResult, execTrace)
 	trace.GradingPrompt = gradingPrompt
 
-	// Determine which model to use for grading
-	gradingModel := ec.config.Model
-	if ec.config.GradingModel != "" {
-		gradingModel = ec.config.GradingModel
-	}
+	gradingModel := ec.config.gradingModel()
+	trace.Model = gradingModel
 
-	// Build grading system prompt with optional cache control
-	gradingSystemPrompt := anthropic.TextBlockParam{
-		Text: EvalSystemPrompt,
-	}
-	if ec.config.EnablePromptCaching != nil && *ec.config.EnablePromptCaching {
-		gradingSystemPrompt.CacheControl = anthropic.NewCacheControlEphemeralParam()
-		if ec.config.CacheTTL == "1h" {
-			gradingSystemPrompt.CacheControl.TTL = "1h"
-		}
+	systemPrompt := EvalSystemPrompt
+	if ec.config.Grader.SystemPrompt != "" {
+		systemPrompt = ec.config.Grader.SystemPrompt
 	}
 
 	// Execute grading
-	resp, err := ec.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     anthropic.Model(gradingModel),
-		MaxTokens: 1000,
-		System: []anthropic.TextBlockParam{
-			gradingSystemPrompt,
-		},
-		Messages: []anthropic.MessageParam{
-			anthropic.NewUserMessage(anthropic.NewTextBlock(gradingPrompt)),
-		},
+	var resp *ProviderResponse
+	attempts, err := ec.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = ec.grader.CreateMessage(ctx, ProviderRequest{
+			Model:       gradingModel,
+			System:      systemPrompt,
+			MaxTokens:   ec.config.Grader.MaxTokens,
+			Temperature: ec.config.Grader.Temperature,
+			Messages: []Message{
+				{Role: RoleUser, Content: []ContentBlock{TextBlock(gradingPrompt)}},
+			},
+		})
+		return err
 	})
 
 	trace.EndTime = time.Now()
 	trace.Duration = trace.EndTime.Sub(trace.StartTime)
+	trace.Attempts = attempts
+	trace.RetryDuration = retryDuration(attempts)
 
 	if err != nil {
 		trace.Error = err.Error()
@@ -640,14 +924,14 @@ func (ec *EvalClient) gradeWithTrace(ctx context.Context, eval Eval, evalResult
 	}
 
 	// Capture raw response and token usage
-	rawResponse := resp.Content[0].AsAny().(anthropic.TextBlock).Text
+	rawResponse := resp.Text()
 	trace.RawGradingOutput = rawResponse
-	trace.InputTokens = int(resp.Usage.InputTokens)
-	trace.OutputTokens = int(resp.Usage.OutputTokens)
+	trace.InputTokens = resp.Usage.InputTokens
+	trace.OutputTokens = resp.Usage.OutputTokens
 
 	// Capture cache metrics from API response
-	trace.CacheCreationInputTokens = int(resp.Usage.CacheCreationInputTokens)
-	trace.CacheReadInputTokens = int(resp.Usage.CacheReadInputTokens)
+	trace.CacheCreationInputTokens = resp.Usage.CacheCreationInputTokens
+	trace.CacheReadInputTokens = resp.Usage.CacheReadInputTokens
 
 	// Parse grade result
 	cleanedResponse, err := extractJSONFromResponse(rawResponse)
@@ -666,8 +950,8 @@ func (ec *EvalClient) gradeWithTrace(ctx context.Context, eval Eval, evalResult
 }
 
 type EvalResult struct {
-	Prompt      string
-	RawResponse string
+	Prompt      string `json:"prompt"`
+	RawResponse string `json:"raw_response"`
 }
 
 type GradeResult struct {
@@ -679,14 +963,32 @@ type GradeResult struct {
 	OverallComment string `json:"overall_comments"`
 }
 
+// Average returns the mean of the five dimension scores
+func (g *GradeResult) Average() float64 {
+	sum := g.Accuracy + g.Completeness + g.Relevance + g.Clarity + g.Reasoning
+	return float64(sum) / 5.0
+}
+
 // Eval represents a single evaluation test case
 type Eval struct {
-	Name              string         `yaml:"name" json:"name" jsonschema:"Unique identifier for this evaluation"`
-	Description       string         `yaml:"description,omitempty" json:"description,omitempty" jsonschema:"Human-readable description of what this eval tests"`
-	Prompt            string         `yaml:"prompt" json:"prompt" jsonschema:"The input prompt to send to the LLM"`
-	ExpectedResult    string         `yaml:"expected_result,omitempty" json:"expected_result,omitempty" jsonschema:"Expected behavior or result (used for documentation and grading context)"`
-	AgentSystemPrompt string         `yaml:"agent_system_prompt,omitempty" json:"agent_system_prompt,omitempty" jsonschema:"Optional custom system prompt for the agent (overrides global default)"`
-	GradingRubric     *GradingRubric `yaml:"grading_rubric,omitempty" json:"grading_rubric,omitempty" jsonschema:"Optional custom grading criteria for this evaluation"`
+	Name              string                `yaml:"name" json:"name" jsonschema:"Unique identifier for this evaluation"`
+	Description       string                `yaml:"description,omitempty" json:"description,omitempty" jsonschema:"Human-readable description of what this eval tests"`
+	Tags              []string              `yaml:"tags,omitempty" json:"tags,omitempty" jsonschema:"Labels for selecting evals with --tag, --exclude-tag and --select, e.g. smoke or slow"`
+	Prompt            string                `yaml:"prompt,omitempty" json:"prompt,omitempty" jsonschema:"The input prompt to send to the LLM (sent after the prompt_ref messages when both are set)"`
+	PromptRef         *PromptRef            `yaml:"prompt_ref,omitempty" json:"prompt_ref,omitempty" jsonschema:"Start the conversation from a prompt defined by the MCP server"`
+	Turns             []string              `yaml:"turns,omitempty" json:"turns,omitempty" jsonschema:"Scripted follow-up user messages, each sent after the agent answers the previous one"`
+	SimulatedUser     *SimulatedUser        `yaml:"simulated_user,omitempty" json:"simulated_user,omitempty" jsonschema:"LLM-simulated user that continues the conversation after the scripted turns"`
+	Elicitation       []ElicitationResponse `yaml:"elicitation,omitempty" json:"elicitation,omitempty" jsonschema:"Scripted answers to elicitation requests from the MCP server; unmatched requests are declined"`
+	ExpectedResult    string                `yaml:"expected_result,omitempty" json:"expected_result,omitempty" jsonschema:"Expected behavior or result (used for documentation and grading context)"`
+	AgentSystemPrompt string                `yaml:"agent_system_prompt,omitempty" json:"agent_system_prompt,omitempty" jsonschema:"Optional custom system prompt for the agent (overrides global default)"`
+	Thinking          *ThinkingConfig       `yaml:"thinking,omitempty" json:"thinking,omitempty" jsonschema:"Extended thinking for this evaluation (overrides the global thinking; budget_tokens 0 disables it)"`
+	GradingRubric     *GradingRubric        `yaml:"grading_rubric,omitempty" json:"grading_rubric,omitempty" jsonschema:"Optional custom grading criteria for this evaluation"`
+	Timeout           string                `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"Optional timeout for this evaluation (overrides the global timeout, e.g. '5m')"`
+	Assertions        []Assertion           `yaml:"assertions,omitempty" json:"assertions,omitempty" jsonschema:"Deterministic checks on the final response and tool calls; any failure fails the eval regardless of grade"`
+	ExpectedTools     *ExpectedTools        `yaml:"expected_tools,omitempty" json:"expected_tools,omitempty" jsonschema:"Tool calls the agent is expected to make; a mismatch fails the eval regardless of grade"`
+	Trials            int                   `yaml:"trials,omitempty" json:"trials,omitempty" jsonschema:"Number of times to run this eval (overrides the global trials)"`
+	TrialPass         *TrialPolicy          `yaml:"trial_pass,omitempty" json:"trial_pass,omitempty" jsonschema:"How this eval's trials decide pass/fail (overrides the global trial_pass)"`
+	Budget            *Budget               `yaml:"budget,omitempty" json:"budget,omitempty" jsonschema:"Token, cost and time limits for a single run of this eval; it stops between steps once one is reached"`
 }
 
 // GradingRubric defines specific evaluation criteria for grading
@@ -779,42 +1081,127 @@ func (r *GradingRubric) CheckMinimumScores(grade *GradeResult) error {
 	return nil
 }
 
+// EvalStatus records why an eval stopped before running to completion
+type EvalStatus string
+
+const (
+	EvalStatusTimeout        EvalStatus = "timeout"         // The eval or suite deadline was exceeded
+	EvalStatusBudgetExceeded EvalStatus = "budget_exceeded" // The eval or suite budget ran out, see Budget
+)
+
 // EvalRunResult combines the eval configuration with its execution results
 type EvalRunResult struct {
-	Eval   Eval
-	Result *EvalResult
-	Grade  *GradeResult
-	Error  error
-	Trace  *EvalTrace // Complete execution trace for debugging and analysis
+	Eval       Eval              `json:"eval"`
+	Result     *EvalResult       `json:"result,omitempty"`
+	Grade      *GradeResult      `json:"grade,omitempty"`
+	Error      error             `json:"-"`                    // Marshaled as the "error" message string, see MarshalJSON
+	Status     EvalStatus        `json:"status,omitempty"`     // Set when the eval was cut short, empty otherwise
+	Assertions []AssertionResult `json:"assertions,omitempty"` // Outcome of each deterministic assertion, in config order
+	Trace      *EvalTrace        `json:"trace,omitempty"`      // Complete execution trace for debugging and analysis
+	Trials     []EvalRunResult   `json:"trials,omitempty"`     // Every run when the eval has more than one trial, see RunEvalTrials
+	Aggregate  *TrialAggregate   `json:"aggregate,omitempty"`  // Summary of Trials, nil for a single run
+}
+
+// MarshalJSON encodes the result with Error as its message in an "error" string field,
+// since error values have no JSON form of their own
+func (r EvalRunResult) MarshalJSON() ([]byte, error) {
+	type alias EvalRunResult
+	encoded := struct {
+		alias
+		Error string `json:"error,omitempty"`
+	}{alias: alias(r)}
+	if r.Error != nil {
+		encoded.Error = r.Error.Error()
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes a result written by MarshalJSON. The error message is
+// restored as a plain error, its original type is not preserved.
+func (r *EvalRunResult) UnmarshalJSON(data []byte) error {
+	type alias EvalRunResult
+	decoded := struct {
+		*alias
+		Error string `json:"error,omitempty"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	r.Error = nil
+	if decoded.Error != "" {
+		r.Error = errors.New(decoded.Error)
+	}
+	return nil
+}
+
+// CheckFailures returns an error describing failed assertions and tool trajectory
+// mismatches, or nil if every deterministic check passed
+func (r EvalRunResult) CheckFailures() error {
+	var trajectoryErr error
+	if r.Trace != nil {
+		trajectoryErr = r.Trace.ToolTrajectory.Err()
+	}
+	return errors.Join(AssertionFailures(r.Assertions), trajectoryErr)
 }
 
 // EvalTrace captures complete execution history of an evaluation run
 type EvalTrace struct {
-	Steps                    []AgenticStep `json:"steps"`                       // Each step in the agentic loop
-	Grading                  *GradingTrace `json:"grading,omitempty"`           // Grading interaction details
-	TotalDuration            time.Duration `json:"total_duration"`              // Total execution time
-	TotalInputTokens         int           `json:"total_input_tokens"`          // Sum of input tokens across all steps
-	TotalOutputTokens        int           `json:"total_output_tokens"`         // Sum of output tokens across all steps
-	StepCount                int           `json:"step_count"`                  // Number of agentic steps executed
-	ToolCallCount            int           `json:"tool_call_count"`             // Total number of tool calls made
-	TotalCacheCreationTokens int           `json:"total_cache_creation_tokens"` // Sum of cache creation tokens across all steps
-	TotalCacheReadTokens     int           `json:"total_cache_read_tokens"`     // Sum of cache read tokens across all steps
+	Steps                    []AgenticStep         `json:"steps"`                       // Each step in the agentic loop
+	Grading                  *GradingTrace         `json:"grading,omitempty"`           // Grading interaction details
+	TotalDuration            time.Duration         `json:"total_duration"`              // Total execution time
+	TotalInputTokens         int                   `json:"total_input_tokens"`          // Sum of input tokens across all steps
+	TotalOutputTokens        int                   `json:"total_output_tokens"`         // Sum of output tokens across all steps
+	StepCount                int                   `json:"step_count"`                  // Number of agentic steps executed
+	ToolCallCount            int                   `json:"tool_call_count"`             // Total number of tool calls made
+	TotalCacheCreationTokens int                   `json:"total_cache_creation_tokens"` // Sum of cache creation tokens across all steps
+	TotalCacheReadTokens     int                   `json:"total_cache_read_tokens"`     // Sum of cache read tokens across all steps
+	Status                   EvalStatus            `json:"status,omitempty"`            // Set when the eval was cut short (e.g. timeout)
+	ToolTrajectory           *ToolTrajectoryResult `json:"tool_trajectory,omitempty"`   // Comparison with the eval's expected tools, if any
+	SchemaWarnings           []string              `json:"schema_warnings,omitempty"`   // Tool schema constructs dropped or loosened for the model API
+	Tools                    []*mcp.Tool           `json:"tools,omitempty"`             // Tools the MCP server offered, served again by NewMockServer
+	Prompt                   *PromptTrace          `json:"prompt,omitempty"`            // Server prompt the eval started from, if any
+	ServerRequests           []ServerRequest       `json:"server_requests,omitempty"`   // Sampling, elicitation and roots requests made by the MCP server
+	Turns                    []TurnTrace           `json:"turns,omitempty"`             // User turns of a conversational eval and the steps answering each
+	Cost                     *Cost                 `json:"cost,omitempty"`              // USD cost of the agent and grading requests
+}
+
+// summarize recalculates the step, token and tool call totals from the recorded steps
+func (t *EvalTrace) summarize() {
+	t.StepCount = len(t.Steps)
+	t.TotalInputTokens = 0
+	t.TotalOutputTokens = 0
+	t.ToolCallCount = 0
+	t.TotalCacheCreationTokens = 0
+	t.TotalCacheReadTokens = 0
+
+	for _, step := range t.Steps {
+		t.TotalInputTokens += step.InputTokens
+		t.TotalOutputTokens += step.OutputTokens
+		t.ToolCallCount += len(step.ToolCalls)
+
+		// Aggregate cache metrics
+		t.TotalCacheCreationTokens += step.CacheCreationInputTokens
+		t.TotalCacheReadTokens += step.CacheReadInputTokens
+	}
 }
 
 // AgenticStep records a single iteration of the agentic loop
 type AgenticStep struct {
-	StepNumber               int           `json:"step_number"`                 // 1-indexed step number
-	StartTime                time.Time     `json:"start_time"`                  // When this step started
-	EndTime                  time.Time     `json:"end_time"`                    // When this step completed
-	Duration                 time.Duration `json:"duration"`                    // Step execution duration
-	ModelResponse            string        `json:"model_response"`              // Text content from assistant
-	StopReason               string        `json:"stop_reason"`                 // end_turn, tool_use, max_tokens, etc.
-	ToolCalls                []ToolCall    `json:"tool_calls"`                  // Tools executed in this step
-	InputTokens              int           `json:"input_tokens"`                // Input tokens for this step
-	OutputTokens             int           `json:"output_tokens"`               // Output tokens for this step
-	CacheCreationInputTokens int           `json:"cache_creation_input_tokens"` // Tokens used to create cache
-	CacheReadInputTokens     int           `json:"cache_read_input_tokens"`     // Tokens read from cache
-	Error                    string        `json:"error,omitempty"`             // Error message if step failed
+	StepNumber               int              `json:"step_number"`                 // 1-indexed step number
+	StartTime                time.Time        `json:"start_time"`                  // When this step started
+	EndTime                  time.Time        `json:"end_time"`                    // When this step completed
+	Duration                 time.Duration    `json:"duration"`                    // Step execution duration
+	ModelResponse            string           `json:"model_response"`              // Text content from assistant
+	Thinking                 string           `json:"thinking,omitempty"`          // Extended thinking text from assistant, if enabled
+	StopReason               string           `json:"stop_reason"`                 // end_turn, tool_use, max_tokens, etc.
+	ToolCalls                []ToolCall       `json:"tool_calls"`                  // Tools executed in this step
+	InputTokens              int              `json:"input_tokens"`                // Input tokens for this step
+	OutputTokens             int              `json:"output_tokens"`               // Output tokens for this step
+	CacheCreationInputTokens int              `json:"cache_creation_input_tokens"` // Tokens used to create cache
+	CacheReadInputTokens     int              `json:"cache_read_input_tokens"`     // Tokens read from cache
+	Attempts                 []RequestAttempt `json:"attempts,omitempty"`          // Each model API attempt, including retries
+	RetryDuration            time.Duration    `json:"retry_duration,omitempty"`    // Time spent on failed attempts and backoff
+	Error                    string           `json:"error,omitempty"`             // Error message if step failed
 }
 
 // ToolCall captures details of a single tool invocation
@@ -828,23 +1215,28 @@ type ToolCall struct {
 	Output    json.RawMessage `json:"output"`          // Tool result as JSON
 	Success   bool            `json:"success"`         // Whether tool executed successfully
 	Error     string          `json:"error,omitempty"` // Error message if tool failed
+
+	StructuredContent json.RawMessage `json:"structured_content,omitempty"` // Structured result returned by the tool, if any
 }
 
 // GradingTrace records the grading interaction with the LLM
 type GradingTrace struct {
-	UserPrompt               string        `json:"user_prompt"`                 // Original eval prompt
-	ModelResponse            string        `json:"model_response"`              // Model's answer being graded
-	ExpectedResult           string        `json:"expected_result"`             // Expected result description
-	GradingPrompt            string        `json:"grading_prompt"`              // Full prompt sent to grader
-	RawGradingOutput         string        `json:"raw_grading_output"`          // Complete LLM response before parsing
-	StartTime                time.Time     `json:"start_time"`                  // When grading started
-	EndTime                  time.Time     `json:"end_time"`                    // When grading completed
-	Duration                 time.Duration `json:"duration"`                    // Grading duration
-	InputTokens              int           `json:"input_tokens"`                // Input tokens for grading
-	OutputTokens             int           `json:"output_tokens"`               // Output tokens for grading
-	CacheCreationInputTokens int           `json:"cache_creation_input_tokens"` // Tokens used to create cache
-	CacheReadInputTokens     int           `json:"cache_read_input_tokens"`     // Tokens read from cache
-	Error                    string        `json:"error,omitempty"`             // Error message if grading failed
+	Model                    string           `json:"model,omitempty"`             // Model used for grading
+	UserPrompt               string           `json:"user_prompt"`                 // Original eval prompt
+	ModelResponse            string           `json:"model_response"`              // Model's answer being graded
+	ExpectedResult           string           `json:"expected_result"`             // Expected result description
+	GradingPrompt            string           `json:"grading_prompt"`              // Full prompt sent to grader
+	RawGradingOutput         string           `json:"raw_grading_output"`          // Complete LLM response before parsing
+	StartTime                time.Time        `json:"start_time"`                  // When grading started
+	EndTime                  time.Time        `json:"end_time"`                    // When grading completed
+	Duration                 time.Duration    `json:"duration"`                    // Grading duration
+	InputTokens              int              `json:"input_tokens"`                // Input tokens for grading
+	OutputTokens             int              `json:"output_tokens"`               // Output tokens for grading
+	CacheCreationInputTokens int              `json:"cache_creation_input_tokens"` // Tokens used to create cache
+	CacheReadInputTokens     int              `json:"cache_read_input_tokens"`     // Tokens read from cache
+	Attempts                 []RequestAttempt `json:"attempts,omitempty"`          // Each grading API attempt, including retries
+	RetryDuration            time.Duration    `json:"retry_duration,omitempty"`    // Time spent on failed attempts and backoff
+	Error                    string           `json:"error,omitempty"`             // Error message if grading failed
 }
 
 // toPtr returns a pointer to the provided value.
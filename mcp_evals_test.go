@@ -3,10 +3,17 @@ package evaluations
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/require"
+	"github.com/wolfeidau/mcp-evals/testdata/mcp-test-server/testserver"
 	"gopkg.in/yaml.v3"
 )
 
@@ -24,11 +31,16 @@ func TestEvalClient_loadMCPSession(t *testing.T) {
 			args:    []string{"run", "testdata/mcp-test-server/main.go"},
 			expectedTools: []string{
 				"add",
+				"divide",
+				"get_logo",
 				"echo",
 				"get_current_time",
 				"get_env",
 				"get_user",
 				"get_system_logs",
+				"summarize",
+				"delete_service",
+				"list_workspace",
 			},
 			expectError: false,
 		},
@@ -50,7 +62,7 @@ func TestEvalClient_loadMCPSession(t *testing.T) {
 			})
 
 			ctx := context.Background()
-			session, toolsResp, err := client.loadMCPSession(ctx)
+			session, toolsResp, err := client.loadMCPSession(ctx, nil)
 
 			if tt.expectError {
 				assert.Error(err)
@@ -90,7 +102,7 @@ func TestEvalClient_loadMCPSession_ToolExecution(t *testing.T) {
 	})
 
 	ctx := context.Background()
-	session, _, err := client.loadMCPSession(ctx)
+	session, _, err := client.loadMCPSession(ctx, nil)
 	assert.NoError(err)
 	defer func() { _ = session.Close() }()
 
@@ -133,7 +145,7 @@ func TestEvalClient_loadMCPSession_CustomEnv(t *testing.T) {
 	})
 
 	ctx := context.Background()
-	session, _, err := client.loadMCPSession(ctx)
+	session, _, err := client.loadMCPSession(ctx, nil)
 	assert.NoError(err)
 	defer func() { _ = session.Close() }()
 
@@ -185,6 +197,176 @@ func TestEvalClient_loadMCPSession_CustomEnv(t *testing.T) {
 	assert.NotEmpty(output2.Value)
 }
 
+func TestEvalClient_loadMCPSession_HTTPTransports(t *testing.T) {
+	getServer := func(*http.Request) *mcp.Server { return testserver.New() }
+
+	tests := []struct {
+		name      string
+		transport MCPTransport
+		handler   http.Handler
+	}{
+		{
+			name:      "streamable http",
+			transport: TransportStreamableHTTP,
+			handler:   mcp.NewStreamableHTTPHandler(getServer, nil),
+		},
+		{
+			name:      "sse",
+			transport: TransportSSE,
+			handler:   mcp.NewSSEHandler(getServer, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			// Record the auth header seen on each request to verify headers are forwarded
+			var mu sync.Mutex
+			var authHeaders []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+				mu.Unlock()
+				tt.handler.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			client := NewEvalClient(EvalClientConfig{
+				Transport: tt.transport,
+				URL:       srv.URL,
+				Headers:   map[string]string{"Authorization": "Bearer test-token"},
+			})
+
+			ctx := context.Background()
+			session, toolsResp, err := client.loadMCPSession(ctx, nil)
+			assert.NoError(err)
+			defer func() { _ = session.Close() }()
+
+			assert.Len(toolsResp.Tools, 11)
+
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "add",
+				Arguments: map[string]any{"a": 5, "b": 3},
+			})
+			assert.NoError(err)
+
+			textContent, ok := result.Content[0].(*mcp.TextContent)
+			assert.True(ok, "expected text content but got %T", result.Content[0])
+			assert.JSONEq(`{"result":8}`, textContent.Text)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.NotEmpty(authHeaders)
+			for _, header := range authHeaders {
+				assert.Equal("Bearer test-token", header)
+			}
+		})
+	}
+}
+
+func TestEvalClient_loadMCPSession_MissingURL(t *testing.T) {
+	assert := require.New(t)
+
+	client := NewEvalClient(EvalClientConfig{
+		Transport: TransportStreamableHTTP,
+	})
+
+	_, _, err := client.loadMCPSession(context.Background(), nil)
+	assert.Error(err)
+	assert.Contains(err.Error(), "url is required")
+}
+
+func TestEvalClient_RunEvalsWithProgress_Concurrency(t *testing.T) {
+	assert := require.New(t)
+
+	// A command that cannot start makes every eval fail fast without calling the API
+	client := NewEvalClient(EvalClientConfig{
+		Command:     "nonexistent-command",
+		Concurrency: 4,
+	})
+
+	evals := make([]Eval, 10)
+	for i := range evals {
+		evals[i] = Eval{Name: fmt.Sprintf("eval_%d", i), Prompt: "test"}
+	}
+
+	started := make(map[int]bool)
+	completed := make(map[int]bool)
+	active := 0
+	results, err := client.RunEvalsWithProgress(context.Background(), evals, func(i int, eval Eval, result *EvalRunResult) {
+		// Calls are serialized, so this must never observe another call in flight
+		active++
+		defer func() { active-- }()
+		assert.Equal(1, active)
+
+		assert.Equal(evals[i].Name, eval.Name)
+		if result == nil {
+			started[i] = true
+			return
+		}
+		assert.True(started[i], "eval %d completed before it started", i)
+		completed[i] = true
+	})
+	assert.NoError(err)
+
+	assert.Len(results, len(evals))
+	assert.Len(completed, len(evals))
+	for i, result := range results {
+		assert.Equal(evals[i].Name, result.Eval.Name, "results must keep input order")
+		assert.Error(result.Error)
+	}
+}
+
+func TestEvalClient_RunEval_Timeout(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+
+	// Fake Anthropic API that never answers before the client gives up
+	release := make(chan struct{})
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer apiServer.Close()
+	defer close(release) // unblock any request still held open so Close doesn't wait on it
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   apiServer.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		Timeout:   time.Hour,
+	})
+
+	// The per-eval override takes precedence over the client default
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:    "slow",
+		Prompt:  "What is 5 plus 3?",
+		Timeout: "200ms",
+	})
+	assert.NoError(err)
+	assert.Equal(EvalStatusTimeout, result.Status)
+	assert.Error(result.Error)
+	assert.Contains(result.Error.Error(), "timed out after 200ms")
+	assert.NotNil(result.Trace)
+	assert.Equal(EvalStatusTimeout, result.Trace.Status)
+}
+
+func TestEvalClient_RunEval_InvalidTimeout(t *testing.T) {
+	assert := require.New(t)
+
+	client := NewEvalClient(EvalClientConfig{Command: "nonexistent-command"})
+
+	_, err := client.RunEval(context.Background(), Eval{Name: "bad", Prompt: "test", Timeout: "soon"})
+	assert.Error(err)
+	assert.Contains(err.Error(), "invalid timeout")
+}
+
 func TestGradingRubricParsing(t *testing.T) {
 	assert := require.New(t)
 
@@ -600,3 +782,259 @@ func TestGradingRubricCheckMinimumScores(t *testing.T) {
 		})
 	}
 }
+
+func TestEvalClient_RunEval_SeparateGrader(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	agentAPI := newFakeModelAPI(t, streamTextResponse("5 plus 3 is 8"))
+	graderAPI := newFakeModelAPI(t, chatGradeResponse(4))
+
+	temperature := 0.0
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:       "agent-key",
+		BaseURL:      agentAPI.URL,
+		Transport:    TransportStreamableHTTP,
+		URL:          mcpServer.URL,
+		Model:        "agent-model",
+		GradingModel: "ignored-grading-model",
+		Grader: GraderConfig{
+			Provider:     ProviderOpenAI,
+			Model:        "judge-model",
+			BaseURL:      graderAPI.URL,
+			APIKey:       "judge-key",
+			MaxTokens:    500,
+			Temperature:  &temperature,
+			SystemPrompt: "You are a strict judge. Respond with the JSON score format.",
+		},
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{Name: "add", Prompt: "What is 5 plus 3?"})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+	assert.NotNil(result.Grade)
+	assert.Equal(4, result.Grade.Accuracy)
+	assert.Equal("judge-model", result.Trace.Grading.Model)
+
+	// The agent API only sees the agent conversation
+	agentRequests := agentAPI.Requests()
+	assert.Len(agentRequests, 1)
+	assert.Equal("agent-model", agentRequests[0]["model"])
+
+	graderRequests := graderAPI.Requests()
+	assert.Len(graderRequests, 1)
+	grading := graderRequests[0]
+	assert.Equal("judge-model", grading["model"])
+	assert.EqualValues(500, grading["max_tokens"])
+	assert.Contains(grading, "temperature")
+	assert.EqualValues(0, grading["temperature"])
+	system := grading["messages"].([]any)[0].(map[string]any)
+	assert.Equal("system", system["role"])
+	assert.Equal("You are a strict judge. Respond with the JSON score format.", system["content"])
+}
+
+func TestEvalClientConfig_graderClientConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     EvalClientConfig
+		wantConfig EvalClientConfig
+		wantModel  string
+	}{
+		{
+			name:       "inherits agent settings by default",
+			config:     EvalClientConfig{Provider: ProviderAnthropic, APIKey: "agent-key", BaseURL: "http://agent", Model: "agent-model"},
+			wantConfig: EvalClientConfig{Provider: ProviderAnthropic, APIKey: "agent-key", BaseURL: "http://agent"},
+			wantModel:  "agent-model",
+		},
+		{
+			name:       "grading model overrides agent model",
+			config:     EvalClientConfig{Provider: ProviderAnthropic, Model: "agent-model", GradingModel: "grading-model"},
+			wantConfig: EvalClientConfig{Provider: ProviderAnthropic},
+			wantModel:  "grading-model",
+		},
+		{
+			name: "same provider overrides key and endpoint",
+			config: EvalClientConfig{
+				Provider: ProviderOpenAI, APIKey: "agent-key", BaseURL: "http://agent", Model: "agent-model", GradingModel: "grading-model",
+				Grader: GraderConfig{Provider: ProviderOpenAI, APIKey: "judge-key", Model: "judge-model"},
+			},
+			wantConfig: EvalClientConfig{Provider: ProviderOpenAI, APIKey: "judge-key", BaseURL: "http://agent"},
+			wantModel:  "judge-model",
+		},
+		{
+			name: "different provider does not inherit key or endpoint",
+			config: EvalClientConfig{
+				Provider: ProviderAnthropic, APIKey: "agent-key", BaseURL: "http://agent", Model: "agent-model",
+				Grader: GraderConfig{Provider: ProviderOpenAI},
+			},
+			wantConfig: EvalClientConfig{Provider: ProviderOpenAI},
+			wantModel:  "agent-model",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			grader := tt.config.graderClientConfig()
+			assert.Equal(tt.wantConfig.Provider, grader.Provider)
+			assert.Equal(tt.wantConfig.APIKey, grader.APIKey)
+			assert.Equal(tt.wantConfig.BaseURL, grader.BaseURL)
+			assert.Equal(tt.wantModel, tt.config.gradingModel())
+		})
+	}
+}
+
+func TestEvalRunResult_JSON(t *testing.T) {
+	assert := require.New(t)
+
+	result := EvalRunResult{
+		Eval:   Eval{Name: "add", Prompt: "What is 5 plus 3?"},
+		Result: &EvalResult{Prompt: "What is 5 plus 3?", RawResponse: "8"},
+		Grade:  &GradeResult{Accuracy: 5, Completeness: 4, Relevance: 5, Clarity: 5, Reasoning: 4},
+		Error:  fmt.Errorf("grading failed: %w", context.DeadlineExceeded),
+		Status: EvalStatusTimeout,
+		Trace:  &EvalTrace{StepCount: 1},
+	}
+
+	data, err := json.Marshal(result)
+	assert.NoError(err)
+
+	var raw map[string]any
+	assert.NoError(json.Unmarshal(data, &raw))
+	assert.Equal("grading failed: context deadline exceeded", raw["error"])
+	assert.Equal("timeout", raw["status"])
+	assert.Equal("8", raw["result"].(map[string]any)["raw_response"])
+	assert.NotContains(raw, "trials")
+
+	var decoded EvalRunResult
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.EqualError(decoded.Error, "grading failed: context deadline exceeded")
+	assert.Equal(result.Eval.Name, decoded.Eval.Name)
+	assert.Equal(result.Grade, decoded.Grade)
+	assert.Equal(EvalStatusTimeout, decoded.Status)
+	assert.Equal(1, decoded.Trace.StepCount)
+
+	// A result without an error omits the field and decodes to a nil error
+	data, err = json.Marshal(EvalRunResult{Eval: Eval{Name: "ok"}, Trials: []EvalRunResult{{Error: errors.New("trial failed")}}})
+	assert.NoError(err)
+	assert.NotContains(string(data), `"error":""`)
+	decoded = EvalRunResult{Error: errors.New("stale")}
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.NoError(decoded.Error)
+	assert.EqualError(decoded.Trials[0].Error, "trial failed")
+}
+
+func TestEvalClient_RunEval_ToolResults(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamToolUseResponse("toolu_1", "divide", `{"a":1,"b":0}`),
+		streamToolUseResponse("toolu_2", "get_logo", `{}`),
+		streamToolUseResponse("toolu_3", "add", `{"a":5,"b":3}`),
+		streamTextResponse("done"),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{Name: "tools", Prompt: "Use the tools"})
+	assert.NoError(err)
+	assert.Len(result.Trace.Steps, 4)
+
+	// A tool error reported in the result fails the call
+	divide := result.Trace.Steps[0].ToolCalls[0]
+	assert.False(divide.Success)
+	assert.Contains(divide.Error, "division by zero")
+
+	logo := result.Trace.Steps[1].ToolCalls[0]
+	assert.True(logo.Success)
+	assert.Contains(string(logo.Output), "[Image: image/png]")
+
+	add := result.Trace.Steps[2].ToolCalls[0]
+	assert.True(add.Success)
+	assert.JSONEq(`{"result":8}`, string(add.StructuredContent))
+
+	toolResult := func(request map[string]any) map[string]any {
+		messages := request["messages"].([]any)
+		content := messages[len(messages)-1].(map[string]any)["content"].([]any)
+		return content[0].(map[string]any)
+	}
+	requests := api.Requests()
+
+	// The error is sent to the model as an is_error tool result
+	divideResult := toolResult(requests[1])
+	assert.Equal("toolu_1", divideResult["tool_use_id"])
+	assert.Equal(true, divideResult["is_error"])
+
+	// Images are sent as image blocks after the text
+	logoContent := toolResult(requests[2])["content"].([]any)
+	assert.Len(logoContent, 2)
+	image := logoContent[1].(map[string]any)
+	assert.Equal("image", image["type"])
+	source := image["source"].(map[string]any)
+	assert.Equal("base64", source["type"])
+	assert.Equal("image/png", source["media_type"])
+	assert.NotEmpty(source["data"])
+
+	addResult := toolResult(requests[3])
+	assert.NotEqual(true, addResult["is_error"])
+}
+
+func TestEvalClient_RunEval_Thinking(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamThinkingToolUseResponse("The user wants a sum, the add tool does that.", "sig_1", "toolu_1", "add", `{"a":2,"b":3}`),
+		streamTextResponse("5"),
+		gradeResponse(5),
+		streamTextResponse("5"),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:         "test-key",
+		BaseURL:        api.URL,
+		Transport:      TransportStreamableHTTP,
+		URL:            mcpServer.URL,
+		Model:          "test-model",
+		ThinkingBudget: 2048,
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{Name: "thinking", Prompt: "What is 2 + 3?"})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+	assert.Equal("The user wants a sum, the add tool does that.", result.Trace.Steps[0].Thinking)
+	assert.Empty(result.Trace.Steps[1].Thinking)
+
+	requests := api.Requests()
+	thinking := requests[0]["thinking"].(map[string]any)
+	assert.Equal("enabled", thinking["type"])
+	assert.Equal(float64(2048), thinking["budget_tokens"])
+
+	// The signed thinking block is sent back ahead of the tool use it led to
+	messages := requests[1]["messages"].([]any)
+	assistant := messages[1].(map[string]any)["content"].([]any)
+	assert.Len(assistant, 2)
+	block := assistant[0].(map[string]any)
+	assert.Equal("thinking", block["type"])
+	assert.Equal("sig_1", block["signature"])
+	assert.Equal("The user wants a sum, the add tool does that.", block["thinking"])
+	assert.Equal("tool_use", assistant[1].(map[string]any)["type"])
+
+	// Grading runs without thinking
+	assert.Nil(requests[2]["thinking"])
+
+	// A per-eval budget of 0 turns thinking off
+	_, err = client.RunEval(context.Background(), Eval{Name: "no_thinking", Prompt: "What is 2 + 3?", Thinking: &ThinkingConfig{}})
+	assert.NoError(err)
+	assert.Nil(api.Requests()[3]["thinking"])
+}
@@ -0,0 +1,227 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Synthetic tools offered to the model when the MCP server exposes resources. Calls to
+// them are served from the resources API and traced like any other tool call.
+const (
+	ListResourcesTool = "list_resources"
+	ReadResourceTool  = "read_resource"
+)
+
+// PromptRef starts an eval from a prompt defined by the MCP server
+type PromptRef struct {
+	Name      string            `yaml:"name" json:"name" jsonschema:"Name of the prompt defined by the MCP server"`
+	Arguments map[string]string `yaml:"arguments,omitempty" json:"arguments,omitempty" jsonschema:"Arguments passed to the prompt"`
+}
+
+// PromptTrace records the server prompt an eval started from
+type PromptTrace struct {
+	Name        string            `json:"name"`                  // Prompt name
+	Arguments   map[string]string `json:"arguments,omitempty"`   // Arguments passed to the prompt
+	Description string            `json:"description,omitempty"` // Description returned by the server
+	Messages    []PromptMessage   `json:"messages,omitempty"`    // Messages returned by the server, flattened to text
+	StartTime   time.Time         `json:"start_time"`            // When the prompt was requested
+	Duration    time.Duration     `json:"duration"`              // How long the server took to return it
+	Error       string            `json:"error,omitempty"`       // Error message if the prompt could not be fetched
+}
+
+// PromptMessage is one message of a server prompt
+type PromptMessage struct {
+	Role Role   `json:"role"`
+	Text string `json:"text"`
+}
+
+// resourceToolDefinitions returns the synthetic resource tools when the server supports
+// resources. A tool is left out if the server already has a tool with the same name.
+func resourceToolDefinitions(session *mcp.ClientSession, serverTools []*mcp.Tool) []ToolDefinition {
+	init := session.InitializeResult()
+	if init == nil || init.Capabilities == nil || init.Capabilities.Resources == nil {
+		return nil
+	}
+
+	existing := make(map[string]bool, len(serverTools))
+	for _, tool := range serverTools {
+		existing[tool.Name] = true
+	}
+
+	candidates := []ToolDefinition{
+		{
+			Name:        ListResourcesTool,
+			Description: "Lists the resources and resource templates the MCP server exposes, with their URIs, names, descriptions and MIME types",
+			InputSchema: map[string]any{"type": "object", "properties": map[string]any{}},
+		},
+		{
+			Name:        ReadResourceTool,
+			Description: "Reads the contents of an MCP server resource by URI",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"uri": map[string]any{"type": "string", "description": "URI of the resource to read"},
+				},
+				"required": []any{"uri"},
+			},
+		},
+	}
+
+	var tools []ToolDefinition
+	for _, tool := range candidates {
+		if !existing[tool.Name] {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// callResourceTool serves a synthetic resource tool call, returning the result in the
+// same form as a server tool call. Failed reads are reported as tool errors.
+func callResourceTool(ctx context.Context, session *mcp.ClientSession, name string, input json.RawMessage) (*mcp.CallToolResult, error) {
+	switch name {
+	case ListResourcesTool:
+		return listResources(ctx, session)
+	case ReadResourceTool:
+		var args struct {
+			URI string `json:"uri"`
+		}
+		if len(input) > 0 {
+			if err := json.Unmarshal(input, &args); err != nil {
+				return toolError(fmt.Sprintf("invalid arguments: %v", err)), nil
+			}
+		}
+		if args.URI == "" {
+			return toolError("uri is required"), nil
+		}
+		return readResource(ctx, session, args.URI)
+	default:
+		return nil, fmt.Errorf("unknown resource tool '%s'", name)
+	}
+}
+
+func listResources(ctx context.Context, session *mcp.ClientSession) (*mcp.CallToolResult, error) {
+	type entry struct {
+		URI         string `json:"uri,omitempty"`
+		URITemplate string `json:"uri_template,omitempty"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		MIMEType    string `json:"mime_type,omitempty"`
+	}
+	listing := struct {
+		Resources []entry `json:"resources"`
+		Templates []entry `json:"resource_templates,omitempty"`
+	}{Resources: []entry{}}
+
+	for resource, err := range session.Resources(ctx, nil) {
+		if err != nil {
+			return toolError(fmt.Sprintf("failed to list resources: %v", err)), nil
+		}
+		listing.Resources = append(listing.Resources, entry{
+			URI:         resource.URI,
+			Name:        resource.Name,
+			Description: resource.Description,
+			MIMEType:    resource.MIMEType,
+		})
+	}
+
+	for template, err := range session.ResourceTemplates(ctx, nil) {
+		if err != nil {
+			// Templates are optional, servers without any may not implement the method
+			break
+		}
+		listing.Templates = append(listing.Templates, entry{
+			URITemplate: template.URITemplate,
+			Name:        template.Name,
+			Description: template.Description,
+			MIMEType:    template.MIMEType,
+		})
+	}
+
+	data, err := json.Marshal(listing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resources: %w", err)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(data)}}}, nil
+}
+
+func readResource(ctx context.Context, session *mcp.ClientSession, uri string) (*mcp.CallToolResult, error) {
+	resp, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	if err != nil {
+		return toolError(fmt.Sprintf("failed to read resource '%s': %v", uri, err)), nil
+	}
+
+	result := &mcp.CallToolResult{}
+	for _, contents := range resp.Contents {
+		if len(contents.Blob) > 0 && strings.HasPrefix(contents.MIMEType, "image/") {
+			result.Content = append(result.Content, &mcp.ImageContent{Data: contents.Blob, MIMEType: contents.MIMEType})
+			continue
+		}
+		result.Content = append(result.Content, &mcp.EmbeddedResource{Resource: contents})
+	}
+	return result, nil
+}
+
+// toolError builds a tool result reporting an error to the model
+func toolError(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: message}},
+		IsError: true,
+	}
+}
+
+// fetchPrompt gets a server prompt and converts it to the opening messages of the
+// conversation. Consecutive messages with the same role are merged.
+func fetchPrompt(ctx context.Context, session *mcp.ClientSession, ref *PromptRef) ([]Message, *PromptTrace, error) {
+	trace := &PromptTrace{
+		Name:      ref.Name,
+		Arguments: ref.Arguments,
+		StartTime: time.Now(),
+	}
+
+	resp, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: ref.Name, Arguments: ref.Arguments})
+	trace.Duration = time.Since(trace.StartTime)
+	if err != nil {
+		trace.Error = err.Error()
+		return nil, trace, fmt.Errorf("failed to get prompt '%s': %w", ref.Name, err)
+	}
+	trace.Description = resp.Description
+
+	var messages []Message
+	for _, message := range resp.Messages {
+		text, _ := flattenContent([]mcp.Content{message.Content})
+		role := RoleUser
+		if message.Role == "assistant" {
+			role = RoleAssistant
+		}
+		trace.Messages = append(trace.Messages, PromptMessage{Role: role, Text: text})
+
+		if n := len(messages); n > 0 && messages[n-1].Role == role {
+			messages[n-1].Content = append(messages[n-1].Content, TextBlock(text))
+			continue
+		}
+		messages = append(messages, Message{Role: role, Content: []ContentBlock{TextBlock(text)}})
+	}
+
+	if len(messages) == 0 {
+		return nil, trace, fmt.Errorf("prompt '%s' returned no messages", ref.Name)
+	}
+	return messages, trace, nil
+}
+
+// Text joins the prompt messages into the text shown to the grader
+func (t *PromptTrace) Text() string {
+	if len(t.Messages) == 1 {
+		return t.Messages[0].Text
+	}
+	parts := make([]string, 0, len(t.Messages))
+	for _, message := range t.Messages {
+		parts = append(parts, fmt.Sprintf("[%s] %s", message.Role, message.Text))
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,156 @@
+package evaluations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalClient_RunEval_ResourcesAndPromptRef(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamToolUseResponse("toolu_1", ListResourcesTool, `{}`),
+		streamToolUseResponse("toolu_2", ReadResourceTool, `{"uri":"docs://runbooks/api-gateway"}`),
+		streamToolUseResponse("toolu_3", ReadResourceTool, `{"uri":"docs://missing"}`),
+		streamTextResponse("Check the user-service connection pool"),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:      "troubleshoot",
+		PromptRef: &PromptRef{Name: "troubleshoot", Arguments: map[string]string{"service": "api-gateway"}},
+		Prompt:    "Keep it short.",
+	})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+
+	// The prompt fetch is recorded in the trace
+	prompt := result.Trace.Prompt
+	assert.NotNil(prompt)
+	assert.Equal("troubleshoot", prompt.Name)
+	assert.Equal("Troubleshoot a failing service", prompt.Description)
+	assert.Len(prompt.Messages, 1)
+	assert.Equal(RoleUser, prompt.Messages[0].Role)
+	assert.Contains(prompt.Messages[0].Text, "The api-gateway service is failing")
+
+	// Resource reads are traced as calls to the synthetic tools
+	steps := result.Trace.Steps
+	assert.Len(steps, 4)
+	list := steps[0].ToolCalls[0]
+	assert.True(list.Success)
+	assert.Contains(string(list.Output), "docs://runbooks/api-gateway")
+	read := steps[1].ToolCalls[0]
+	assert.True(read.Success)
+	assert.Contains(string(read.Output), "connection pool")
+	missing := steps[2].ToolCalls[0]
+	assert.False(missing.Success)
+	assert.Contains(missing.Error, "failed to read resource 'docs://missing'")
+
+	requests := api.Requests()
+
+	// The conversation opens with the server prompt followed by the eval's own prompt
+	first := requests[0]
+	content := first["messages"].([]any)[0].(map[string]any)["content"].([]any)
+	assert.Len(content, 2)
+	assert.Contains(content[0].(map[string]any)["text"], "The api-gateway service is failing")
+	assert.Equal("Keep it short.", content[1].(map[string]any)["text"])
+
+	var toolNames []string
+	for _, tool := range first["tools"].([]any) {
+		toolNames = append(toolNames, tool.(map[string]any)["name"].(string))
+	}
+	assert.Contains(toolNames, ListResourcesTool)
+	assert.Contains(toolNames, ReadResourceTool)
+
+	// The grader sees the rendered prompt
+	assert.Contains(result.Trace.Grading.UserPrompt, "The api-gateway service is failing")
+	assert.Contains(result.Trace.Grading.UserPrompt, "Keep it short.")
+}
+
+func TestEvalClient_RunEval_PromptRefNotFound(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	_, err := client.RunEval(context.Background(), Eval{Name: "missing", PromptRef: &PromptRef{Name: "missing"}})
+	assert.ErrorContains(err, "failed to get prompt 'missing'")
+	assert.Empty(api.Requests())
+}
+
+func TestLoadConfig_PromptRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		eval    string
+		wantErr string
+	}{
+		{
+			name: "prompt_ref only",
+			eval: `
+  - name: test
+    prompt_ref:
+      name: troubleshoot
+      arguments:
+        service: api-gateway
+`,
+		},
+		{
+			name: "neither prompt nor prompt_ref",
+			eval: `
+  - name: test
+    description: no prompt
+`,
+			wantErr: "needs a prompt or prompt_ref",
+		},
+		{
+			name: "prompt_ref without name",
+			eval: `
+  - name: test
+    prompt_ref:
+      arguments:
+        service: api-gateway
+`,
+			wantErr: "name is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			content := "model: test-model\nmcp_server:\n  command: echo\nevals:" + tt.eval
+			assert.NoError(os.WriteFile(path, []byte(content), 0o600))
+
+			config, err := LoadConfig(path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal("troubleshoot", config.Evals[0].PromptRef.Name)
+			assert.Equal("api-gateway", config.Evals[0].PromptRef.Arguments["service"])
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package evaluations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// SessionMode selects how MCP sessions are shared between eval runs
+type SessionMode string
+
+const (
+	SessionPerEval   SessionMode = "per_eval"   // Start a fresh session for every eval run (default)
+	SessionShared    SessionMode = "shared"     // Run every eval against one session
+	SessionPerWorker SessionMode = "per_worker" // Give each concurrent worker its own session, reused for the evals it runs
+)
+
+var sessionModes = []SessionMode{SessionPerEval, SessionShared, SessionPerWorker}
+
+// healthCheckTimeout bounds the ping sent before a pooled session is reused
+const healthCheckTimeout = 5 * time.Second
+
+// mcpSession is a connected MCP session with the tools it offered when it started
+type mcpSession struct {
+	session *mcp.ClientSession
+	tools   *mcp.ListToolsResult
+	router  *requestRouter
+	refs    int // runs using the session in shared mode, guarded by sessionPool.mu
+}
+
+// sessionPool hands out MCP sessions according to the configured SessionMode. Pooled
+// sessions are pinged before they are reused and replaced when the server has gone away.
+// The lock is never held while talking to the server, so slow pings and connects don't
+// hold up other workers.
+type sessionPool struct {
+	ec   *EvalClient
+	mode SessionMode
+
+	mu         sync.Mutex
+	shared     *mcpSession   // the session handed to new runs in shared mode
+	connecting chan struct{} // closed once the shared session being started is ready
+	idle       []*mcpSession // sessions not in use by a run in per_worker mode
+	closed     bool
+}
+
+func newSessionPool(ec *EvalClient) *sessionPool {
+	mode := ec.config.SessionMode
+	if mode == "" {
+		mode = SessionPerEval
+	}
+	return &sessionPool{ec: ec, mode: mode}
+}
+
+// acquire returns a session for one eval run with handler attached to its request router
+func (p *sessionPool) acquire(ctx context.Context, handler *serverRequestHandler) (*mcpSession, error) {
+	var (
+		s   *mcpSession
+		err error
+	)
+	switch p.mode {
+	case SessionPerEval:
+		// Attach before connecting so the requests made during initialization are recorded
+		router := newRequestRouter(p.ec)
+		router.attach(handler)
+		return p.connect(ctx, router)
+	case SessionShared:
+		s, err = p.reuseShared(ctx)
+	default:
+		s, err = p.reuseIdle(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.router.attach(handler)
+	return s, nil
+}
+
+// reuseShared returns the shared session with a reference held for the caller, starting
+// a new one if there is none or it failed its health check. A session that fails its
+// health check is replaced for new runs but only closed once the runs using it finish.
+func (p *sessionPool) reuseShared(ctx context.Context) (*mcpSession, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, fmt.Errorf("eval client is closed")
+		}
+
+		if s := p.shared; s != nil {
+			s.refs++
+			p.mu.Unlock()
+
+			if p.healthy(ctx, s) {
+				return s, nil
+			}
+			p.mu.Lock()
+			if p.shared == s {
+				p.shared = nil
+			}
+			p.mu.Unlock()
+			p.releaseShared(s)
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Wait for another run already starting the shared session
+		if wait := p.connecting; wait != nil {
+			p.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		done := make(chan struct{})
+		p.connecting = done
+		p.mu.Unlock()
+
+		// The session outlives the eval that started it, so it must not inherit its deadline
+		s, err := p.connect(context.WithoutCancel(ctx), newRequestRouter(p.ec))
+
+		p.mu.Lock()
+		p.connecting = nil
+		close(done)
+		if err != nil {
+			p.mu.Unlock()
+			return nil, err
+		}
+		if p.closed {
+			p.mu.Unlock()
+			_ = s.session.Close()
+			return nil, fmt.Errorf("eval client is closed")
+		}
+		s.refs = 1
+		p.shared = s
+		p.mu.Unlock()
+		return s, nil
+	}
+}
+
+// reuseIdle returns a healthy idle session, starting a new one if none is available
+func (p *sessionPool) reuseIdle(ctx context.Context) (*mcpSession, error) {
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, fmt.Errorf("eval client is closed")
+		}
+		if len(p.idle) == 0 {
+			p.mu.Unlock()
+			break
+		}
+		s := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		p.mu.Unlock()
+
+		if p.healthy(ctx, s) {
+			return s, nil
+		}
+		_ = s.session.Close()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	// The session outlives the eval that started it, so it must not inherit its deadline
+	return p.connect(context.WithoutCancel(ctx), newRequestRouter(p.ec))
+}
+
+// healthy pings the server to check a pooled session can still be used
+func (p *sessionPool) healthy(ctx context.Context, s *mcpSession) bool {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if err := s.session.Ping(ctx, nil); err != nil {
+		log.Warn().
+			Str("session_mode", string(p.mode)).
+			Err(err).
+			Msg("MCP session failed health check, starting a new one")
+		return false
+	}
+	return true
+}
+
+func (p *sessionPool) connect(ctx context.Context, router *requestRouter) (*mcpSession, error) {
+	session, tools, err := p.ec.loadMCPSession(ctx, router)
+	if err != nil {
+		return nil, err
+	}
+	return &mcpSession{session: session, tools: tools, router: router}, nil
+}
+
+// release returns a session after an eval run. Pooled sessions stay open for the next
+// run, which checks their health before using them.
+func (p *sessionPool) release(s *mcpSession, handler *serverRequestHandler) {
+	s.router.detach(handler)
+
+	switch p.mode {
+	case SessionPerEval:
+		_ = s.session.Close()
+	case SessionShared:
+		p.releaseShared(s)
+	case SessionPerWorker:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.closed {
+			_ = s.session.Close()
+			return
+		}
+		p.idle = append(p.idle, s)
+	}
+}
+
+// releaseShared drops a reference to a shared session, closing it once it has been
+// replaced or the pool closed and no run is using it any more
+func (p *sessionPool) releaseShared(s *mcpSession) {
+	p.mu.Lock()
+	s.refs--
+	unused := s.refs == 0 && p.shared != s
+	p.mu.Unlock()
+
+	if unused {
+		_ = s.session.Close()
+	}
+}
+
+// Close closes the pooled sessions, it is meant to be called once no evals are running.
+// A shared session still in use is closed when its last run releases it.
+func (p *sessionPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+
+	var errs []error
+	if p.shared != nil {
+		if p.shared.refs == 0 {
+			errs = append(errs, p.shared.session.Close())
+		}
+		p.shared = nil
+	}
+	for _, s := range p.idle {
+		errs = append(errs, s.session.Close())
+	}
+	p.idle = nil
+
+	return errors.Join(errs...)
+}
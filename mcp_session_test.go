@@ -0,0 +1,242 @@
+package evaluations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfeidau/mcp-evals/testdata/mcp-test-server/testserver"
+)
+
+// newCountingMCPServer is like newTestMCPServer but counts the sessions clients start
+func newCountingMCPServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var sessions atomic.Int32
+	srv := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		sessions.Add(1)
+		return testserver.New()
+	}, nil))
+	t.Cleanup(srv.Close)
+
+	return srv, &sessions
+}
+
+func TestEvalClient_RunEvals_SessionModes(t *testing.T) {
+	tests := []struct {
+		mode         SessionMode
+		wantSessions int32
+	}{
+		{mode: SessionPerEval, wantSessions: 3},
+		{mode: SessionShared, wantSessions: 1},
+		{mode: SessionPerWorker, wantSessions: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			assert := require.New(t)
+
+			mcpServer, sessions := newCountingMCPServer(t)
+			api := newFakeModelAPI(t,
+				streamTextResponse("one"), gradeResponse(5),
+				streamTextResponse("two"), gradeResponse(5),
+				streamTextResponse("three"), gradeResponse(5),
+			)
+
+			client := NewEvalClient(EvalClientConfig{
+				APIKey:      "test-key",
+				BaseURL:     api.URL,
+				Transport:   TransportStreamableHTTP,
+				URL:         mcpServer.URL,
+				Model:       "test-model",
+				SessionMode: tt.mode,
+			})
+			t.Cleanup(func() { _ = client.Close() })
+
+			results, err := client.RunEvals(context.Background(), []Eval{
+				{Name: "one", Prompt: "First"},
+				{Name: "two", Prompt: "Second"},
+				{Name: "three", Prompt: "Third"},
+			})
+			assert.NoError(err)
+			for _, result := range results {
+				assert.NoError(result.Error)
+			}
+			assert.Equal(tt.wantSessions, sessions.Load())
+		})
+	}
+}
+
+func TestEvalClient_RunEval_SharedSessionRespawn(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer, sessions := newCountingMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("ready"), gradeResponse(5),
+		streamToolUseResponse("toolu_1", "delete_service", `{"service":"api-gateway"}`),
+		streamTextResponse("done"),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:      "test-key",
+		BaseURL:     api.URL,
+		Transport:   TransportStreamableHTTP,
+		URL:         mcpServer.URL,
+		Model:       "test-model",
+		SessionMode: SessionShared,
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	_, err := client.RunEval(context.Background(), Eval{Name: "first", Prompt: "Are you ready?"})
+	assert.NoError(err)
+
+	// Losing the connection fails the health check and a new session is started
+	assert.NoError(client.sessions.shared.session.Close())
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:        "second",
+		Prompt:      "Delete the api-gateway",
+		Elicitation: []ElicitationResponse{{Content: map[string]any{"reason": "retired"}}},
+	})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+	assert.Equal(int32(2), sessions.Load())
+
+	// Requests from the pooled session are answered with the running eval's script
+	assert.Contains(string(result.Trace.Steps[0].ToolCalls[0].Output), "Deleted api-gateway because retired")
+	assert.Len(result.Trace.ServerRequests, 1)
+	assert.Equal("elicitation/create", result.Trace.ServerRequests[0].Method)
+}
+
+func TestSessionPool_SharedConcurrentAcquire(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer, sessions := newCountingMCPServer(t)
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:      "test-key",
+		Transport:   TransportStreamableHTTP,
+		URL:         mcpServer.URL,
+		Model:       "test-model",
+		SessionMode: SessionShared,
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	var wg sync.WaitGroup
+	acquired := make([]*mcpSession, 5)
+	handlers := make([]*serverRequestHandler, len(acquired))
+	for i := range acquired {
+		handlers[i] = newServerRequestHandler(Eval{})
+		wg.Go(func() {
+			s, err := client.sessions.acquire(context.Background(), handlers[i])
+			assert.NoError(err)
+			acquired[i] = s
+		})
+	}
+	wg.Wait()
+	t.Cleanup(func() {
+		for i, s := range acquired {
+			client.sessions.release(s, handlers[i])
+		}
+	})
+
+	// Runs starting together wait for one session rather than each starting their own
+	assert.Equal(int32(1), sessions.Load())
+	for _, s := range acquired {
+		assert.Same(acquired[0], s)
+	}
+	assert.Equal(5, acquired[0].refs)
+}
+
+func TestSessionPool_SharedSessionClosedByLastRun(t *testing.T) {
+	assert := require.New(t)
+
+	// The server answers pings from broken sessions with an error and records the
+	// sessions clients close
+	var (
+		mu      sync.Mutex
+		broken  = make(map[string]bool)
+		deleted = make(map[string]bool)
+	)
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return testserver.New()
+	}, nil)
+	mcpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("Mcp-Session-Id")
+		mu.Lock()
+		isBroken := broken[id]
+		if r.Method == http.MethodDelete {
+			deleted[id] = true
+		}
+		mu.Unlock()
+
+		if isBroken && r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			var req struct {
+				ID     json.RawMessage `json:"id"`
+				Method string          `json:"method"`
+			}
+			if json.Unmarshal(body, &req) == nil && req.Method == "ping" {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32603,"message":"server unavailable"}}`, req.ID)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(mcpServer.Close)
+
+	isDeleted := func(s *mcpSession) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return deleted[s.session.ID()]
+	}
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:      "test-key",
+		Transport:   TransportStreamableHTTP,
+		URL:         mcpServer.URL,
+		Model:       "test-model",
+		SessionMode: SessionShared,
+	})
+	pool := client.sessions
+	ctx := context.Background()
+
+	firstHandler := newServerRequestHandler(Eval{Name: "first"})
+	first, err := pool.acquire(ctx, firstHandler)
+	assert.NoError(err)
+	secondHandler := newServerRequestHandler(Eval{Name: "second"})
+	second, err := pool.acquire(ctx, secondHandler)
+	assert.NoError(err)
+	assert.Same(first, second)
+
+	// A failed health check replaces the session for new runs
+	mu.Lock()
+	broken[first.session.ID()] = true
+	mu.Unlock()
+	thirdHandler := newServerRequestHandler(Eval{Name: "third"})
+	third, err := pool.acquire(ctx, thirdHandler)
+	assert.NoError(err)
+	assert.NotSame(first, third)
+
+	// The old session stays open until the runs still using it release it
+	pool.release(first, firstHandler)
+	assert.False(isDeleted(first))
+	pool.release(second, secondHandler)
+	assert.True(isDeleted(first))
+
+	pool.release(third, thirdHandler)
+	assert.False(isDeleted(third))
+	assert.NoError(client.Close())
+	assert.True(isDeleted(third))
+}
@@ -0,0 +1,105 @@
+package evaluations
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newMCPTransport builds the MCP transport selected by the client config
+func (ec *EvalClient) newMCPTransport() (mcp.Transport, error) {
+	switch ec.config.Transport {
+	case "", TransportStdio:
+		return ec.newCommandTransport()
+	case TransportSSE:
+		if ec.config.URL == "" {
+			return nil, fmt.Errorf("url is required for %s transport", ec.config.Transport)
+		}
+		return &mcp.SSEClientTransport{
+			Endpoint:   ec.config.URL,
+			HTTPClient: ec.newMCPHTTPClient(),
+		}, nil
+	case TransportStreamableHTTP:
+		if ec.config.URL == "" {
+			return nil, fmt.Errorf("url is required for %s transport", ec.config.Transport)
+		}
+		return &mcp.StreamableClientTransport{
+			Endpoint:   ec.config.URL,
+			HTTPClient: ec.newMCPHTTPClient(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported MCP transport: %s", ec.config.Transport)
+	}
+}
+
+// newCommandTransport launches the MCP server as a subprocess speaking MCP over stdio
+func (ec *EvalClient) newCommandTransport() (mcp.Transport, error) {
+	if ec.config.Command == "" {
+		return nil, fmt.Errorf("command is required for %s transport", TransportStdio)
+	}
+
+	// #nosec G204 - Command and args are provided by the library caller as part of EvalClientConfig
+	cmd := exec.Command(ec.config.Command, ec.config.Args...)
+
+	// Handle stderr based on whether a callback is provided
+	if ec.config.StderrCallback != nil {
+		stderrPipe, err := cmd.StderrPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+		}
+
+		// Spawn goroutine to read stderr line-by-line and invoke callback
+		go func() {
+			scanner := bufio.NewScanner(stderrPipe)
+			for scanner.Scan() {
+				ec.config.StderrCallback(scanner.Text())
+			}
+			// Ignore scanner errors as they typically occur when the process exits
+		}()
+	} else {
+		cmd.Stderr = os.Stderr // forward subprocess stderr for visibility (backward compatible)
+	}
+
+	// If custom env vars are provided, append them to the parent environment
+	if len(ec.config.Env) > 0 {
+		cmd.Env = append(os.Environ(), ec.config.Env...)
+	}
+
+	return &mcp.CommandTransport{
+		Command: cmd,
+	}, nil
+}
+
+// newMCPHTTPClient returns the HTTP client used by the HTTP based transports,
+// adding any configured headers to every request
+func (ec *EvalClient) newMCPHTTPClient() *http.Client {
+	if len(ec.config.Headers) == 0 {
+		return http.DefaultClient
+	}
+
+	return &http.Client{
+		Transport: &headerRoundTripper{
+			headers: ec.config.Headers,
+			next:    http.DefaultTransport,
+		},
+	}
+}
+
+// headerRoundTripper sets a fixed set of headers on each outgoing request
+type headerRoundTripper struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for key, value := range h.headers {
+		req.Header.Set(key, value)
+	}
+	return h.next.RoundTrip(req)
+}
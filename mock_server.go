@@ -0,0 +1,243 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultUnmatchedError is returned by the mock server for calls matching no recorded call
+const DefaultUnmatchedError = "no recorded call matches these arguments"
+
+// DefaultMinSimilarity is the share of arguments a fuzzy match must have in common with a call
+const DefaultMinSimilarity = 0.5
+
+// MockServerOptions configures a mock MCP server built from recorded traces
+type MockServerOptions struct {
+	MinSimilarity  float64 // Optional: share of arguments a fuzzy match must have in common with the call, above 1 allows only exact matches. Default: DefaultMinSimilarity
+	UnmatchedError string  // Optional: tool error returned for calls that match no recorded call. Default: DefaultUnmatchedError
+}
+
+// NewMockServer returns an MCP server offering the tools recorded in the traces of
+// results, including their trials. Each call is answered with the output of a recorded
+// call to the same tool, preferring one with exactly the same arguments and otherwise
+// the most similar. Traces written before tool lists were recorded offer the tools
+// they called, without descriptions or input schemas.
+func NewMockServer(results []EvalRunResult, opts MockServerOptions) *mcp.Server {
+	if opts.MinSimilarity <= 0 {
+		opts.MinSimilarity = DefaultMinSimilarity
+	}
+	if opts.UnmatchedError == "" {
+		opts.UnmatchedError = DefaultUnmatchedError
+	}
+
+	mock := &mockServer{opts: opts, calls: make(map[string][]mockCall)}
+	tools := mock.load(results)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "mcp-evals-mock", Version: "1.0.0"}, nil)
+	for _, tool := range tools {
+		server.AddTool(tool, mock.callTool)
+	}
+	return server
+}
+
+// mockServer answers tool calls from recorded ones
+type mockServer struct {
+	opts  MockServerOptions
+	calls map[string][]mockCall // recorded calls by tool name, in trace order
+}
+
+type mockCall struct {
+	args any // decoded call arguments
+	call ToolCall
+}
+
+// load collects the recorded tools and calls of results, returning the tools in the
+// order they were first seen
+func (m *mockServer) load(results []EvalRunResult) []*mcp.Tool {
+	var traces []*EvalTrace
+	for _, result := range results {
+		if result.Trace != nil {
+			traces = append(traces, result.Trace)
+		}
+		for _, trial := range result.Trials {
+			if trial.Trace != nil {
+				traces = append(traces, trial.Trace)
+			}
+		}
+	}
+
+	var tools []*mcp.Tool
+	known := make(map[string]bool)
+	addTool := func(tool *mcp.Tool) {
+		if known[tool.Name] {
+			return
+		}
+		known[tool.Name] = true
+		tools = append(tools, mockTool(tool))
+	}
+
+	for _, trace := range traces {
+		for _, tool := range trace.Tools {
+			addTool(tool)
+		}
+	}
+
+	for _, trace := range traces {
+		for _, step := range trace.Steps {
+			for _, call := range step.ToolCalls {
+				// Resource tools are served by the client from the resources API
+				if len(call.Output) == 0 || call.ToolName == ListResourcesTool || call.ToolName == ReadResourceTool {
+					continue
+				}
+				var args any
+				if len(call.Input) > 0 {
+					_ = json.Unmarshal(call.Input, &args)
+				}
+				m.calls[call.ToolName] = append(m.calls[call.ToolName], mockCall{args: args, call: call})
+				addTool(&mcp.Tool{Name: call.ToolName})
+			}
+		}
+	}
+
+	return tools
+}
+
+// mockTool copies a recorded tool, giving it the object input schema the server requires
+func mockTool(recorded *mcp.Tool) *mcp.Tool {
+	tool := *recorded
+	if schema, ok := tool.InputSchema.(map[string]any); !ok || schema["type"] != "object" {
+		tool.InputSchema = map[string]any{"type": "object"}
+	}
+	if schema, ok := tool.OutputSchema.(map[string]any); ok && schema["type"] != "object" {
+		tool.OutputSchema = nil
+	}
+	return &tool
+}
+
+func (m *mockServer) callTool(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var args any
+	if len(req.Params.Arguments) > 0 {
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return nil, err
+		}
+	}
+
+	call, ok := m.match(req.Params.Name, args)
+	if !ok {
+		log.Warn().
+			Str("tool", req.Params.Name).
+			Str("arguments", string(req.Params.Arguments)).
+			Msg("No recorded call matches, returning the unmatched error")
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{&mcp.TextContent{Text: m.opts.UnmatchedError}},
+		}, nil
+	}
+	return mockResult(call)
+}
+
+// match finds the recorded call to answer a call to tool with args: the first with
+// the same arguments, otherwise the most similar that reaches MinSimilarity
+func (m *mockServer) match(tool string, args any) (ToolCall, bool) {
+	calls := m.calls[tool]
+	for _, recorded := range calls {
+		if reflect.DeepEqual(recorded.args, args) {
+			return recorded.call, true
+		}
+	}
+
+	best, bestScore := -1, 0.0
+	for i, recorded := range calls {
+		if score := argumentSimilarity(recorded.args, args); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 || bestScore < m.opts.MinSimilarity {
+		return ToolCall{}, false
+	}
+	return calls[best].call, true
+}
+
+// mockResult rebuilds the tool result recorded by executeAndTraceToolCall. Calls that
+// failed with a protocol error fail the same way.
+func mockResult(call ToolCall) (*mcp.CallToolResult, error) {
+	var output struct {
+		Result *string `json:"result"`
+		Error  *string `json:"error"`
+	}
+	_ = json.Unmarshal(call.Output, &output)
+	if output.Error != nil {
+		return nil, errors.New(*output.Error)
+	}
+
+	result := &mcp.CallToolResult{IsError: !call.Success}
+	if output.Result != nil {
+		result.Content = []mcp.Content{&mcp.TextContent{Text: *output.Result}}
+	}
+	if len(call.StructuredContent) > 0 {
+		var structured any
+		if err := json.Unmarshal(call.StructuredContent, &structured); err == nil {
+			result.StructuredContent = structured
+		}
+	}
+	return result, nil
+}
+
+// argumentSimilarity scores how alike two sets of call arguments are from 0 to 1, as
+// the share of their combined fields with matching values
+func argumentSimilarity(a, b any) float64 {
+	aFields, aOK := a.(map[string]any)
+	bFields, bOK := b.(map[string]any)
+	if !aOK || !bOK {
+		return valueSimilarity(a, b)
+	}
+
+	fields := unionStrings(sortedKeys(aFields), sortedKeys(bFields))
+	if len(fields) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, field := range fields {
+		aValue, inA := aFields[field]
+		bValue, inB := bFields[field]
+		if inA && inB {
+			total += valueSimilarity(aValue, bValue)
+		}
+	}
+	return total / float64(len(fields))
+}
+
+// valueSimilarity scores two argument values: 1 when equal ignoring case and
+// surrounding space, 0.5 when one string contains the other, and recursively for objects
+func valueSimilarity(a, b any) float64 {
+	if reflect.DeepEqual(a, b) {
+		return 1
+	}
+
+	aString, aOK := a.(string)
+	bString, bOK := b.(string)
+	if aOK && bOK {
+		aString = strings.ToLower(strings.TrimSpace(aString))
+		bString = strings.ToLower(strings.TrimSpace(bString))
+		switch {
+		case aString == bString:
+			return 1
+		case aString != "" && bString != "" && (strings.Contains(aString, bString) || strings.Contains(bString, aString)):
+			return 0.5
+		}
+		return 0
+	}
+
+	_, aObject := a.(map[string]any)
+	_, bObject := b.(map[string]any)
+	if aObject && bObject {
+		return argumentSimilarity(a, b)
+	}
+	return 0
+}
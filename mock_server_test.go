@@ -0,0 +1,178 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+// connectMockServer connects a client to a mock server built from results
+func connectMockServer(t *testing.T, results []EvalRunResult, opts MockServerOptions) *mcp.ClientSession {
+	t.Helper()
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := NewMockServer(results, opts).Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+
+	return session
+}
+
+func recordedCall(name, input, output string, success bool) ToolCall {
+	return ToolCall{ToolName: name, Input: json.RawMessage(input), Output: json.RawMessage(output), Success: success}
+}
+
+func TestNewMockServer(t *testing.T) {
+	results := []EvalRunResult{
+		{Trace: &EvalTrace{
+			Tools: []*mcp.Tool{{
+				Name:        "get_forecast",
+				Description: "Gets the weather forecast for a city",
+				InputSchema: map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+			}},
+			Steps: []AgenticStep{{ToolCalls: []ToolCall{
+				recordedCall("get_forecast", `{"city":"Paris","days":3}`, `{"result":"Sunny in Paris"}`, true),
+				recordedCall("get_forecast", `{"city":"Atlantis"}`, `{"result":"unknown city"}`, false),
+				recordedCall(ReadResourceTool, `{"uri":"file:///notes.txt"}`, `{"result":"notes"}`, true),
+			}}},
+		}},
+		// An older trace without a tool list, run with trials
+		{Trials: []EvalRunResult{{Trace: &EvalTrace{
+			Steps: []AgenticStep{{ToolCalls: []ToolCall{
+				recordedCall("delete_service", `{"service":"api"}`, `{"error":"calling \"tools/call\": permission denied"}`, false),
+			}}},
+		}}}},
+	}
+
+	session := connectMockServer(t, results, MockServerOptions{UnmatchedError: "not recorded"})
+
+	t.Run("tool list", func(t *testing.T) {
+		assert := require.New(t)
+
+		tools, err := session.ListTools(context.Background(), nil)
+		assert.NoError(err)
+		descriptions := make(map[string]string)
+		for _, tool := range tools.Tools {
+			descriptions[tool.Name] = tool.Description
+		}
+		assert.Equal(map[string]string{
+			"get_forecast":   "Gets the weather forecast for a city",
+			"delete_service": "",
+		}, descriptions)
+	})
+
+	tests := []struct {
+		name      string
+		tool      string
+		args      map[string]any
+		wantText  string
+		wantError bool
+		wantErr   string
+	}{
+		{name: "exact match", tool: "get_forecast", args: map[string]any{"city": "Paris", "days": 3}, wantText: "Sunny in Paris"},
+		{name: "fuzzy match", tool: "get_forecast", args: map[string]any{"city": "paris ", "days": 3}, wantText: "Sunny in Paris"},
+		{name: "recorded tool error", tool: "get_forecast", args: map[string]any{"city": "Atlantis"}, wantText: "unknown city", wantError: true},
+		{name: "unmatched", tool: "get_forecast", args: map[string]any{"city": "Tokyo"}, wantText: "not recorded", wantError: true},
+		{name: "recorded protocol error", tool: "delete_service", args: map[string]any{"service": "api"}, wantErr: "permission denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: tt.tool, Arguments: tt.args})
+			if tt.wantErr != "" {
+				assert.ErrorContains(err, tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tt.wantError, result.IsError)
+			text, _ := flattenContent(result.Content)
+			assert.Equal(tt.wantText, text)
+		})
+	}
+}
+
+func TestArgumentSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b any
+		want float64
+	}{
+		{name: "equal", a: map[string]any{"city": "Paris"}, b: map[string]any{"city": "Paris"}, want: 1},
+		{name: "case and space", a: map[string]any{"city": "Paris"}, b: map[string]any{"city": " PARIS"}, want: 1},
+		{name: "substring", a: map[string]any{"query": "open incidents"}, b: map[string]any{"query": "incidents"}, want: 0.5},
+		{name: "extra field", a: map[string]any{"city": "Paris"}, b: map[string]any{"city": "Paris", "days": 3.0}, want: 0.5},
+		{name: "nested", a: map[string]any{"filter": map[string]any{"a": 1.0, "b": 2.0}}, b: map[string]any{"filter": map[string]any{"a": 1.0, "b": 3.0}}, want: 0.5},
+		{name: "object and string", a: map[string]any{"filter": map[string]any{"a": 1.0}}, b: map[string]any{"filter": "a"}, want: 0},
+		{name: "no arguments", a: nil, b: map[string]any{"city": "Paris"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.InDelta(t, tt.want, argumentSimilarity(tt.a, tt.b), 1e-9)
+		})
+	}
+}
+
+func TestEvalClient_RunEval_AgainstMockServer(t *testing.T) {
+	assert := require.New(t)
+
+	eval := Eval{Name: "echo", Prompt: "Echo hello"}
+	responses := func() []fakeAPIResponse {
+		return []fakeAPIResponse{
+			streamToolUseResponse("toolu_1", "echo", `{"message":"hello"}`),
+			streamTextResponse("Echoed hello."),
+			gradeResponse(5),
+		}
+	}
+
+	// Record a trace against the real server
+	recorder := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   newFakeModelAPI(t, responses()...).URL,
+		Transport: TransportStreamableHTTP,
+		URL:       newTestMCPServer(t).URL,
+		Model:     "test-model",
+	})
+	recorded, err := recorder.RunEval(context.Background(), eval)
+	assert.NoError(err)
+	assert.NoError(recorded.Error)
+	assert.NotEmpty(recorded.Trace.Tools)
+
+	// The trace survives a round trip through a trace file
+	data, err := json.Marshal(recorded)
+	assert.NoError(err)
+	var loaded EvalRunResult
+	assert.NoError(json.Unmarshal(data, &loaded))
+
+	mock := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return NewMockServer([]EvalRunResult{loaded}, MockServerOptions{})
+	}, nil))
+	t.Cleanup(mock.Close)
+
+	replayer := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   newFakeModelAPI(t, responses()...).URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mock.URL,
+		Model:     "test-model",
+	})
+	replayed, err := replayer.RunEval(context.Background(), eval)
+	assert.NoError(err)
+	assert.NoError(replayed.Error)
+
+	assert.Len(replayed.Trace.Tools, len(recorded.Trace.Tools))
+	assert.Equal(recorded.Trace.Steps[0].ToolCalls[0].Output, replayed.Trace.Steps[0].ToolCalls[0].Output)
+	assert.True(replayed.Trace.Steps[0].ToolCalls[0].Success)
+}
@@ -0,0 +1,221 @@
+package evaluations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ModelPrice is the price of a model's tokens in USD per million tokens. Unset cache
+// prices are derived from Input using Anthropic's multipliers: 1.25x for 5 minute cache
+// writes, 2x for 1 hour cache writes and 0.1x for cache reads.
+type ModelPrice struct {
+	Input        float64 `yaml:"input" json:"input" jsonschema:"USD per million uncached input tokens"`
+	Output       float64 `yaml:"output" json:"output" jsonschema:"USD per million output tokens"`
+	CacheWrite5m float64 `yaml:"cache_write_5m,omitempty" json:"cache_write_5m,omitempty" jsonschema:"USD per million tokens written to the 5 minute cache (default 1.25x input)"`
+	CacheWrite1h float64 `yaml:"cache_write_1h,omitempty" json:"cache_write_1h,omitempty" jsonschema:"USD per million tokens written to the 1 hour cache (default 2x input)"`
+	CacheRead    float64 `yaml:"cache_read,omitempty" json:"cache_read,omitempty" jsonschema:"USD per million tokens read from the cache (default 0.1x input)"`
+}
+
+// Validate checks that no price is negative
+func (p ModelPrice) Validate() error {
+	prices := []struct {
+		name  string
+		value float64
+	}{
+		{"input", p.Input},
+		{"output", p.Output},
+		{"cache_write_5m", p.CacheWrite5m},
+		{"cache_write_1h", p.CacheWrite1h},
+		{"cache_read", p.CacheRead},
+	}
+	for _, price := range prices {
+		if price.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %g", price.name, price.value)
+		}
+	}
+	return nil
+}
+
+// cost returns the USD cost of usage, pricing cache writes for the given cache TTL
+func (p ModelPrice) cost(usage Usage, cacheTTL string) float64 {
+	cacheWrite := p.CacheWrite5m
+	if cacheTTL == "1h" {
+		cacheWrite = p.CacheWrite1h
+		if cacheWrite == 0 {
+			cacheWrite = p.Input * 2
+		}
+	} else if cacheWrite == 0 {
+		cacheWrite = p.Input * 1.25
+	}
+
+	total := float64(usage.InputTokens)*p.Input +
+		float64(usage.OutputTokens)*p.Output +
+		float64(usage.CacheCreationInputTokens)*cacheWrite +
+		float64(usage.CacheReadInputTokens)*p.cacheRead()
+	return total / 1e6
+}
+
+// cacheSavings returns how much less the cache reads in usage cost than uncached input
+func (p ModelPrice) cacheSavings(usage Usage) float64 {
+	return float64(usage.CacheReadInputTokens) * (p.Input - p.cacheRead()) / 1e6
+}
+
+func (p ModelPrice) cacheRead() float64 {
+	if p.CacheRead == 0 {
+		return p.Input * 0.1
+	}
+	return p.CacheRead
+}
+
+// Pricing maps model IDs to their prices
+type Pricing map[string]ModelPrice
+
+// DefaultPricing holds list prices for common models. Override or extend it with the
+// pricing section of the config when prices change or a model is missing.
+var DefaultPricing = Pricing{
+	"claude-opus-4-5":   {Input: 5, Output: 25},
+	"claude-opus-4-1":   {Input: 15, Output: 75},
+	"claude-opus-4":     {Input: 15, Output: 75},
+	"claude-sonnet-4-5": {Input: 3, Output: 15},
+	"claude-sonnet-4":   {Input: 3, Output: 15},
+	"claude-haiku-4-5":  {Input: 1, Output: 5},
+	"claude-3-7-sonnet": {Input: 3, Output: 15},
+	"claude-3-5-sonnet": {Input: 3, Output: 15},
+	"claude-3-5-haiku":  {Input: 0.8, Output: 4},
+	"claude-3-opus":     {Input: 15, Output: 75},
+	"claude-3-haiku":    {Input: 0.25, Output: 1.25, CacheWrite5m: 0.3, CacheRead: 0.03},
+	"gpt-4o":            {Input: 2.5, Output: 10, CacheRead: 1.25},
+	"gpt-4o-mini":       {Input: 0.15, Output: 0.6, CacheRead: 0.075},
+	"gpt-4.1":           {Input: 2, Output: 8, CacheRead: 0.5},
+	"gpt-4.1-mini":      {Input: 0.4, Output: 1.6, CacheRead: 0.1},
+	"gpt-4.1-nano":      {Input: 0.1, Output: 0.4, CacheRead: 0.025},
+}
+
+// Merge returns a copy of the pricing with overrides added, replacing any existing entries
+func (p Pricing) Merge(overrides Pricing) Pricing {
+	merged := make(Pricing, len(p)+len(overrides))
+	for model, price := range p {
+		merged[model] = price
+	}
+	for model, price := range overrides {
+		merged[model] = price
+	}
+	return merged
+}
+
+// Validate checks the price of every model
+func (p Pricing) Validate() error {
+	models := make([]string, 0, len(p))
+	for model := range p {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+
+	for _, model := range models {
+		if err := p[model].Validate(); err != nil {
+			return fmt.Errorf("invalid pricing for model '%s': %w", model, err)
+		}
+	}
+	return nil
+}
+
+// Lookup returns the price of a model. An exact match wins, otherwise the longest
+// entry the model ID starts with is used so dated IDs such as
+// claude-sonnet-4-5-20250929 match claude-sonnet-4-5.
+func (p Pricing) Lookup(model string) (ModelPrice, bool) {
+	if price, ok := p[model]; ok {
+		return price, true
+	}
+
+	best := ""
+	for prefix := range p {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return p[best], true
+}
+
+// Cost is the USD cost of an eval run. Agent covers the agent's steps along with the
+// sampling and simulated user requests made on its behalf.
+type Cost struct {
+	Agent          float64  `json:"agent"`                     // Agent steps, sampling and simulated user requests
+	Grading        float64  `json:"grading"`                   // The grading request
+	Total          float64  `json:"total"`                     // Agent plus grading
+	CacheSavings   float64  `json:"cache_savings,omitempty"`   // How much cache reads saved compared to uncached input
+	UnpricedModels []string `json:"unpriced_models,omitempty"` // Models without a price, their tokens are not counted
+}
+
+// Add returns the sum of two costs, used to total trials and runs
+func (c *Cost) Add(other *Cost) *Cost {
+	if other == nil {
+		return c
+	}
+	if c == nil {
+		c = &Cost{}
+	}
+	sum := &Cost{
+		Agent:          c.Agent + other.Agent,
+		Grading:        c.Grading + other.Grading,
+		Total:          c.Total + other.Total,
+		CacheSavings:   c.CacheSavings + other.CacheSavings,
+		UnpricedModels: unionStrings(c.UnpricedModels, other.UnpricedModels),
+	}
+	sort.Strings(sum.UnpricedModels)
+	return sum
+}
+
+// traceCost prices the model requests recorded in a trace. The agent steps are priced
+// for agentModel and cache writes for cacheTTL.
+func (p Pricing) traceCost(trace *EvalTrace, agentModel, cacheTTL string) *Cost {
+	cost := &Cost{}
+	unpriced := map[string]bool{}
+
+	price := func(model string, usage Usage) float64 {
+		modelPrice, ok := p.Lookup(model)
+		if !ok {
+			unpriced[model] = true
+			return 0
+		}
+		cost.CacheSavings += modelPrice.cacheSavings(usage)
+		return modelPrice.cost(usage, cacheTTL)
+	}
+
+	for _, step := range trace.Steps {
+		cost.Agent += price(agentModel, Usage{
+			InputTokens:              step.InputTokens,
+			OutputTokens:             step.OutputTokens,
+			CacheCreationInputTokens: step.CacheCreationInputTokens,
+			CacheReadInputTokens:     step.CacheReadInputTokens,
+		})
+	}
+	for _, request := range trace.ServerRequests {
+		if request.Model != "" {
+			cost.Agent += price(request.Model, Usage{InputTokens: request.InputTokens, OutputTokens: request.OutputTokens})
+		}
+	}
+	for _, turn := range trace.Turns {
+		if call := turn.Simulation; call != nil {
+			cost.Agent += price(call.Model, Usage{InputTokens: call.InputTokens, OutputTokens: call.OutputTokens})
+		}
+	}
+	if grading := trace.Grading; grading != nil && grading.Model != "" {
+		cost.Grading = price(grading.Model, Usage{
+			InputTokens:              grading.InputTokens,
+			OutputTokens:             grading.OutputTokens,
+			CacheCreationInputTokens: grading.CacheCreationInputTokens,
+			CacheReadInputTokens:     grading.CacheReadInputTokens,
+		})
+	}
+
+	cost.Total = cost.Agent + cost.Grading
+	for model := range unpriced {
+		cost.UnpricedModels = append(cost.UnpricedModels, model)
+	}
+	sort.Strings(cost.UnpricedModels)
+	return cost
+}
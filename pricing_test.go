@@ -0,0 +1,140 @@
+package evaluations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPricing_Lookup(t *testing.T) {
+	pricing := Pricing{
+		"claude-sonnet-4":   {Input: 3, Output: 15},
+		"claude-sonnet-4-5": {Input: 4, Output: 20},
+		"exact-model":       {Input: 1, Output: 2},
+	}
+
+	tests := []struct {
+		model     string
+		wantInput float64
+		wantOK    bool
+	}{
+		{model: "exact-model", wantInput: 1, wantOK: true},
+		{model: "claude-sonnet-4-5-20250929", wantInput: 4, wantOK: true},
+		{model: "claude-sonnet-4-20250514", wantInput: 3, wantOK: true},
+		{model: "mystery-model", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			assert := require.New(t)
+
+			price, ok := pricing.Lookup(tt.model)
+			assert.Equal(tt.wantOK, ok)
+			assert.Equal(tt.wantInput, price.Input)
+		})
+	}
+}
+
+func TestModelPrice_Cost(t *testing.T) {
+	assert := require.New(t)
+
+	price := ModelPrice{Input: 10, Output: 50}
+	usage := Usage{
+		InputTokens:              1_000_000,
+		OutputTokens:             100_000,
+		CacheCreationInputTokens: 1_000_000,
+		CacheReadInputTokens:     1_000_000,
+	}
+
+	// Cache writes cost 1.25x input for 5 minutes and 2x for an hour, reads cost 0.1x
+	assert.InDelta(10+5+12.5+1, price.cost(usage, "5m"), 1e-9)
+	assert.InDelta(10+5+20+1, price.cost(usage, "1h"), 1e-9)
+	assert.InDelta(9, price.cacheSavings(usage), 1e-9)
+
+	explicit := ModelPrice{Input: 10, Output: 50, CacheWrite5m: 11, CacheRead: 2}
+	assert.InDelta(10+5+11+2, explicit.cost(usage, ""), 1e-9)
+	assert.InDelta(8, explicit.cacheSavings(usage), 1e-9)
+}
+
+func TestPricing_TraceCost(t *testing.T) {
+	assert := require.New(t)
+
+	pricing := Pricing{
+		"agent-model": {Input: 1, Output: 10},
+		"judge-model": {Input: 2, Output: 20},
+	}
+	trace := &EvalTrace{
+		Steps: []AgenticStep{
+			{InputTokens: 1_000_000, OutputTokens: 100_000},
+			{InputTokens: 500_000, OutputTokens: 100_000, CacheReadInputTokens: 1_000_000},
+		},
+		ServerRequests: []ServerRequest{
+			{Method: "sampling/createMessage", Model: "mystery-model", InputTokens: 1000, OutputTokens: 100},
+			{Method: "elicitation/create"},
+		},
+		Turns: []TurnTrace{
+			{TurnNumber: 1},
+			{TurnNumber: 2, Simulation: &ModelCall{Model: "agent-model", InputTokens: 1_000_000}},
+		},
+		Grading: &GradingTrace{Model: "judge-model", InputTokens: 1_000_000, OutputTokens: 100_000},
+	}
+
+	cost := pricing.traceCost(trace, "agent-model", "5m")
+	assert.InDelta(2+1.5+0.1+1, cost.Agent, 1e-9)
+	assert.InDelta(4, cost.Grading, 1e-9)
+	assert.InDelta(cost.Agent+cost.Grading, cost.Total, 1e-9)
+	assert.InDelta(0.9, cost.CacheSavings, 1e-9)
+	assert.Equal([]string{"mystery-model"}, cost.UnpricedModels)
+}
+
+func TestCost_Add(t *testing.T) {
+	assert := require.New(t)
+
+	var total *Cost
+	assert.Nil(total.Add(nil))
+
+	total = total.Add(&Cost{Agent: 1, Total: 1, UnpricedModels: []string{"b"}})
+	total = total.Add(&Cost{Agent: 2, Grading: 1, Total: 3, UnpricedModels: []string{"a", "b"}})
+	assert.Equal(&Cost{Agent: 3, Grading: 1, Total: 4, UnpricedModels: []string{"a", "b"}}, total)
+}
+
+func TestPricing_Validate(t *testing.T) {
+	assert := require.New(t)
+
+	assert.NoError(DefaultPricing.Validate())
+	assert.EqualError(Pricing{"bad-model": {Input: 1, Output: -1}}.Validate(),
+		"invalid pricing for model 'bad-model': output must not be negative, got -1")
+}
+
+func TestEvalClient_RunEval_Cost(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamToolUseResponse("toolu_1", "echo", `{"message":"hello"}`),
+		streamTextResponse("Echoed hello."),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		// One dollar per token keeps the arithmetic readable
+		Pricing: Pricing{"test-model": {Input: 1_000_000, Output: 1_000_000}},
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{Name: "echo", Prompt: "Echo hello"})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+
+	cost := result.Trace.Cost
+	assert.NotNil(cost)
+	assert.InDelta(float64(result.Trace.TotalInputTokens+result.Trace.TotalOutputTokens), cost.Agent, 1e-6)
+	assert.Positive(cost.Grading)
+	assert.InDelta(cost.Agent+cost.Grading, cost.Total, 1e-6)
+	assert.Empty(cost.UnpricedModels)
+}
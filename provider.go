@@ -0,0 +1,172 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ProviderName selects the LLM API used to run and grade evals
+type ProviderName string
+
+const (
+	ProviderAnthropic ProviderName = "anthropic" // Anthropic Messages API (default)
+	ProviderOpenAI    ProviderName = "openai"    // OpenAI-compatible chat completions API
+)
+
+var providerNames = []ProviderName{ProviderAnthropic, ProviderOpenAI}
+
+// Provider sends a conversation and the available tools to a model and returns its reply.
+// Implementations translate between these provider-neutral types and their own API.
+type Provider interface {
+	// Name identifies the provider in logs and errors
+	Name() ProviderName
+	// CreateMessage sends a single request and returns the complete response
+	CreateMessage(ctx context.Context, req ProviderRequest) (*ProviderResponse, error)
+}
+
+// ProviderRequest is a single model request
+type ProviderRequest struct {
+	Model       string
+	System      string
+	Messages    []Message
+	Tools       []ToolDefinition
+	MaxTokens   int
+	Temperature *float64 // Optional: sampling temperature, the provider's default when nil
+	// Optional: token budget for extended thinking, disabled when 0. Providers without
+	// extended thinking ignore it.
+	ThinkingBudget int
+}
+
+// ProviderResponse is the model's reply to a ProviderRequest
+type ProviderResponse struct {
+	Content    []ContentBlock
+	StopReason StopReason
+	Usage      Usage
+}
+
+// Text returns the concatenated text blocks of the response
+func (r *ProviderResponse) Text() string {
+	var sb strings.Builder
+	for _, block := range r.Content {
+		if block.Type == ContentText {
+			sb.WriteString(block.Text)
+		}
+	}
+	return sb.String()
+}
+
+// Thinking returns the concatenated thinking blocks of the response
+func (r *ProviderResponse) Thinking() string {
+	var parts []string
+	for _, block := range r.Content {
+		if block.Type == ContentThinking && block.Text != "" {
+			parts = append(parts, block.Text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// ToolUses returns the tool use blocks of the response in order
+func (r *ProviderResponse) ToolUses() []ContentBlock {
+	var uses []ContentBlock
+	for _, block := range r.Content {
+		if block.Type == ContentToolUse {
+			uses = append(uses, block)
+		}
+	}
+	return uses
+}
+
+// StopReason explains why the model stopped generating
+type StopReason string
+
+const (
+	StopReasonEndTurn   StopReason = "end_turn"   // The model finished its answer
+	StopReasonToolUse   StopReason = "tool_use"   // The model wants the results of one or more tool calls
+	StopReasonMaxTokens StopReason = "max_tokens" // The response was cut off at MaxTokens
+)
+
+// Role is the author of a message in the conversation
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Message is a single turn in the conversation sent to a provider
+type Message struct {
+	Role    Role
+	Content []ContentBlock
+}
+
+// ContentType identifies the kind of a content block
+type ContentType string
+
+const (
+	ContentText       ContentType = "text"        // Plain text
+	ContentToolUse    ContentType = "tool_use"    // A tool call requested by the model
+	ContentToolResult ContentType = "tool_result" // The result of a tool call, sent back to the model
+
+	// Extended thinking returned by the model. Both kinds must be sent back unchanged
+	// with the tool results that follow them.
+	ContentThinking         ContentType = "thinking"          // The model's reasoning
+	ContentRedactedThinking ContentType = "redacted_thinking" // Reasoning encrypted by the provider
+)
+
+// ContentBlock is one piece of a message's content
+type ContentBlock struct {
+	Type      ContentType
+	Text      string          // Text, thinking, the tool result content, or the encrypted redacted thinking
+	Signature string          // Signature verifying the thinking text (thinking)
+	ToolUseID string          // ID of the tool call (tool_use and tool_result)
+	ToolName  string          // Name of the tool to call (tool_use)
+	Input     json.RawMessage // Tool arguments as JSON (tool_use)
+	IsError   bool            // Whether the tool call failed (tool_result)
+	Images    []Image         // Images returned by the tool alongside the text (tool_result)
+}
+
+// Image is an image returned by a tool
+type Image struct {
+	MIMEType string
+	Data     []byte // Raw image bytes
+}
+
+// TextBlock returns a text content block
+func TextBlock(text string) ContentBlock {
+	return ContentBlock{Type: ContentText, Text: text}
+}
+
+// ToolResultBlock returns a tool result content block for the given tool call
+func ToolResultBlock(toolUseID, content string, isError bool) ContentBlock {
+	return ContentBlock{Type: ContentToolResult, ToolUseID: toolUseID, Text: content, IsError: isError}
+}
+
+// ToolDefinition describes a tool the model may call
+type ToolDefinition struct {
+	Name        string
+	Description string
+	InputSchema map[string]any // JSON Schema for the tool arguments
+}
+
+// Usage records the tokens consumed by a request
+type Usage struct {
+	InputTokens              int // Uncached input tokens
+	OutputTokens             int
+	CacheCreationInputTokens int // Input tokens written to the prompt cache
+	CacheReadInputTokens     int // Input tokens read from the prompt cache
+}
+
+// newProvider creates the provider selected by the client config
+func newProvider(config EvalClientConfig) (Provider, error) {
+	switch config.Provider {
+	case "", ProviderAnthropic:
+		return newAnthropicProvider(config), nil
+	case ProviderOpenAI:
+		return newOpenAIProvider(config), nil
+	default:
+		return nil, fmt.Errorf("invalid provider '%s': must be one of: %s", config.Provider, joinEnum(providerNames))
+	}
+}
@@ -0,0 +1,221 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+// anthropicProvider implements Provider using the Anthropic Messages API
+type anthropicProvider struct {
+	client        anthropic.Client
+	enableCaching bool
+	cacheTTL      string
+}
+
+func newAnthropicProvider(config EvalClientConfig) *anthropicProvider {
+	opts := []option.RequestOption{}
+	if config.APIKey != "" {
+		opts = append(opts, option.WithAPIKey(config.APIKey))
+	}
+	if config.BaseURL != "" {
+		opts = append(opts, option.WithBaseURL(config.BaseURL))
+	}
+
+	// Retries are handled by withRetry so each attempt can be traced
+	opts = append(opts, option.WithMaxRetries(0))
+
+	// enable 1m tokens beta for sonnet models
+	opts = append(opts, option.WithHeader("anthropic-beta", anthropic.AnthropicBetaContext1m2025_08_07))
+
+	return &anthropicProvider{
+		client:        anthropic.NewClient(opts...), // uses ANTHROPIC_API_KEY from env
+		enableCaching: config.EnablePromptCaching != nil && *config.EnablePromptCaching,
+		cacheTTL:      config.CacheTTL,
+	}
+}
+
+func (p *anthropicProvider) Name() ProviderName {
+	return ProviderAnthropic
+}
+
+// CreateMessage streams the response so long generations don't hit request timeouts
+func (p *anthropicProvider) CreateMessage(ctx context.Context, req ProviderRequest) (*ProviderResponse, error) {
+	params := anthropic.MessageNewParams{
+		Model:     anthropic.Model(req.Model),
+		MaxTokens: int64(req.MaxTokens),
+		Messages:  p.messageParams(req.Messages),
+		Tools:     p.toolParams(req.Tools),
+	}
+	if req.Temperature != nil {
+		params.Temperature = anthropic.Float(*req.Temperature)
+	}
+	if req.ThinkingBudget > 0 {
+		params.Thinking = anthropic.ThinkingConfigParamOfEnabled(int64(req.ThinkingBudget))
+	}
+
+	if req.System != "" {
+		systemPrompt := anthropic.TextBlockParam{
+			Text: req.System,
+		}
+		if p.enableCaching {
+			systemPrompt.CacheControl = p.cacheControl()
+		}
+		params.System = []anthropic.TextBlockParam{systemPrompt}
+	}
+
+	stream := p.client.Messages.NewStreaming(ctx, params)
+	defer func() { _ = stream.Close() }()
+
+	message := anthropic.Message{}
+	for stream.Next() {
+		if err := message.Accumulate(stream.Current()); err != nil {
+			return nil, fmt.Errorf("failed to accumulate event: %w", err)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, fmt.Errorf("streaming error: %w", err)
+	}
+
+	resp := &ProviderResponse{
+		StopReason: StopReason(message.StopReason),
+		Usage: Usage{
+			InputTokens:              int(message.Usage.InputTokens),
+			OutputTokens:             int(message.Usage.OutputTokens),
+			CacheCreationInputTokens: int(message.Usage.CacheCreationInputTokens),
+			CacheReadInputTokens:     int(message.Usage.CacheReadInputTokens),
+		},
+	}
+
+	for _, block := range message.Content {
+		switch variant := block.AsAny().(type) {
+		case anthropic.TextBlock:
+			resp.Content = append(resp.Content, TextBlock(variant.Text))
+		case anthropic.ThinkingBlock:
+			resp.Content = append(resp.Content, ContentBlock{
+				Type:      ContentThinking,
+				Text:      variant.Thinking,
+				Signature: variant.Signature,
+			})
+		case anthropic.RedactedThinkingBlock:
+			resp.Content = append(resp.Content, ContentBlock{Type: ContentRedactedThinking, Text: variant.Data})
+		case anthropic.ToolUseBlock:
+			resp.Content = append(resp.Content, ContentBlock{
+				Type:      ContentToolUse,
+				ToolUseID: variant.ID,
+				ToolName:  variant.Name,
+				Input:     variant.Input,
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+func (p *anthropicProvider) messageParams(messages []Message) []anthropic.MessageParam {
+	params := make([]anthropic.MessageParam, 0, len(messages))
+	for _, message := range messages {
+		blocks := make([]anthropic.ContentBlockParamUnion, 0, len(message.Content))
+		for _, block := range message.Content {
+			switch block.Type {
+			case ContentText:
+				blocks = append(blocks, anthropic.NewTextBlock(block.Text))
+			case ContentThinking:
+				blocks = append(blocks, anthropic.NewThinkingBlock(block.Signature, block.Text))
+			case ContentRedactedThinking:
+				blocks = append(blocks, anthropic.NewRedactedThinkingBlock(block.Text))
+			case ContentToolUse:
+				blocks = append(blocks, anthropic.NewToolUseBlock(block.ToolUseID, block.Input, block.ToolName))
+			case ContentToolResult:
+				blocks = append(blocks, toolResultParam(block))
+			}
+		}
+
+		if message.Role == RoleAssistant {
+			params = append(params, anthropic.NewAssistantMessage(blocks...))
+		} else {
+			params = append(params, anthropic.NewUserMessage(blocks...))
+		}
+	}
+	return params
+}
+
+// anthropicImageTypes are the image formats the Messages API accepts
+var anthropicImageTypes = map[string]bool{"image/jpeg": true, "image/png": true, "image/gif": true, "image/webp": true}
+
+// toolResultParam converts a tool result, sending any supported images as image blocks
+// so the model can see them
+func toolResultParam(block ContentBlock) anthropic.ContentBlockParamUnion {
+	param := anthropic.NewToolResultBlock(block.ToolUseID, block.Text, block.IsError)
+	for _, image := range block.Images {
+		if !anthropicImageTypes[image.MIMEType] {
+			continue
+		}
+		param.OfToolResult.Content = append(param.OfToolResult.Content, anthropic.ToolResultBlockParamContentUnion{
+			OfImage: &anthropic.ImageBlockParam{
+				Source: anthropic.ImageBlockParamSourceUnion{
+					OfBase64: &anthropic.Base64ImageSourceParam{
+						Data:      base64.StdEncoding.EncodeToString(image.Data),
+						MediaType: anthropic.Base64ImageSourceMediaType(image.MIMEType),
+					},
+				},
+			},
+		})
+	}
+	return param
+}
+
+func (p *anthropicProvider) toolParams(tools []ToolDefinition) []anthropic.ToolUnionParam {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	toolParams := make([]anthropic.ToolParam, 0, len(tools))
+	for _, tool := range tools {
+		inputSchema := anthropic.ToolInputSchemaParam{
+			Properties: tool.InputSchema["properties"],
+			Required:   stringList(tool.InputSchema["required"]),
+		}
+		// Keep the remaining keywords (additionalProperties, $defs etc.) alongside properties
+		for key, value := range tool.InputSchema {
+			switch key {
+			case "type", "properties", "required":
+			default:
+				if inputSchema.ExtraFields == nil {
+					inputSchema.ExtraFields = make(map[string]any)
+				}
+				inputSchema.ExtraFields[key] = value
+			}
+		}
+
+		toolParams = append(toolParams, anthropic.ToolParam{
+			Name:        tool.Name,
+			Description: anthropic.String(tool.Description),
+			InputSchema: inputSchema,
+		})
+	}
+
+	// Add cache control to the last tool definition if caching is enabled
+	// This creates a cache breakpoint after all tools, maximizing cache reuse
+	if p.enableCaching {
+		toolParams[len(toolParams)-1].CacheControl = p.cacheControl()
+	}
+
+	unions := make([]anthropic.ToolUnionParam, len(toolParams))
+	for i := range toolParams {
+		unions[i] = anthropic.ToolUnionParam{OfTool: &toolParams[i]}
+	}
+	return unions
+}
+
+func (p *anthropicProvider) cacheControl() anthropic.CacheControlEphemeralParam {
+	cacheControl := anthropic.NewCacheControlEphemeralParam()
+	// Set TTL if specified (5m or 1h)
+	if p.cacheTTL == "1h" {
+		cacheControl.TTL = "1h"
+	}
+	return cacheControl
+}
@@ -0,0 +1,334 @@
+package evaluations
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// openAIProvider implements Provider using an OpenAI-compatible chat completions API
+type openAIProvider struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newOpenAIProvider(config EvalClientConfig) *openAIProvider {
+	apiKey := config.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = os.Getenv("OPENAI_BASE_URL")
+	}
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+
+	return &openAIProvider{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+}
+
+func (p *openAIProvider) Name() ProviderName {
+	return ProviderOpenAI
+}
+
+// openAIError is a non-2xx response from an OpenAI-compatible API
+type openAIError struct {
+	StatusCode int
+	Header     http.Header
+	Type       string
+	Message    string
+}
+
+func (e *openAIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("openai: %d %s: %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("openai: %d: %s", e.StatusCode, e.Message)
+}
+
+type openAIChatRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Tools       []openAITool    `json:"tools,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Temperature *float64        `json:"temperature,omitempty"`
+}
+
+type openAIMessage struct {
+	Role       string              `json:"role"`
+	Content    *string             `json:"content"`
+	Parts      []openAIContentPart `json:"-"` // Sent as the content instead of Content when set
+	ToolCalls  []openAIToolCall    `json:"tool_calls,omitempty"`
+	ToolCallID string              `json:"tool_call_id,omitempty"`
+}
+
+// MarshalJSON sends Parts as the message content when the message has any
+func (m openAIMessage) MarshalJSON() ([]byte, error) {
+	type message openAIMessage
+	if len(m.Parts) == 0 {
+		return json.Marshal(message(m))
+	}
+	return json.Marshal(struct {
+		message
+		Content []openAIContentPart `json:"content"`
+	}{message(m), m.Parts})
+}
+
+// openAIContentPart is a text or image part of a user message
+type openAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+// openAIImageTypes are the image formats chat completions accept
+var openAIImageTypes = map[string]bool{"image/jpeg": true, "image/png": true, "image/gif": true, "image/webp": true}
+
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type openAITool struct {
+	Type     string             `json:"type"`
+	Function openAIToolFunction `json:"function"`
+}
+
+type openAIToolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens        int `json:"prompt_tokens"`
+		CompletionTokens    int `json:"completion_tokens"`
+		PromptTokensDetails struct {
+			CachedTokens int `json:"cached_tokens"`
+		} `json:"prompt_tokens_details"`
+	} `json:"usage"`
+}
+
+func (p *openAIProvider) CreateMessage(ctx context.Context, req ProviderRequest) (*ProviderResponse, error) {
+	body, err := json.Marshal(openAIChatRequest{
+		Model:       req.Model,
+		Messages:    p.messages(req.System, req.Messages),
+		Tools:       p.tools(req.Tools),
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat completion request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chat completion request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	httpResp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("chat completion request failed: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chat completion response: %w", err)
+	}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		apiErr := &openAIError{
+			StatusCode: httpResp.StatusCode,
+			Header:     httpResp.Header,
+			Message:    strings.TrimSpace(string(respBody)),
+		}
+		var errResp struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+			apiErr.Type = errResp.Error.Type
+			apiErr.Message = errResp.Error.Message
+		}
+		return nil, apiErr
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode chat completion response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("chat completion response has no choices")
+	}
+
+	choice := chatResp.Choices[0]
+	cached := chatResp.Usage.PromptTokensDetails.CachedTokens
+	resp := &ProviderResponse{
+		StopReason: openAIStopReason(choice.FinishReason),
+		Usage: Usage{
+			// prompt_tokens includes cached tokens, report them separately like Anthropic does
+			InputTokens:          chatResp.Usage.PromptTokens - cached,
+			OutputTokens:         chatResp.Usage.CompletionTokens,
+			CacheReadInputTokens: cached,
+		},
+	}
+
+	if choice.Message.Content != nil && *choice.Message.Content != "" {
+		resp.Content = append(resp.Content, TextBlock(*choice.Message.Content))
+	}
+	for _, call := range choice.Message.ToolCalls {
+		input := json.RawMessage(call.Function.Arguments)
+		if len(input) == 0 {
+			input = json.RawMessage(`{}`)
+		}
+		resp.Content = append(resp.Content, ContentBlock{
+			Type:      ContentToolUse,
+			ToolUseID: call.ID,
+			ToolName:  call.Function.Name,
+			Input:     input,
+		})
+	}
+
+	return resp, nil
+}
+
+// messages converts the conversation to chat messages. Tool results, which
+// Anthropic sends as user content blocks, become separate tool messages. Tool
+// messages only carry text, so images from tool results follow them in a user message.
+func (p *openAIProvider) messages(system string, messages []Message) []openAIMessage {
+	var result []openAIMessage
+	if system != "" {
+		result = append(result, openAIMessage{Role: "system", Content: &system})
+	}
+
+	for _, message := range messages {
+		var text strings.Builder
+		var toolCalls []openAIToolCall
+		var toolResults []openAIMessage
+		var images []openAIContentPart
+
+		for _, block := range message.Content {
+			switch block.Type {
+			case ContentText:
+				text.WriteString(block.Text)
+			case ContentToolUse:
+				call := openAIToolCall{ID: block.ToolUseID, Type: "function"}
+				call.Function.Name = block.ToolName
+				call.Function.Arguments = string(block.Input)
+				toolCalls = append(toolCalls, call)
+			case ContentToolResult:
+				content := block.Text
+				if block.IsError {
+					// Tool messages have no error flag, so the failure is stated in the content
+					content = "Error: " + content
+				}
+				toolResults = append(toolResults, openAIMessage{Role: "tool", Content: &content, ToolCallID: block.ToolUseID})
+				images = append(images, toolResultImages(block)...)
+			}
+		}
+
+		result = append(result, toolResults...)
+		if len(images) > 0 {
+			result = append(result, openAIMessage{Role: string(RoleUser), Parts: images})
+		}
+		if text.Len() > 0 || len(toolCalls) > 0 {
+			msg := openAIMessage{Role: string(message.Role), ToolCalls: toolCalls}
+			if text.Len() > 0 {
+				content := text.String()
+				msg.Content = &content
+			}
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+// toolResultImages converts the supported images of a tool result to content parts,
+// introduced by a text part naming the tool call they came from
+func toolResultImages(block ContentBlock) []openAIContentPart {
+	var parts []openAIContentPart
+	for _, image := range block.Images {
+		if !openAIImageTypes[image.MIMEType] {
+			continue
+		}
+		if len(parts) == 0 {
+			parts = append(parts, openAIContentPart{Type: "text", Text: fmt.Sprintf("Images returned by tool call %s:", block.ToolUseID)})
+		}
+		parts = append(parts, openAIContentPart{
+			Type:     "image_url",
+			ImageURL: &openAIImageURL{URL: "data:" + image.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(image.Data)},
+		})
+	}
+	return parts
+}
+
+func (p *openAIProvider) tools(tools []ToolDefinition) []openAITool {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	result := make([]openAITool, 0, len(tools))
+	for _, tool := range tools {
+		parameters := tool.InputSchema
+		if parameters == nil {
+			parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		result = append(result, openAITool{
+			Type: "function",
+			Function: openAIToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  parameters,
+			},
+		})
+	}
+	return result
+}
+
+func openAIStopReason(finishReason string) StopReason {
+	switch finishReason {
+	case "stop":
+		return StopReasonEndTurn
+	case "tool_calls", "function_call":
+		return StopReasonToolUse
+	case "length":
+		return StopReasonMaxTokens
+	default:
+		return StopReason(finishReason)
+	}
+}
@@ -0,0 +1,268 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// chatResponse builds an OpenAI chat completion response that ends the turn with text
+func chatResponse(text string) fakeAPIResponse {
+	content, _ := json.Marshal(text)
+	return fakeAPIResponse{
+		body: fmt.Sprintf(`{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-test",`+
+			`"choices":[{"index":0,"message":{"role":"assistant","content":%s},"finish_reason":"stop"}],`+
+			`"usage":{"prompt_tokens":120,"completion_tokens":15,"prompt_tokens_details":{"cached_tokens":20}}}`, content),
+	}
+}
+
+// chatToolCallResponse builds an OpenAI chat completion response requesting a single tool call
+func chatToolCallResponse(id, name, arguments string) fakeAPIResponse {
+	args, _ := json.Marshal(arguments)
+	return fakeAPIResponse{
+		body: fmt.Sprintf(`{"id":"chatcmpl-1","object":"chat.completion","model":"gpt-test",`+
+			`"choices":[{"index":0,"message":{"role":"assistant","content":null,"tool_calls":[`+
+			`{"id":%q,"type":"function","function":{"name":%q,"arguments":%s}}]},"finish_reason":"tool_calls"}],`+
+			`"usage":{"prompt_tokens":80,"completion_tokens":10}}`, id, name, args),
+	}
+}
+
+// chatGradeResponse builds an OpenAI chat completion response containing a grade
+func chatGradeResponse(score int) fakeAPIResponse {
+	return chatResponse(fmt.Sprintf(`{"accuracy":%d,"completeness":%d,"relevance":%d,"clarity":%d,"reasoning":%d,"overall_comments":"fake grade"}`,
+		score, score, score, score, score))
+}
+
+func TestEvalClient_RunEval_OpenAIProvider(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		chatToolCallResponse("call_1", "add", `{"a":5,"b":3}`),
+		chatResponse("5 plus 3 is 8"),
+		chatGradeResponse(4),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		Provider:     ProviderOpenAI,
+		APIKey:       "test-key",
+		BaseURL:      api.URL,
+		Transport:    TransportStreamableHTTP,
+		URL:          mcpServer.URL,
+		Model:        "gpt-test",
+		GradingModel: "gpt-grader",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{Name: "add", Prompt: "What is 5 plus 3?"})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+	assert.Equal("5 plus 3 is 8", result.Result.RawResponse)
+	assert.NotNil(result.Grade)
+	assert.Equal(4, result.Grade.Accuracy)
+
+	trace := result.Trace
+	assert.Len(trace.Steps, 2)
+	assert.Equal(string(StopReasonToolUse), trace.Steps[0].StopReason)
+	assert.Len(trace.Steps[0].ToolCalls, 1)
+	assert.Equal("add", trace.Steps[0].ToolCalls[0].ToolName)
+	assert.True(trace.Steps[0].ToolCalls[0].Success)
+	assert.Contains(string(trace.Steps[0].ToolCalls[0].Output), "8")
+	assert.Equal(string(StopReasonEndTurn), trace.Steps[1].StopReason)
+
+	// Cached prompt tokens are reported separately from uncached input tokens
+	assert.Equal(100, trace.Steps[1].InputTokens)
+	assert.Equal(20, trace.Steps[1].CacheReadInputTokens)
+
+	requests := api.Requests()
+	assert.Len(requests, 3)
+
+	// The first request carries the system prompt and MCP tools as functions
+	first := requests[0]
+	assert.Equal("gpt-test", first["model"])
+	messages := first["messages"].([]any)
+	assert.Equal("system", messages[0].(map[string]any)["role"])
+	assert.Equal("user", messages[1].(map[string]any)["role"])
+	var addTool map[string]any
+	for _, tool := range first["tools"].([]any) {
+		function := tool.(map[string]any)["function"].(map[string]any)
+		if function["name"] == "add" {
+			addTool = function
+		}
+	}
+	assert.NotNil(addTool, "add tool should be offered to the model")
+	assert.Equal("object", addTool["parameters"].(map[string]any)["type"])
+
+	// The second request replays the tool call and sends its result as a tool message
+	messages = requests[1]["messages"].([]any)
+	assert.Len(messages, 4)
+	assistant := messages[2].(map[string]any)
+	assert.Equal("assistant", assistant["role"])
+	assert.Equal("call_1", assistant["tool_calls"].([]any)[0].(map[string]any)["id"])
+	toolMessage := messages[3].(map[string]any)
+	assert.Equal("tool", toolMessage["role"])
+	assert.Equal("call_1", toolMessage["tool_call_id"])
+	assert.Contains(toolMessage["content"], "8")
+
+	// Grading uses the grading model without tools
+	assert.Equal("gpt-grader", requests[2]["model"])
+	assert.Nil(requests[2]["tools"])
+}
+
+func TestEvalClient_RunEval_OpenAIProviderRetries(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		fakeAPIResponse{
+			status:  http.StatusTooManyRequests,
+			headers: map[string]string{"Retry-After-Ms": "10"},
+			body:    `{"error":{"type":"rate_limit_exceeded","message":"slow down"}}`,
+		},
+		chatResponse("8"),
+		chatGradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		Provider:  ProviderOpenAI,
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "gpt-test",
+		Retry:     RetryPolicy{BaseBackoff: time.Millisecond},
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{Name: "add", Prompt: "What is 5 plus 3?"})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+
+	attempts := result.Trace.Steps[0].Attempts
+	assert.Len(attempts, 2)
+	assert.Equal(http.StatusTooManyRequests, attempts[0].StatusCode)
+	assert.Contains(attempts[0].Error, "slow down")
+	assert.Equal(10*time.Millisecond, attempts[0].Backoff)
+}
+
+func TestOpenAIProvider_CreateMessage(t *testing.T) {
+	assert := require.New(t)
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		assert.Equal("/v1/chat/completions", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"partial"},"finish_reason":"length"}],` +
+			`"usage":{"prompt_tokens":10,"completion_tokens":5}}`))
+	}))
+	defer server.Close()
+
+	provider := newOpenAIProvider(EvalClientConfig{APIKey: "sk-test", BaseURL: server.URL + "/v1/"})
+	resp, err := provider.CreateMessage(context.Background(), ProviderRequest{
+		Model:    "gpt-test",
+		Messages: []Message{{Role: RoleUser, Content: []ContentBlock{TextBlock("hi")}}},
+	})
+	assert.NoError(err)
+	assert.Equal("Bearer sk-test", authorization)
+	assert.Equal(StopReasonMaxTokens, resp.StopReason)
+	assert.Equal("partial", resp.Text())
+	assert.Equal(Usage{InputTokens: 10, OutputTokens: 5}, resp.Usage)
+}
+
+func TestOpenAIProvider_ToolResultImages(t *testing.T) {
+	assert := require.New(t)
+
+	provider := newOpenAIProvider(EvalClientConfig{APIKey: "sk-test"})
+	toolResult := ToolResultBlock("call_1", "chart rendering failed", true)
+	toolResult.Images = []Image{
+		{MIMEType: "image/png", Data: []byte("png")},
+		{MIMEType: "image/tiff", Data: []byte("tiff")},
+	}
+	data, err := json.Marshal(provider.messages("", []Message{
+		{Role: RoleAssistant, Content: []ContentBlock{{Type: ContentToolUse, ToolUseID: "call_1", ToolName: "render_chart", Input: json.RawMessage(`{}`)}}},
+		{Role: RoleUser, Content: []ContentBlock{toolResult}},
+	}))
+	assert.NoError(err)
+
+	// The error is stated in the tool message and the supported images follow in a user message
+	assert.JSONEq(`[
+		{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"render_chart","arguments":"{}"}}]},
+		{"role":"tool","content":"Error: chart rendering failed","tool_call_id":"call_1"},
+		{"role":"user","content":[
+			{"type":"text","text":"Images returned by tool call call_1:"},
+			{"type":"image_url","image_url":{"url":"data:image/png;base64,cG5n"}}
+		]}
+	]`, string(data))
+}
+
+func TestOpenAIProvider_APIKeyFromEnv(t *testing.T) {
+	assert := require.New(t)
+
+	t.Setenv("OPENAI_API_KEY", "sk-env")
+
+	provider := newOpenAIProvider(EvalClientConfig{})
+	assert.Equal("sk-env", provider.apiKey)
+	assert.Equal(defaultOpenAIBaseURL, provider.baseURL)
+}
+
+func TestNewEvalClient_InvalidProvider(t *testing.T) {
+	assert := require.New(t)
+
+	client := NewEvalClient(EvalClientConfig{Provider: "bogus", Command: "echo"})
+
+	_, err := client.RunEval(context.Background(), Eval{Name: "test", Prompt: "test"})
+	assert.Error(err)
+	assert.Contains(err.Error(), "invalid provider 'bogus'")
+}
+
+func TestLoadConfig_Provider(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		wantErr  string
+	}{
+		{"default", "", ""},
+		{"anthropic", "anthropic", ""},
+		{"openai", "openai", ""},
+		{"invalid", "gemini", "invalid provider 'gemini'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			configContent := fmt.Sprintf(`
+provider: %q
+model: test-model
+mcp_server:
+  command: echo
+evals:
+  - name: test
+    prompt: "test"
+`, tt.provider)
+
+			tmpFile, err := os.CreateTemp("", "config-provider-*.yaml")
+			assert.NoError(err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(configContent)
+			assert.NoError(err)
+			tmpFile.Close()
+
+			config, err := LoadConfig(tmpFile.Name())
+			if tt.wantErr != "" {
+				assert.Error(err)
+				assert.Contains(err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(ProviderName(tt.provider), config.Provider)
+		})
+	}
+}
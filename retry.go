@@ -0,0 +1,199 @@
+package evaluations
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// RetryPolicy controls how rate-limit, overload and other transient errors from the model API are retried
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts per request, including the first. Default: 3
+	BaseBackoff time.Duration // Delay before the first retry, doubled for each retry after that. Default: 1s
+	MaxBackoff  time.Duration // Upper bound on the computed delay between attempts. Default: 30s
+	Jitter      *float64      // Fraction of each delay that is randomized, between 0 and 1. Default: 0.2
+}
+
+// ApplyDefaults sets default values for unset retry fields.
+func (p *RetryPolicy) ApplyDefaults() *RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.BaseBackoff <= 0 {
+		p.BaseBackoff = time.Second
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 30 * time.Second
+	}
+	if p.Jitter == nil {
+		p.Jitter = toPtr(0.2)
+	}
+	return p
+}
+
+// backoff returns the delay before the given retry (1-indexed), preferring the
+// server supplied retry-after value when present
+func (p *RetryPolicy) backoff(retry int, err error) time.Duration {
+	if retryAfter, ok := retryAfterFromError(err); ok {
+		return retryAfter
+	}
+
+	delay := p.BaseBackoff << (retry - 1)
+	if delay <= 0 || delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if p.Jitter != nil && *p.Jitter > 0 {
+		// Spread the delay evenly across +/- jitter to avoid synchronized retries
+		jitter := (rand.Float64()*2 - 1) * *p.Jitter * float64(delay) // #nosec G404 - jitter does not need a secure source
+		delay += time.Duration(jitter)
+	}
+
+	return max(delay, 0)
+}
+
+// RequestAttempt records a single attempt at a model API request
+type RequestAttempt struct {
+	Attempt    int           `json:"attempt"`               // 1-indexed attempt number
+	StartTime  time.Time     `json:"start_time"`            // When the attempt started
+	Duration   time.Duration `json:"duration"`              // How long the attempt took
+	StatusCode int           `json:"status_code,omitempty"` // HTTP status of a failed attempt, if known
+	Error      string        `json:"error,omitempty"`       // Error message if the attempt failed
+	Backoff    time.Duration `json:"backoff,omitempty"`     // Delay waited before the next attempt
+}
+
+// retryDuration returns the time lost to failed attempts and the backoff between them
+func retryDuration(attempts []RequestAttempt) time.Duration {
+	var total time.Duration
+	for _, attempt := range attempts {
+		if attempt.Error != "" {
+			total += attempt.Duration + attempt.Backoff
+		}
+	}
+	return total
+}
+
+// withRetry calls fn until it succeeds, fails with a non-retryable error or the
+// policy runs out of attempts. Every attempt is returned for tracing.
+func (ec *EvalClient) withRetry(ctx context.Context, fn func(ctx context.Context) error) ([]RequestAttempt, error) {
+	policy := ec.config.Retry
+
+	var attempts []RequestAttempt
+	for attemptNumber := 1; ; attemptNumber++ {
+		attempt := RequestAttempt{
+			Attempt:   attemptNumber,
+			StartTime: time.Now(),
+		}
+
+		err := fn(ctx)
+		attempt.Duration = time.Since(attempt.StartTime)
+
+		if err == nil {
+			attempts = append(attempts, attempt)
+			return attempts, nil
+		}
+
+		attempt.Error = err.Error()
+		if statusCode, _, ok := apiErrorStatus(err); ok {
+			attempt.StatusCode = statusCode
+		}
+
+		if attemptNumber >= policy.MaxAttempts || !isRetryableError(err) {
+			attempts = append(attempts, attempt)
+			return attempts, err
+		}
+
+		attempt.Backoff = policy.backoff(attemptNumber, err)
+		attempts = append(attempts, attempt)
+
+		timer := time.NewTimer(attempt.Backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempts, err
+		case <-timer.C:
+		}
+	}
+}
+
+// apiErrorStatus extracts the HTTP status code and response headers from a provider API error
+func apiErrorStatus(err error) (int, http.Header, bool) {
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		var header http.Header
+		if anthropicErr.Response != nil {
+			header = anthropicErr.Response.Header
+		}
+		return anthropicErr.StatusCode, header, true
+	}
+
+	var openAIErr *openAIError
+	if errors.As(err, &openAIErr) {
+		return openAIErr.StatusCode, openAIErr.Header, true
+	}
+
+	return 0, nil, false
+}
+
+// isRetryableError reports whether err is a rate-limit, overload, transient server or
+// connection error. The providers' own retries are disabled, so this covers the errors
+// their SDKs would retry.
+func isRetryableError(err error) bool {
+	// A cancelled eval must stop, whatever the request was doing when it was cancelled
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if statusCode, _, ok := apiErrorStatus(err); ok {
+		switch statusCode {
+		case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+			return true
+		}
+		return statusCode >= http.StatusInternalServerError
+	}
+
+	// Connections that failed, timed out or were cut off part way through a response
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	// Errors sent as events part way through an Anthropic stream arrive as plain errors
+	msg := err.Error()
+	return strings.Contains(msg, "overloaded_error") || strings.Contains(msg, "rate_limit_error")
+}
+
+// retryAfterFromError extracts the delay requested by the server in the
+// retry-after-ms or retry-after response headers
+func retryAfterFromError(err error) (time.Duration, bool) {
+	_, header, ok := apiErrorStatus(err)
+	if !ok || header == nil {
+		return 0, false
+	}
+
+	if value := header.Get("Retry-After-Ms"); value != "" {
+		if ms, err := strconv.ParseFloat(value, 64); err == nil && ms >= 0 {
+			return time.Duration(ms * float64(time.Millisecond)), true
+		}
+	}
+
+	if value := header.Get("Retry-After"); value != "" {
+		// retry-after is either a number of seconds or an HTTP date
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(time.Until(at), 0), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,190 @@
+package evaluations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := (&RetryPolicy{
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+		Jitter:      toPtr(0.0),
+	}).ApplyDefaults()
+
+	tests := []struct {
+		name     string
+		retry    int
+		err      error
+		expected time.Duration
+	}{
+		{name: "first retry uses base backoff", retry: 1, err: errors.New("boom"), expected: 100 * time.Millisecond},
+		{name: "doubles for each retry", retry: 3, err: errors.New("boom"), expected: 400 * time.Millisecond},
+		{name: "capped at max backoff", retry: 10, err: errors.New("boom"), expected: time.Second},
+		{
+			name:     "honors retry-after seconds",
+			retry:    1,
+			err:      apiErrorWithHeader(http.StatusTooManyRequests, "Retry-After", "2"),
+			expected: 2 * time.Second,
+		},
+		{
+			name:     "prefers retry-after-ms",
+			retry:    1,
+			err:      apiErrorWithHeader(529, "Retry-After-Ms", "250"),
+			expected: 250 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := require.New(t)
+
+			assert.Equal(tt.expected, policy.backoff(tt.retry, tt.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Jitter(t *testing.T) {
+	assert := require.New(t)
+
+	policy := (&RetryPolicy{
+		BaseBackoff: time.Second,
+		Jitter:      toPtr(0.5),
+	}).ApplyDefaults()
+
+	for range 100 {
+		delay := policy.backoff(1, errors.New("boom"))
+		assert.GreaterOrEqual(delay, 500*time.Millisecond)
+		assert.LessOrEqual(delay, 1500*time.Millisecond)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "rate limited", err: &anthropic.Error{StatusCode: http.StatusTooManyRequests}, retryable: true},
+		{name: "overloaded", err: &anthropic.Error{StatusCode: 529}, retryable: true},
+		{name: "request timeout", err: &anthropic.Error{StatusCode: http.StatusRequestTimeout}, retryable: true},
+		{name: "conflict", err: &openAIError{StatusCode: http.StatusConflict}, retryable: true},
+		{name: "bad request", err: &anthropic.Error{StatusCode: http.StatusBadRequest}, retryable: false},
+		{name: "connection refused", err: &url.Error{Op: "Post", URL: "https://api.anthropic.com/v1/messages", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}, retryable: true},
+		{name: "response cut off", err: fmt.Errorf("reading stream: %w", io.ErrUnexpectedEOF), retryable: true},
+		{name: "cancelled", err: &url.Error{Op: "Post", URL: "https://api.anthropic.com/v1/messages", Err: context.Canceled}, retryable: false},
+		{name: "cancelled overloaded stream", err: fmt.Errorf("overloaded_error: %w", context.Canceled), retryable: false},
+		{name: "overloaded stream event", err: errors.New(`received error while streaming: {"type":"error","error":{"type":"overloaded_error"}}`), retryable: true},
+		{name: "other error", err: errors.New("failed to accumulate event"), retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert := require.New(t)
+
+			assert.Equal(tt.retryable, isRetryableError(tt.err))
+		})
+	}
+}
+
+func TestEvalClient_RunEval_RetriesRateLimits(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		apiError(http.StatusTooManyRequests, "rate_limit_error", map[string]string{"Retry-After-Ms": "10"}),
+		streamToolUseResponse("toolu_1", "add", `{"a":5,"b":3}`),
+		streamTextResponse("5 plus 3 is 8"),
+		apiError(529, "overloaded_error", nil),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		Retry:     RetryPolicy{BaseBackoff: 10 * time.Millisecond},
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{Name: "add", Prompt: "What is 5 plus 3?"})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+	assert.NotNil(result.Grade)
+	assert.Equal(5, result.Grade.Accuracy)
+	assert.Contains(result.Result.RawResponse, "8")
+
+	trace := result.Trace
+	assert.Len(trace.Steps, 2)
+
+	// The first step was rate limited once before succeeding
+	firstStep := trace.Steps[0]
+	assert.Len(firstStep.Attempts, 2)
+	assert.Equal(http.StatusTooManyRequests, firstStep.Attempts[0].StatusCode)
+	assert.Equal(10*time.Millisecond, firstStep.Attempts[0].Backoff)
+	assert.Empty(firstStep.Attempts[1].Error)
+	assert.Positive(firstStep.RetryDuration)
+	assert.Len(firstStep.ToolCalls, 1)
+	assert.True(firstStep.ToolCalls[0].Success)
+
+	assert.Len(trace.Steps[1].Attempts, 1)
+	assert.Zero(trace.Steps[1].RetryDuration)
+
+	// Grading was overloaded once before succeeding
+	assert.Len(trace.Grading.Attempts, 2)
+	assert.Equal(529, trace.Grading.Attempts[0].StatusCode)
+	assert.Positive(trace.Grading.RetryDuration)
+
+	assert.Len(api.Requests(), 5)
+}
+
+func TestEvalClient_RunEval_RetriesExhausted(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		apiError(http.StatusTooManyRequests, "rate_limit_error", nil),
+		apiError(http.StatusTooManyRequests, "rate_limit_error", nil),
+		streamTextResponse("never reached"),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		Retry:     RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond},
+	})
+
+	_, err := client.RunEval(context.Background(), Eval{Name: "add", Prompt: "What is 5 plus 3?"})
+	assert.Error(err)
+
+	var apiErr *anthropic.Error
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal(http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Len(api.Requests(), 2)
+}
+
+func apiErrorWithHeader(status int, key, value string) error {
+	header := http.Header{}
+	header.Set(key, value)
+	return &anthropic.Error{
+		StatusCode: status,
+		Response:   &http.Response{StatusCode: status, Header: header},
+	}
+}
@@ -0,0 +1,380 @@
+package evaluations
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Schema keywords grouped by the shape of their value, everything else is copied as is
+var (
+	schemaValuedKeywords = map[string]bool{
+		"items": true, "additionalItems": true, "unevaluatedItems": true, "contains": true,
+		"additionalProperties": true, "unevaluatedProperties": true, "propertyNames": true,
+		"not": true, "if": true, "then": true, "else": true,
+	}
+	schemaListKeywords = map[string]bool{"allOf": true, "anyOf": true, "oneOf": true, "prefixItems": true}
+	schemaMapKeywords  = map[string]bool{"properties": true, "patternProperties": true, "dependentSchemas": true}
+)
+
+// toolDefinitions converts MCP tools to provider-neutral definitions, normalizing each
+// input schema with NormalizeToolSchema. The warnings are prefixed with the tool name.
+func toolDefinitions(tools []*mcp.Tool) ([]ToolDefinition, []string) {
+	definitions := make([]ToolDefinition, 0, len(tools))
+	var warnings []string
+
+	for _, tool := range tools {
+		var schema map[string]any
+		if tool.InputSchema != nil {
+			// MCP uses JSON Schema, convert to map
+			schemaBytes, _ := json.Marshal(tool.InputSchema)
+			_ = json.Unmarshal(schemaBytes, &schema)
+		}
+
+		schema, schemaWarnings := NormalizeToolSchema(schema)
+		for _, warning := range schemaWarnings {
+			warnings = append(warnings, fmt.Sprintf("tool '%s': %s", tool.Name, warning))
+		}
+
+		definitions = append(definitions, ToolDefinition{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: schema,
+		})
+	}
+
+	return definitions, warnings
+}
+
+// NormalizeToolSchema translates an MCP tool input schema into one the model APIs accept
+// while keeping as much of it as possible. Local $ref pointers are inlined and their
+// $defs removed, a top-level allOf, anyOf or oneOf is flattened into a single object and
+// the root is forced to type object. The returned warnings describe everything that had
+// to be dropped or loosened; the input is not modified.
+func NormalizeToolSchema(schema map[string]any) (map[string]any, []string) {
+	n := &schemaNormalizer{
+		root:      schema,
+		resolving: make(map[string]bool),
+		warned:    make(map[string]bool),
+	}
+
+	out, _ := n.schema(schema, "#").(map[string]any)
+	if out == nil {
+		out = map[string]any{}
+	}
+	n.normalizeRoot(out)
+
+	return out, n.warnings
+}
+
+type schemaNormalizer struct {
+	root      map[string]any
+	resolving map[string]bool // $ref pointers being inlined on the current path, to detect cycles
+	warnings  []string
+	warned    map[string]bool
+}
+
+func (n *schemaNormalizer) warn(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if n.warned[msg] {
+		return
+	}
+	n.warned[msg] = true
+	n.warnings = append(n.warnings, msg)
+}
+
+// schema normalizes a schema, which is either an object or a boolean
+func (n *schemaNormalizer) schema(node any, path string) any {
+	m, ok := node.(map[string]any)
+	if !ok {
+		return node
+	}
+
+	if ref, ok := m["$ref"].(string); ok {
+		return n.ref(m, ref, path)
+	}
+
+	out := make(map[string]any, len(m))
+	for _, key := range sortedKeys(m) {
+		value := m[key]
+		switch {
+		case key == "$defs" || key == "definitions":
+			// Inlined wherever they are referenced
+		case schemaValuedKeywords[key]:
+			if list, ok := value.([]any); ok && key == "items" {
+				out[key] = n.schemaList(list, path+"/items")
+			} else {
+				out[key] = n.schema(value, path+"/"+key)
+			}
+		case schemaListKeywords[key]:
+			if list, ok := value.([]any); ok {
+				out[key] = n.schemaList(list, path+"/"+key)
+			} else {
+				out[key] = value
+			}
+		case schemaMapKeywords[key]:
+			if props, ok := value.(map[string]any); ok {
+				normalized := make(map[string]any, len(props))
+				for _, name := range sortedKeys(props) {
+					normalized[name] = n.schema(props[name], path+"/"+key+"/"+name)
+				}
+				out[key] = normalized
+			} else {
+				out[key] = value
+			}
+		default:
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func (n *schemaNormalizer) schemaList(list []any, path string) []any {
+	out := make([]any, len(list))
+	for i, item := range list {
+		out[i] = n.schema(item, path+"/"+strconv.Itoa(i))
+	}
+	return out
+}
+
+// ref inlines the schema a $ref points to. Keywords next to the $ref override the target's.
+func (n *schemaNormalizer) ref(node map[string]any, ref, path string) any {
+	siblings := make(map[string]any, len(node))
+	for key, value := range node {
+		if key != "$ref" {
+			siblings[key] = value
+		}
+	}
+	withSiblings := func(resolved map[string]any) map[string]any {
+		if len(siblings) == 0 {
+			return resolved
+		}
+		normalized, _ := n.schema(siblings, path).(map[string]any)
+		for key, value := range normalized {
+			resolved[key] = value
+		}
+		return resolved
+	}
+
+	if !strings.HasPrefix(ref, "#") {
+		n.warn("external $ref '%s' at %s is not supported, replaced with an empty schema", ref, path)
+		return withSiblings(map[string]any{})
+	}
+	if n.resolving[ref] {
+		n.warn("recursive $ref '%s' at %s is not supported, replaced with an empty schema", ref, path)
+		return withSiblings(map[string]any{})
+	}
+
+	target, ok := resolvePointer(n.root, ref)
+	if !ok {
+		n.warn("$ref '%s' at %s does not resolve, replaced with an empty schema", ref, path)
+		return withSiblings(map[string]any{})
+	}
+
+	n.resolving[ref] = true
+	defer delete(n.resolving, ref)
+
+	switch resolved := n.schema(target, path).(type) {
+	case map[string]any:
+		return withSiblings(resolved)
+	case bool:
+		if len(siblings) == 0 {
+			return resolved
+		}
+		if !resolved {
+			return withSiblings(map[string]any{"not": map[string]any{}})
+		}
+		return withSiblings(map[string]any{})
+	default:
+		n.warn("$ref '%s' at %s does not point to a schema, replaced with an empty schema", ref, path)
+		return withSiblings(map[string]any{})
+	}
+}
+
+// resolvePointer follows a JSON pointer fragment such as "#/$defs/Address" from root
+func resolvePointer(root map[string]any, ref string) (any, bool) {
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return root, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	var node any = root
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := node.(type) {
+		case map[string]any:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			node = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			node = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// normalizeRoot rewrites the constructs the model APIs reject at the top level of a tool schema
+func (n *schemaNormalizer) normalizeRoot(root map[string]any) {
+	if allOf, ok := root["allOf"].([]any); ok {
+		delete(root, "allOf")
+		for i, variant := range allOf {
+			v, ok := variant.(map[string]any)
+			if !ok {
+				continue
+			}
+			n.mergeVariant(root, v, fmt.Sprintf("allOf[%d]", i))
+			root["required"] = unionStrings(stringList(root["required"]), stringList(v["required"]))
+		}
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		variants, ok := root[keyword].([]any)
+		if !ok {
+			continue
+		}
+		delete(root, keyword)
+
+		// A field is only required when every alternative requires it
+		var required []string
+		for i, variant := range variants {
+			v, ok := variant.(map[string]any)
+			if !ok {
+				continue
+			}
+			n.mergeVariant(root, v, fmt.Sprintf("%s[%d]", keyword, i))
+			if i == 0 {
+				required = stringList(v["required"])
+			} else {
+				required = intersectStrings(required, stringList(v["required"]))
+			}
+		}
+		root["required"] = unionStrings(stringList(root["required"]), required)
+		n.warn("top-level %s with %d alternatives was flattened into a single object, the model sees the union of their properties", keyword, len(variants))
+	}
+
+	if required := stringList(root["required"]); len(required) == 0 {
+		delete(root, "required")
+	}
+
+	switch t := root["type"].(type) {
+	case nil:
+		root["type"] = "object"
+	case string:
+		if t != "object" {
+			n.warn("top-level type '%s' is not an object, replaced with object", t)
+			root["type"] = "object"
+		}
+	default:
+		n.warn("top-level type %v is not an object, replaced with object", t)
+		root["type"] = "object"
+	}
+	if _, ok := root["properties"]; !ok {
+		root["properties"] = map[string]any{}
+	}
+}
+
+// mergeVariant copies the properties of one top-level alternative into the root. Keywords
+// other than type, properties and required can't be kept once the alternatives are merged.
+func (n *schemaNormalizer) mergeVariant(root, variant map[string]any, name string) {
+	props, _ := root["properties"].(map[string]any)
+	if props == nil {
+		props = map[string]any{}
+	}
+
+	if variantProps, ok := variant["properties"].(map[string]any); ok {
+		for prop, schema := range variantProps {
+			existing, ok := props[prop]
+			switch {
+			case !ok:
+				props[prop] = schema
+			case !reflect.DeepEqual(existing, schema):
+				// Accept either definition of a property the alternatives disagree on
+				props[prop] = map[string]any{"anyOf": []any{existing, schema}}
+			}
+		}
+	}
+	root["properties"] = props
+
+	var dropped []string
+	for key := range variant {
+		switch key {
+		case "type", "properties", "required", "title", "description":
+		default:
+			dropped = append(dropped, key)
+		}
+	}
+	if len(dropped) > 0 {
+		sort.Strings(dropped)
+		n.warn("dropped %s from top-level %s", strings.Join(dropped, ", "), name)
+	}
+}
+
+// sortedKeys returns the keys of m in order, so warnings come out in a stable order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stringList reads a JSON array of strings such as required
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func unionStrings(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var out []string
+	for _, s := range append(append([]string(nil), a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func intersectStrings(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if in[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,146 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeToolSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected string
+		warnings []string
+	}{
+		{
+			name:     "nil schema becomes an empty object",
+			schema:   `null`,
+			expected: `{"type":"object","properties":{}}`,
+		},
+		{
+			name:     "constraints are preserved",
+			schema:   `{"type":"object","properties":{"unit":{"type":"string","enum":["c","f"]}},"required":["unit"],"additionalProperties":false}`,
+			expected: `{"type":"object","properties":{"unit":{"type":"string","enum":["c","f"]}},"required":["unit"],"additionalProperties":false}`,
+		},
+		{
+			name: "local refs are inlined and defs removed",
+			schema: `{"type":"object","$defs":{"Point":{"type":"object","properties":{"x":{"type":"number"}}}},
+				"properties":{"from":{"$ref":"#/$defs/Point"},"to":{"$ref":"#/definitions/Point","description":"end"}},
+				"definitions":{"Point":{"type":"object","properties":{"y":{"type":"number"}}}}}`,
+			expected: `{"type":"object","properties":{
+				"from":{"type":"object","properties":{"x":{"type":"number"}}},
+				"to":{"type":"object","properties":{"y":{"type":"number"}},"description":"end"}}}`,
+		},
+		{
+			name:     "recursive refs are replaced with an empty schema",
+			schema:   `{"type":"object","properties":{"node":{"$ref":"#/$defs/Node"}},"$defs":{"Node":{"type":"object","properties":{"child":{"$ref":"#/$defs/Node"}}}}}`,
+			expected: `{"type":"object","properties":{"node":{"type":"object","properties":{"child":{}}}}}`,
+			warnings: []string{"recursive $ref '#/$defs/Node' at #/properties/node/properties/child is not supported, replaced with an empty schema"},
+		},
+		{
+			name:     "unresolvable refs are replaced with an empty schema",
+			schema:   `{"type":"object","properties":{"a":{"$ref":"#/$defs/Missing"},"b":{"$ref":"https://example.com/schema.json"}}}`,
+			expected: `{"type":"object","properties":{"a":{},"b":{}}}`,
+			warnings: []string{
+				"$ref '#/$defs/Missing' at #/properties/a does not resolve, replaced with an empty schema",
+				"external $ref 'https://example.com/schema.json' at #/properties/b is not supported, replaced with an empty schema",
+			},
+		},
+		{
+			name:     "enum values are not treated as schemas",
+			schema:   `{"type":"object","properties":{"v":{"const":{"$ref":"#/nowhere"}}}}`,
+			expected: `{"type":"object","properties":{"v":{"const":{"$ref":"#/nowhere"}}}}`,
+		},
+		{
+			name:     "top-level allOf is merged",
+			schema:   `{"allOf":[{"type":"object","properties":{"a":{"type":"string"}},"required":["a"]},{"properties":{"b":{"type":"integer"}},"required":["b"]}]}`,
+			expected: `{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"integer"}},"required":["a","b"]}`,
+		},
+		{
+			name: "top-level oneOf is flattened",
+			schema: `{"type":"object","properties":{"mode":{"type":"string"}},"required":["mode"],"oneOf":[
+				{"properties":{"id":{"type":"string"},"q":{"type":"string"}},"required":["id"]},
+				{"properties":{"q":{"type":"integer"}},"required":["q"],"additionalProperties":false}]}`,
+			expected: `{"type":"object","properties":{"mode":{"type":"string"},"id":{"type":"string"},
+				"q":{"anyOf":[{"type":"string"},{"type":"integer"}]}},"required":["mode"]}`,
+			warnings: []string{
+				"dropped additionalProperties from top-level oneOf[1]",
+				"top-level oneOf with 2 alternatives was flattened into a single object, the model sees the union of their properties",
+			},
+		},
+		{
+			name:     "non-object root type is replaced",
+			schema:   `{"type":"string"}`,
+			expected: `{"type":"object","properties":{}}`,
+			warnings: []string{"top-level type 'string' is not an object, replaced with object"},
+		},
+		{
+			name:     "nested unions are kept",
+			schema:   `{"type":"object","properties":{"id":{"anyOf":[{"type":"string"},{"type":"integer"}]}}}`,
+			expected: `{"type":"object","properties":{"id":{"anyOf":[{"type":"string"},{"type":"integer"}]}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			var schema map[string]any
+			assert.NoError(json.Unmarshal([]byte(tt.schema), &schema))
+			original, err := json.Marshal(schema)
+			assert.NoError(err)
+
+			normalized, warnings := NormalizeToolSchema(schema)
+
+			actual, err := json.Marshal(normalized)
+			assert.NoError(err)
+			assert.JSONEq(tt.expected, string(actual))
+			assert.Equal(tt.warnings, warnings)
+
+			// The input schema is left untouched
+			after, err := json.Marshal(schema)
+			assert.NoError(err)
+			assert.JSONEq(string(original), string(after))
+		})
+	}
+}
+
+func TestEvalClient_RunEval_AnthropicToolSchema(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("5 plus 3 is 8"),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{Name: "add", Prompt: "What is 5 plus 3?"})
+	assert.NoError(err)
+	assert.Empty(result.Trace.SchemaWarnings)
+
+	var addTool map[string]any
+	for _, tool := range api.Requests()[0]["tools"].([]any) {
+		if tool.(map[string]any)["name"] == "add" {
+			addTool = tool.(map[string]any)
+		}
+	}
+	assert.NotNil(addTool, "add tool should be offered to the model")
+
+	// Required fields and other constraints are sent, not just the properties
+	inputSchema := addTool["input_schema"].(map[string]any)
+	assert.Equal("object", inputSchema["type"])
+	assert.Contains(inputSchema["properties"], "a")
+	assert.ElementsMatch([]any{"a", "b"}, inputSchema["required"])
+	assert.Equal(false, inputSchema["additionalProperties"])
+}
@@ -0,0 +1,282 @@
+package evaluations
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// ToolMatchMode controls how expected tool calls are compared with the calls made
+type ToolMatchMode string
+
+const (
+	ToolMatchExact       ToolMatchMode = "exact"       // Calls must be exactly the expected calls, in order
+	ToolMatchSubset      ToolMatchMode = "subset"      // Every expected call must be made, in any order
+	ToolMatchSubsequence ToolMatchMode = "subsequence" // Every expected call must be made, in order, other calls may be interleaved
+)
+
+var toolMatchModes = []ToolMatchMode{ToolMatchExact, ToolMatchSubset, ToolMatchSubsequence}
+
+// ExpectedTools describes the tool-call trajectory an eval is expected to follow
+type ExpectedTools struct {
+	Match ToolMatchMode      `yaml:"match,omitempty" json:"match,omitempty" jsonschema:"How calls are matched: exact, subset or subsequence (default: subset)"`
+	Calls []ExpectedToolCall `yaml:"calls" json:"calls" jsonschema:"Expected tool calls"`
+}
+
+// ExpectedToolCall matches a single tool call by name and, optionally, its arguments
+type ExpectedToolCall struct {
+	Tool       string         `yaml:"tool" json:"tool" jsonschema:"Name of the MCP tool"`
+	Args       map[string]any `yaml:"args,omitempty" json:"args,omitempty" jsonschema:"Arguments the call must contain; nested objects match partially"`
+	ArgsSchema map[string]any `yaml:"args_schema,omitempty" json:"args_schema,omitempty" jsonschema:"JSON Schema the call arguments must satisfy"`
+}
+
+// ToolTrajectoryResult records how closely the tool calls made matched the expected tools
+type ToolTrajectoryResult struct {
+	Match      ToolMatchMode `json:"match"`                // Match mode used
+	Passed     bool          `json:"passed"`               // Whether the calls satisfied the match mode
+	Expected   int           `json:"expected"`             // Number of expected calls
+	Actual     int           `json:"actual"`               // Number of calls made
+	Matched    int           `json:"matched"`              // Number of expected calls matched by a call made
+	Precision  float64       `json:"precision"`            // Fraction of calls made that matched an expected call
+	Recall     float64       `json:"recall"`               // Fraction of expected calls that were made
+	Missing    []string      `json:"missing,omitempty"`    // Expected calls that were not matched
+	Unexpected []string      `json:"unexpected,omitempty"` // Calls made that did not match an expected call
+}
+
+// Mode returns the match mode, defaulting to subset
+func (e *ExpectedTools) Mode() ToolMatchMode {
+	if e.Match == "" {
+		return ToolMatchSubset
+	}
+	return e.Match
+}
+
+// Validate checks the match mode and that each expected call is well formed
+func (e *ExpectedTools) Validate() error {
+	if e == nil {
+		return nil
+	}
+
+	switch e.Mode() {
+	case ToolMatchExact:
+		// An empty exact trajectory asserts that no tools are called
+	case ToolMatchSubset, ToolMatchSubsequence:
+		if len(e.Calls) == 0 {
+			return fmt.Errorf("%s match requires at least one call", e.Mode())
+		}
+	default:
+		return fmt.Errorf("invalid match '%s': must be one of: %s", e.Match, joinEnum(toolMatchModes))
+	}
+
+	for i, call := range e.Calls {
+		if call.Tool == "" {
+			return fmt.Errorf("call[%d] requires tool", i)
+		}
+		if _, err := call.resolveSchema(); err != nil {
+			return fmt.Errorf("call[%d] has invalid args_schema: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// String returns a short description of the expected call for reports
+func (c ExpectedToolCall) String() string {
+	if len(c.Args) == 0 {
+		return c.Tool
+	}
+	args, _ := json.Marshal(c.Args)
+	return fmt.Sprintf("%s %s", c.Tool, args)
+}
+
+// Matches reports whether the tool call has the expected name and arguments
+func (c ExpectedToolCall) Matches(call ToolCall) bool {
+	if call.ToolName != c.Tool {
+		return false
+	}
+	if len(c.Args) == 0 && len(c.ArgsSchema) == 0 {
+		return true
+	}
+
+	var input any
+	if err := json.Unmarshal(call.Input, &input); err != nil {
+		return false
+	}
+
+	if len(c.Args) > 0 && !jsonContains(input, normalizeJSON(c.Args)) {
+		return false
+	}
+
+	if len(c.ArgsSchema) > 0 {
+		resolved, err := c.resolveSchema()
+		if err != nil || resolved.Validate(input) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveSchema converts args_schema, which may have been decoded from YAML, into a resolved JSON Schema
+func (c ExpectedToolCall) resolveSchema() (*jsonschema.Resolved, error) {
+	if len(c.ArgsSchema) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(c.ArgsSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return schema.Resolve(nil)
+}
+
+// MatchToolCalls compares the tool calls recorded in the trace with the expected tools
+func MatchToolCalls(expected *ExpectedTools, trace *EvalTrace) *ToolTrajectoryResult {
+	if expected == nil {
+		return nil
+	}
+
+	actual := toolCallsNamed(trace, "")
+	mode := expected.Mode()
+
+	// pairs[i] is the index of the actual call matched to expected call i, or -1
+	var pairs []int
+	if mode == ToolMatchSubset {
+		pairs = matchUnordered(expected.Calls, actual)
+	} else {
+		pairs = matchOrdered(expected.Calls, actual)
+	}
+
+	result := &ToolTrajectoryResult{
+		Match:     mode,
+		Expected:  len(expected.Calls),
+		Actual:    len(actual),
+		Precision: 1,
+		Recall:    1,
+	}
+
+	usedActual := make([]bool, len(actual))
+	for i, j := range pairs {
+		if j < 0 {
+			result.Missing = append(result.Missing, expected.Calls[i].String())
+			continue
+		}
+		result.Matched++
+		usedActual[j] = true
+	}
+	for j, used := range usedActual {
+		if !used {
+			result.Unexpected = append(result.Unexpected, actual[j].ToolName)
+		}
+	}
+
+	if result.Actual > 0 {
+		result.Precision = float64(result.Matched) / float64(result.Actual)
+	}
+	if result.Expected > 0 {
+		result.Recall = float64(result.Matched) / float64(result.Expected)
+	}
+
+	result.Passed = result.Matched == result.Expected
+	if mode == ToolMatchExact {
+		result.Passed = result.Passed && result.Actual == result.Expected
+	}
+
+	return result
+}
+
+// Err returns an error describing why the trajectory did not match, or nil if it passed
+func (r *ToolTrajectoryResult) Err() error {
+	if r == nil || r.Passed {
+		return nil
+	}
+
+	var details []string
+	if len(r.Missing) > 0 {
+		details = append(details, "missing: "+strings.Join(r.Missing, ", "))
+	}
+	if r.Match == ToolMatchExact && len(r.Unexpected) > 0 {
+		details = append(details, "unexpected: "+strings.Join(r.Unexpected, ", "))
+	}
+
+	return fmt.Errorf("tool calls did not match expected tools (%s, %d/%d matched, %d made): %s",
+		r.Match, r.Matched, r.Expected, r.Actual, strings.Join(details, "; "))
+}
+
+// matchOrdered pairs expected calls with actual calls preserving order, using the
+// longest common subsequence so the most expected calls are matched
+func matchOrdered(expected []ExpectedToolCall, actual []ToolCall) []int {
+	n, m := len(expected), len(actual)
+
+	// lengths[i][j] is the LCS length of expected[i:] and actual[j:]
+	lengths := make([][]int, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if expected[i].Matches(actual[j]) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	pairs := make([]int, n)
+	for i := range pairs {
+		pairs[i] = -1
+	}
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case expected[i].Matches(actual[j]) && lengths[i][j] == lengths[i+1][j+1]+1:
+			pairs[i] = j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// matchUnordered pairs expected calls with distinct actual calls in any order,
+// using augmenting paths so the most expected calls are matched
+func matchUnordered(expected []ExpectedToolCall, actual []ToolCall) []int {
+	pairs := make([]int, len(expected))
+	owner := make([]int, len(actual)) // owner[j] is the expected call matched to actual call j, or -1
+	for i := range pairs {
+		pairs[i] = -1
+	}
+	for j := range owner {
+		owner[j] = -1
+	}
+
+	var augment func(i int, visited []bool) bool
+	augment = func(i int, visited []bool) bool {
+		for j := range actual {
+			if visited[j] || !expected[i].Matches(actual[j]) {
+				continue
+			}
+			visited[j] = true
+			if owner[j] < 0 || augment(owner[j], visited) {
+				owner[j] = i
+				pairs[i] = j
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range expected {
+		augment(i, make([]bool, len(actual)))
+	}
+	return pairs
+}
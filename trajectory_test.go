@@ -0,0 +1,322 @@
+package evaluations
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// traceWithCalls builds a trace with one step per tool call
+func traceWithCalls(calls ...ToolCall) *EvalTrace {
+	trace := &EvalTrace{}
+	for _, call := range calls {
+		trace.Steps = append(trace.Steps, AgenticStep{ToolCalls: []ToolCall{call}})
+	}
+	return trace
+}
+
+func TestMatchToolCalls(t *testing.T) {
+	getUser := ToolCall{ToolName: "get_user_info", Input: json.RawMessage(`{"user_id":"123"}`)}
+	listTeams := ToolCall{ToolName: "list_teams", Input: json.RawMessage(`{"user_id":"123","limit":5}`)}
+	echo := ToolCall{ToolName: "echo", Input: json.RawMessage(`{"message":"hi"}`)}
+
+	expectedCalls := []ExpectedToolCall{
+		{Tool: "get_user_info", Args: map[string]any{"user_id": "123"}},
+		{Tool: "list_teams"},
+	}
+
+	tests := []struct {
+		name       string
+		expected   *ExpectedTools
+		trace      *EvalTrace
+		passed     bool
+		matched    int
+		precision  float64
+		recall     float64
+		missing    []string
+		unexpected []string
+	}{
+		{
+			name:      "exact match",
+			expected:  &ExpectedTools{Match: ToolMatchExact, Calls: expectedCalls},
+			trace:     traceWithCalls(getUser, listTeams),
+			passed:    true,
+			matched:   2,
+			precision: 1,
+			recall:    1,
+		},
+		{
+			name:       "exact with extra call",
+			expected:   &ExpectedTools{Match: ToolMatchExact, Calls: expectedCalls},
+			trace:      traceWithCalls(getUser, echo, listTeams),
+			passed:     false,
+			matched:    2,
+			precision:  2.0 / 3.0,
+			recall:     1,
+			unexpected: []string{"echo"},
+		},
+		{
+			name:       "exact out of order",
+			expected:   &ExpectedTools{Match: ToolMatchExact, Calls: expectedCalls},
+			trace:      traceWithCalls(listTeams, getUser),
+			passed:     false,
+			matched:    1,
+			precision:  0.5,
+			recall:     0.5,
+			missing:    []string{`get_user_info {"user_id":"123"}`},
+			unexpected: []string{"get_user_info"},
+		},
+		{
+			name:       "subsequence allows interleaved calls",
+			expected:   &ExpectedTools{Match: ToolMatchSubsequence, Calls: expectedCalls},
+			trace:      traceWithCalls(echo, getUser, echo, listTeams),
+			passed:     true,
+			matched:    2,
+			precision:  0.5,
+			recall:     1,
+			unexpected: []string{"echo", "echo"},
+		},
+		{
+			name:       "subsequence out of order",
+			expected:   &ExpectedTools{Match: ToolMatchSubsequence, Calls: expectedCalls},
+			trace:      traceWithCalls(listTeams, getUser),
+			passed:     false,
+			matched:    1,
+			precision:  0.5,
+			recall:     0.5,
+			missing:    []string{`get_user_info {"user_id":"123"}`},
+			unexpected: []string{"get_user_info"},
+		},
+		{
+			name:      "subset in any order",
+			expected:  &ExpectedTools{Calls: expectedCalls},
+			trace:     traceWithCalls(listTeams, getUser),
+			passed:    true,
+			matched:   2,
+			precision: 1,
+			recall:    1,
+		},
+		{
+			name:      "subset with wrong args",
+			expected:  &ExpectedTools{Match: ToolMatchSubset, Calls: expectedCalls},
+			trace:     traceWithCalls(ToolCall{ToolName: "get_user_info", Input: json.RawMessage(`{"user_id":"999"}`)}, listTeams),
+			passed:    false,
+			matched:   1,
+			precision: 0.5,
+			recall:    0.5,
+			missing:   []string{`get_user_info {"user_id":"123"}`},
+			// The unmatched call keeps its name so reports show what was called instead
+			unexpected: []string{"get_user_info"},
+		},
+		{
+			name: "subset matches distinct calls",
+			expected: &ExpectedTools{Calls: []ExpectedToolCall{
+				{Tool: "list_teams"},
+				{Tool: "list_teams", Args: map[string]any{"limit": 5}},
+			}},
+			// A greedy match would pair the first expectation with the only call that satisfies the second
+			trace:     traceWithCalls(listTeams, ToolCall{ToolName: "list_teams", Input: json.RawMessage(`{}`)}),
+			passed:    true,
+			matched:   2,
+			precision: 1,
+			recall:    1,
+		},
+		{
+			name:      "no calls made",
+			expected:  &ExpectedTools{Calls: expectedCalls},
+			trace:     &EvalTrace{},
+			passed:    false,
+			matched:   0,
+			precision: 1,
+			recall:    0,
+			missing:   []string{`get_user_info {"user_id":"123"}`, "list_teams"},
+		},
+		{
+			name:      "exact empty expects no calls",
+			expected:  &ExpectedTools{Match: ToolMatchExact},
+			trace:     &EvalTrace{},
+			passed:    true,
+			precision: 1,
+			recall:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			result := MatchToolCalls(tt.expected, tt.trace)
+			assert.Equal(tt.passed, result.Passed)
+			assert.Equal(tt.matched, result.Matched)
+			assert.InDelta(tt.precision, result.Precision, 0.001)
+			assert.InDelta(tt.recall, result.Recall, 0.001)
+			assert.Equal(tt.missing, result.Missing)
+			assert.Equal(tt.unexpected, result.Unexpected)
+
+			if tt.passed {
+				assert.NoError(result.Err())
+			} else {
+				assert.Error(result.Err())
+			}
+		})
+	}
+
+	require.Nil(t, MatchToolCalls(nil, &EvalTrace{}))
+}
+
+func TestExpectedToolCall_MatchesSchema(t *testing.T) {
+	call := ExpectedToolCall{
+		Tool: "add",
+		ArgsSchema: map[string]any{
+			"type":     "object",
+			"required": []any{"a", "b"},
+			"properties": map[string]any{
+				"a": map[string]any{"type": "integer", "minimum": 0},
+				"b": map[string]any{"type": "integer"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"valid args", `{"a":5,"b":3}`, true},
+		{"missing required", `{"a":5}`, false},
+		{"wrong type", `{"a":"five","b":3}`, false},
+		{"below minimum", `{"a":-1,"b":3}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+			assert.Equal(tt.want, call.Matches(ToolCall{ToolName: "add", Input: json.RawMessage(tt.input)}))
+		})
+	}
+
+	require.False(t, call.Matches(ToolCall{ToolName: "echo", Input: json.RawMessage(`{"a":5,"b":3}`)}))
+}
+
+func TestExpectedTools_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected *ExpectedTools
+		wantErr  string
+	}{
+		{"nil", nil, ""},
+		{"valid subset", &ExpectedTools{Calls: []ExpectedToolCall{{Tool: "add"}}}, ""},
+		{"empty exact", &ExpectedTools{Match: ToolMatchExact}, ""},
+		{"empty subsequence", &ExpectedTools{Match: ToolMatchSubsequence}, "requires at least one call"},
+		{"invalid match", &ExpectedTools{Match: "fuzzy", Calls: []ExpectedToolCall{{Tool: "add"}}}, "invalid match"},
+		{"missing tool", &ExpectedTools{Calls: []ExpectedToolCall{{Args: map[string]any{"a": 1}}}}, "call[0] requires tool"},
+		{"invalid schema", &ExpectedTools{Calls: []ExpectedToolCall{{Tool: "add", ArgsSchema: map[string]any{"type": 5}}}}, "invalid args_schema"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			err := tt.expected.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(err)
+				return
+			}
+			assert.Error(err)
+			assert.Contains(err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_ExpectedTools(t *testing.T) {
+	assert := require.New(t)
+
+	configContent := `
+model: claude-3-5-sonnet-20241022
+mcp_server:
+  command: echo
+evals:
+  - name: test
+    prompt: "test"
+    expected_tools:
+      match: subsequence
+      calls:
+        - tool: add
+          args: {a: 5}
+        - tool: echo
+          args_schema:
+            type: object
+            required: [message]
+`
+
+	tmpFile, err := os.CreateTemp("", "config-expected-tools-*.yaml")
+	assert.NoError(err)
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.WriteString(configContent)
+	assert.NoError(err)
+	tmpFile.Close()
+
+	config, err := LoadConfig(tmpFile.Name())
+	assert.NoError(err)
+
+	expected := config.Evals[0].ExpectedTools
+	assert.NotNil(expected)
+	assert.Equal(ToolMatchSubsequence, expected.Mode())
+	assert.Len(expected.Calls, 2)
+	assert.True(expected.Calls[0].Matches(ToolCall{ToolName: "add", Input: json.RawMessage(`{"a":5,"b":3}`)}))
+	assert.True(expected.Calls[1].Matches(ToolCall{ToolName: "echo", Input: json.RawMessage(`{"message":"hi"}`)}))
+	assert.False(expected.Calls[1].Matches(ToolCall{ToolName: "echo", Input: json.RawMessage(`{}`)}))
+}
+
+func TestEvalClient_RunEval_ExpectedTools(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamToolUseResponse("toolu_1", "add", `{"a":5,"b":3}`),
+		streamTextResponse("5 plus 3 is 8"),
+		gradeResponse(5),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEval(context.Background(), Eval{
+		Name:   "add",
+		Prompt: "What is 5 plus 3?",
+		ExpectedTools: &ExpectedTools{
+			Match: ToolMatchExact,
+			Calls: []ExpectedToolCall{
+				{Tool: "add", Args: map[string]any{"a": 5, "b": 3}},
+				{Tool: "echo"},
+			},
+		},
+	})
+	assert.NoError(err)
+	assert.NotNil(result.Grade)
+
+	trajectory := result.Trace.ToolTrajectory
+	assert.NotNil(trajectory)
+	assert.False(trajectory.Passed)
+	assert.Equal(1, trajectory.Matched)
+	assert.InDelta(1.0, trajectory.Precision, 0.001)
+	assert.InDelta(0.5, trajectory.Recall, 0.001)
+	assert.Equal([]string{"echo"}, trajectory.Missing)
+
+	assert.Error(result.Error)
+	assert.Contains(result.Error.Error(), "did not match expected tools")
+
+	// The trajectory is persisted with the trace JSON
+	data, err := json.Marshal(result.Trace)
+	assert.NoError(err)
+	assert.Contains(string(data), `"tool_trajectory":{"match":"exact","passed":false`)
+}
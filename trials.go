@@ -0,0 +1,281 @@
+package evaluations
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// PassingScore is the minimum average grade for an eval to pass
+const PassingScore = 3.0
+
+// TrialPassRule decides whether an eval that runs several trials passes overall
+type TrialPassRule string
+
+const (
+	TrialPassAll  TrialPassRule = "all"  // Every trial must pass, pass^k (default)
+	TrialPassAny  TrialPassRule = "any"  // At least one trial must pass, pass@k
+	TrialPassRate TrialPassRule = "rate" // The fraction of passing trials must reach MinPassRate
+	TrialPassMean TrialPassRule = "mean" // The mean average score of the graded trials must reach MinScore
+)
+
+var trialPassRules = []TrialPassRule{TrialPassAll, TrialPassAny, TrialPassRate, TrialPassMean}
+
+// TrialPolicy configures how the trials of an eval are combined into a single pass or fail
+type TrialPolicy struct {
+	Rule        TrialPassRule `yaml:"rule,omitempty" json:"rule,omitempty" jsonschema:"How trials decide pass/fail: all (every trial passes), any (at least one passes), rate (pass rate reaches min_pass_rate) or mean (mean score reaches min_score). Default: all"`
+	MinPassRate float64       `yaml:"min_pass_rate,omitempty" json:"min_pass_rate,omitempty" jsonschema:"Minimum fraction of passing trials for the rate rule, between 0 and 1 (default 0.8)"`
+	MinScore    float64       `yaml:"min_score,omitempty" json:"min_score,omitempty" jsonschema:"Minimum mean average score for the mean rule, between 1 and 5 (default 3)"`
+}
+
+// Validate checks the rule and its thresholds
+func (p *TrialPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	switch p.Rule {
+	case "", TrialPassAll, TrialPassAny, TrialPassRate, TrialPassMean:
+	default:
+		return fmt.Errorf("invalid trial_pass.rule '%s': must be one of: %s", p.Rule, joinEnum(trialPassRules))
+	}
+	if p.MinPassRate < 0 || p.MinPassRate > 1 {
+		return fmt.Errorf("trial_pass.min_pass_rate must be between 0 and 1, got %g", p.MinPassRate)
+	}
+	if p.MinScore != 0 && (p.MinScore < 1 || p.MinScore > 5) {
+		return fmt.Errorf("trial_pass.min_score must be between 1 and 5, got %g", p.MinScore)
+	}
+	return nil
+}
+
+// withDefaults returns a copy of the policy with unset fields defaulted
+func (p TrialPolicy) withDefaults() TrialPolicy {
+	if p.Rule == "" {
+		p.Rule = TrialPassAll
+	}
+	if p.MinPassRate == 0 {
+		p.MinPassRate = 0.8
+	}
+	if p.MinScore == 0 {
+		p.MinScore = PassingScore
+	}
+	return p
+}
+
+// ScoreStats summarizes the distribution of a score across trials
+type ScoreStats struct {
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"` // Population standard deviation
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// TrialScores holds the score distribution of each grading dimension and of the average grade
+type TrialScores struct {
+	Accuracy     ScoreStats `json:"accuracy"`
+	Completeness ScoreStats `json:"completeness"`
+	Relevance    ScoreStats `json:"relevance"`
+	Clarity      ScoreStats `json:"clarity"`
+	Reasoning    ScoreStats `json:"reasoning"`
+	Average      ScoreStats `json:"average"`
+}
+
+// TrialAggregate combines the results of running an eval several times
+type TrialAggregate struct {
+	Policy   TrialPolicy  `json:"policy"`           // Rule and thresholds used to decide Passed
+	Passed   bool         `json:"passed"`           // Whether the trials satisfied the policy
+	Trials   int          `json:"trials"`           // Number of trials run
+	Passes   int          `json:"passes"`           // Number of trials that passed on their own
+	Graded   int          `json:"graded"`           // Number of trials with a grade, the sample size of Scores
+	PassRate float64      `json:"pass_rate"`        // Passes / Trials, equal to pass@1
+	PassAtK  []float64    `json:"pass_at_k"`        // PassAtK[k-1] estimates the chance at least one of k trials passes
+	PassHatK []float64    `json:"pass_hat_k"`       // PassHatK[k-1] estimates the chance all of k trials pass
+	Scores   *TrialScores `json:"scores,omitempty"` // Score distribution of the graded trials
+}
+
+// Err returns an error describing why the trials failed the policy, or nil if they passed
+func (a *TrialAggregate) Err() error {
+	if a == nil || a.Passed {
+		return nil
+	}
+
+	switch a.Policy.Rule {
+	case TrialPassAny:
+		return fmt.Errorf("no trials passed (%d trials)", a.Trials)
+	case TrialPassRate:
+		return fmt.Errorf("%d of %d trials passed, pass rate %.2f is below %.2f", a.Passes, a.Trials, a.PassRate, a.Policy.MinPassRate)
+	case TrialPassMean:
+		if a.Scores == nil {
+			return fmt.Errorf("no trials were graded (%d trials)", a.Trials)
+		}
+		return fmt.Errorf("mean score %.2f across %d graded trials is below %.2f", a.Scores.Average.Mean, a.Graded, a.Policy.MinScore)
+	default:
+		return fmt.Errorf("%d of %d trials passed, all trials must pass", a.Passes, a.Trials)
+	}
+}
+
+// AggregateTrials summarizes the trials of a single eval and decides whether they pass the policy.
+// A trial passes when it finished without error and its average grade, if any, reaches PassingScore.
+func AggregateTrials(trials []EvalRunResult, policy TrialPolicy) *TrialAggregate {
+	policy = policy.withDefaults()
+
+	agg := &TrialAggregate{
+		Policy: policy,
+		Trials: len(trials),
+	}
+
+	var grades []*GradeResult
+	for _, trial := range trials {
+		if trialPassed(trial) {
+			agg.Passes++
+		}
+		if trial.Grade != nil {
+			grades = append(grades, trial.Grade)
+		}
+	}
+	agg.Graded = len(grades)
+
+	if agg.Trials > 0 {
+		agg.PassRate = float64(agg.Passes) / float64(agg.Trials)
+	}
+	agg.PassAtK = make([]float64, agg.Trials)
+	agg.PassHatK = make([]float64, agg.Trials)
+	for k := 1; k <= agg.Trials; k++ {
+		agg.PassAtK[k-1] = passAtK(agg.Trials, agg.Passes, k)
+		agg.PassHatK[k-1] = passHatK(agg.Trials, agg.Passes, k)
+	}
+
+	if len(grades) > 0 {
+		agg.Scores = &TrialScores{
+			Accuracy:     scoreStats(grades, func(g *GradeResult) float64 { return float64(g.Accuracy) }),
+			Completeness: scoreStats(grades, func(g *GradeResult) float64 { return float64(g.Completeness) }),
+			Relevance:    scoreStats(grades, func(g *GradeResult) float64 { return float64(g.Relevance) }),
+			Clarity:      scoreStats(grades, func(g *GradeResult) float64 { return float64(g.Clarity) }),
+			Reasoning:    scoreStats(grades, func(g *GradeResult) float64 { return float64(g.Reasoning) }),
+			Average:      scoreStats(grades, (*GradeResult).Average),
+		}
+	}
+
+	switch policy.Rule {
+	case TrialPassAny:
+		agg.Passed = agg.Passes > 0
+	case TrialPassRate:
+		agg.Passed = agg.Trials > 0 && agg.PassRate >= policy.MinPassRate
+	case TrialPassMean:
+		agg.Passed = agg.Scores != nil && agg.Scores.Average.Mean >= policy.MinScore
+	default:
+		agg.Passed = agg.Trials > 0 && agg.Passes == agg.Trials
+	}
+
+	return agg
+}
+
+// RunEvalTrials runs an eval as many times as its trials setting asks for and aggregates
+// the results. With a single trial it is equivalent to RunEval. With more, the returned
+// result holds every run in Trials, the summary in Aggregate, and an Error when the
+// aggregate fails the trial policy; its other fields are left unset. A trial that
+// fails with an error is recorded as a failed run and the remaining trials still run.
+// Once the suite budget is used up the remaining trials are recorded as skipped with
+// EvalStatusBudgetExceeded, so they count as failures in the aggregate.
+func (ec *EvalClient) RunEvalTrials(ctx context.Context, eval Eval) (*EvalRunResult, error) {
+	n := ec.evalTrials(eval)
+	if n <= 1 {
+		return ec.RunEval(ctx, eval)
+	}
+
+	trials := make([]EvalRunResult, 0, n)
+	for range n {
+		result, err := ec.RunEval(ctx, eval)
+		if err != nil {
+			result = &EvalRunResult{Eval: eval, Error: err}
+		}
+		trials = append(trials, *result)
+
+		// Stop early once the suite deadline has passed, later trials could only time out.
+		// An exhausted budget doesn't stop the loop, RunEval skips those trials itself.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	aggregate := AggregateTrials(trials, ec.trialPolicy(eval))
+	return &EvalRunResult{
+		Eval:      eval,
+		Error:     aggregate.Err(),
+		Trials:    trials,
+		Aggregate: aggregate,
+	}, nil
+}
+
+// evalTrials returns the number of trials for an eval, preferring the eval's own setting
+func (ec *EvalClient) evalTrials(eval Eval) int {
+	if eval.Trials > 0 {
+		return eval.Trials
+	}
+	return ec.config.Trials
+}
+
+// trialPolicy returns the trial policy for an eval, preferring the eval's own policy
+func (ec *EvalClient) trialPolicy(eval Eval) TrialPolicy {
+	if eval.TrialPass != nil {
+		return *eval.TrialPass
+	}
+	return ec.config.TrialPass
+}
+
+// trialPassed reports whether a single run passed: it completed without error
+// and, if graded, its average score reaches PassingScore
+func trialPassed(result EvalRunResult) bool {
+	if result.Status == EvalStatusTimeout || result.Error != nil {
+		return false
+	}
+	return result.Grade == nil || result.Grade.Average() >= PassingScore
+}
+
+// passAtK is the unbiased estimate of the probability that at least one of k trials,
+// drawn without replacement from n trials with c passes, passes: 1 - C(n-c, k) / C(n, k)
+func passAtK(n, c, k int) float64 {
+	if n-c < k {
+		return 1
+	}
+	p := 1.0
+	for i := n - c + 1; i <= n; i++ {
+		p *= 1 - float64(k)/float64(i)
+	}
+	return 1 - p
+}
+
+// passHatK is the unbiased estimate of the probability that all of k trials, drawn
+// without replacement from n trials with c passes, pass: C(c, k) / C(n, k)
+func passHatK(n, c, k int) float64 {
+	if c < k {
+		return 0
+	}
+	p := 1.0
+	for i := range k {
+		p *= float64(c-i) / float64(n-i)
+	}
+	return p
+}
+
+func scoreStats(grades []*GradeResult, score func(*GradeResult) float64) ScoreStats {
+	stats := ScoreStats{Min: math.Inf(1), Max: math.Inf(-1)}
+
+	sum := 0.0
+	for _, grade := range grades {
+		v := score(grade)
+		sum += v
+		stats.Min = math.Min(stats.Min, v)
+		stats.Max = math.Max(stats.Max, v)
+	}
+	stats.Mean = sum / float64(len(grades))
+
+	variance := 0.0
+	for _, grade := range grades {
+		d := score(grade) - stats.Mean
+		variance += d * d
+	}
+	stats.StdDev = math.Sqrt(variance / float64(len(grades)))
+
+	return stats
+}
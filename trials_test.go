@@ -0,0 +1,331 @@
+package evaluations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// gradedTrial builds a completed trial with the same score in every dimension
+func gradedTrial(score int) EvalRunResult {
+	return EvalRunResult{
+		Grade: &GradeResult{Accuracy: score, Completeness: score, Relevance: score, Clarity: score, Reasoning: score},
+		Trace: &EvalTrace{},
+	}
+}
+
+func TestAggregateTrials(t *testing.T) {
+	assert := require.New(t)
+
+	trials := []EvalRunResult{
+		gradedTrial(5),
+		gradedTrial(4),
+		gradedTrial(2),
+		{Eval: Eval{Name: "errored"}, Error: errors.New("grading failed")},
+	}
+
+	agg := AggregateTrials(trials, TrialPolicy{})
+	assert.Equal(TrialPassAll, agg.Policy.Rule)
+	assert.Equal(4, agg.Trials)
+	assert.Equal(2, agg.Passes)
+	assert.Equal(3, agg.Graded)
+	assert.Equal(0.5, agg.PassRate)
+	assert.False(agg.Passed)
+	assert.EqualError(agg.Err(), "2 of 4 trials passed, all trials must pass")
+
+	// pass@1 equals the pass rate, pass@k reaches 1 once k exceeds the failures
+	assert.Len(agg.PassAtK, 4)
+	assert.InDelta(0.5, agg.PassAtK[0], 1e-9)
+	assert.InDelta(5.0/6.0, agg.PassAtK[1], 1e-9)
+	assert.InDelta(1.0, agg.PassAtK[2], 1e-9)
+	assert.InDelta(1.0, agg.PassAtK[3], 1e-9)
+
+	// pass^k falls to 0 once k exceeds the passes
+	assert.Len(agg.PassHatK, 4)
+	assert.InDelta(0.5, agg.PassHatK[0], 1e-9)
+	assert.InDelta(1.0/6.0, agg.PassHatK[1], 1e-9)
+	assert.InDelta(0.0, agg.PassHatK[2], 1e-9)
+
+	// Scores only include graded trials
+	assert.NotNil(agg.Scores)
+	assert.InDelta(11.0/3.0, agg.Scores.Accuracy.Mean, 1e-9)
+	assert.InDelta(1.247219, agg.Scores.Accuracy.StdDev, 1e-6)
+	assert.Equal(2.0, agg.Scores.Accuracy.Min)
+	assert.Equal(5.0, agg.Scores.Accuracy.Max)
+	assert.Equal(agg.Scores.Accuracy, agg.Scores.Average)
+}
+
+func TestAggregateTrials_Rules(t *testing.T) {
+	trials := []EvalRunResult{gradedTrial(5), gradedTrial(4), gradedTrial(4), gradedTrial(1)}
+
+	tests := []struct {
+		name       string
+		policy     TrialPolicy
+		trials     []EvalRunResult
+		wantPassed bool
+		wantErr    string
+	}{
+		{"all fails with one failure", TrialPolicy{Rule: TrialPassAll}, trials, false, "3 of 4 trials passed, all trials must pass"},
+		{"all passes when every trial passes", TrialPolicy{Rule: TrialPassAll}, trials[:3], true, ""},
+		{"any passes with one pass", TrialPolicy{Rule: TrialPassAny}, trials, true, ""},
+		{"any fails without passes", TrialPolicy{Rule: TrialPassAny}, trials[3:], false, "no trials passed (1 trials)"},
+		{"rate passes at default threshold", TrialPolicy{Rule: TrialPassRate}, trials[:3], true, ""},
+		{"rate fails below default threshold", TrialPolicy{Rule: TrialPassRate}, trials, false, "3 of 4 trials passed, pass rate 0.75 is below 0.80"},
+		{"rate passes at custom threshold", TrialPolicy{Rule: TrialPassRate, MinPassRate: 0.7}, trials, true, ""},
+		{"mean passes at default score", TrialPolicy{Rule: TrialPassMean}, trials, true, ""},
+		{"mean fails at custom score", TrialPolicy{Rule: TrialPassMean, MinScore: 4}, trials, false, "mean score 3.50 across 4 graded trials is below 4.00"},
+		{"mean fails without grades", TrialPolicy{Rule: TrialPassMean}, []EvalRunResult{{Error: errors.New("boom")}}, false, "no trials were graded (1 trials)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			agg := AggregateTrials(tt.trials, tt.policy)
+			assert.Equal(tt.wantPassed, agg.Passed)
+			if tt.wantErr == "" {
+				assert.NoError(agg.Err())
+			} else {
+				assert.EqualError(agg.Err(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrialPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *TrialPolicy
+		wantErr string
+	}{
+		{"nil", nil, ""},
+		{"empty", &TrialPolicy{}, ""},
+		{"rate", &TrialPolicy{Rule: TrialPassRate, MinPassRate: 0.9}, ""},
+		{"mean", &TrialPolicy{Rule: TrialPassMean, MinScore: 3.5}, ""},
+		{"invalid rule", &TrialPolicy{Rule: "most"}, "invalid trial_pass.rule 'most': must be one of: all, any, rate, mean"},
+		{"pass rate above 1", &TrialPolicy{Rule: TrialPassRate, MinPassRate: 1.5}, "trial_pass.min_pass_rate must be between 0 and 1, got 1.5"},
+		{"min score above 5", &TrialPolicy{Rule: TrialPassMean, MinScore: 6}, "trial_pass.min_score must be between 1 and 5, got 6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			err := tt.policy.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(err)
+			} else {
+				assert.EqualError(err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvalClient_RunEvalTrials(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("8"), gradeResponse(5),
+		streamTextResponse("8"), gradeResponse(4),
+		streamTextResponse("9"), gradeResponse(1),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+		Trials:    1,
+	})
+
+	eval := Eval{
+		Name:      "add",
+		Prompt:    "What is 5 plus 3?",
+		Trials:    3,
+		TrialPass: &TrialPolicy{Rule: TrialPassRate, MinPassRate: 0.6},
+	}
+
+	results, err := client.RunEvals(context.Background(), []Eval{eval})
+	assert.NoError(err)
+	assert.Len(results, 1)
+
+	result := results[0]
+	assert.NoError(result.Error)
+	assert.Nil(result.Trace)
+	assert.Len(result.Trials, 3)
+	assert.Equal("9", result.Trials[2].Result.RawResponse)
+
+	agg := result.Aggregate
+	assert.NotNil(agg)
+	assert.True(agg.Passed)
+	assert.Equal(TrialPassRate, agg.Policy.Rule)
+	assert.Equal(3, agg.Trials)
+	assert.Equal(2, agg.Passes)
+	assert.InDelta(10.0/3.0, agg.Scores.Average.Mean, 1e-9)
+	assert.Equal(1.0, agg.Scores.Average.Min)
+	assert.Equal(5.0, agg.Scores.Average.Max)
+
+	assert.Len(api.Requests(), 6)
+}
+
+func TestEvalClient_RunEvalTrials_FailedTrial(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t,
+		streamTextResponse("8"), gradeResponse(5),
+		apiError(http.StatusBadRequest, "invalid_request_error", nil),
+		streamTextResponse("8"), gradeResponse(4),
+	)
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEvalTrials(context.Background(), Eval{
+		Name:      "add",
+		Prompt:    "What is 5 plus 3?",
+		Trials:    3,
+		TrialPass: &TrialPolicy{Rule: TrialPassRate, MinPassRate: 0.6},
+	})
+	assert.NoError(err)
+	assert.NoError(result.Error)
+
+	// The failed trial is kept and the trials after it still run
+	assert.Len(result.Trials, 3)
+	assert.NoError(result.Trials[0].Error)
+	assert.ErrorContains(result.Trials[1].Error, "invalid_request_error")
+	assert.Equal("add", result.Trials[1].Eval.Name)
+	assert.Equal(4, result.Trials[2].Grade.Accuracy)
+
+	agg := result.Aggregate
+	assert.Equal(3, agg.Trials)
+	assert.Equal(2, agg.Passes)
+	assert.True(agg.Passed)
+	assert.InDelta(1.0, agg.PassAtK[1], 1e-9)
+	assert.InDelta(1.0/3.0, agg.PassHatK[1], 1e-9)
+	assert.Len(api.Requests(), 5)
+}
+
+func TestEvalClient_RunEvalTrials_SingleTrial(t *testing.T) {
+	assert := require.New(t)
+
+	mcpServer := newTestMCPServer(t)
+	api := newFakeModelAPI(t, streamTextResponse("8"), gradeResponse(5))
+
+	client := NewEvalClient(EvalClientConfig{
+		APIKey:    "test-key",
+		BaseURL:   api.URL,
+		Transport: TransportStreamableHTTP,
+		URL:       mcpServer.URL,
+		Model:     "test-model",
+	})
+
+	result, err := client.RunEvalTrials(context.Background(), Eval{Name: "add", Prompt: "What is 5 plus 3?"})
+	assert.NoError(err)
+	assert.Nil(result.Aggregate)
+	assert.Nil(result.Trials)
+	assert.NotNil(result.Trace)
+	assert.Equal(5, result.Grade.Accuracy)
+}
+
+func TestLoadConfig_Trials(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			config: `
+trials: 5
+trial_pass:
+  rule: rate
+  min_pass_rate: 0.8
+evals:
+  - name: test
+    prompt: "test"
+    trials: 10
+    trial_pass:
+      rule: mean
+      min_score: 3.5
+`,
+		},
+		{
+			name: "negative trials",
+			config: `
+trials: -1
+evals:
+  - name: test
+    prompt: "test"
+`,
+			wantErr: "trials must be at least 1, got -1",
+		},
+		{
+			name: "invalid rule",
+			config: `
+trial_pass:
+  rule: most
+evals:
+  - name: test
+    prompt: "test"
+`,
+			wantErr: "invalid trial_pass.rule 'most'",
+		},
+		{
+			name: "invalid eval policy",
+			config: `
+evals:
+  - name: test
+    prompt: "test"
+    trial_pass:
+      rule: rate
+      min_pass_rate: 2
+`,
+			wantErr: "eval[0] 'test' has invalid trial_pass: trial_pass.min_pass_rate must be between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			configContent := fmt.Sprintf(`
+model: test-model
+mcp_server:
+  command: echo
+%s`, tt.config)
+
+			tmpFile, err := os.CreateTemp("", "config-trials-*.yaml")
+			assert.NoError(err)
+			defer os.Remove(tmpFile.Name())
+
+			_, err = tmpFile.WriteString(configContent)
+			assert.NoError(err)
+			tmpFile.Close()
+
+			config, err := LoadConfig(tmpFile.Name())
+			if tt.wantErr != "" {
+				assert.Error(err)
+				assert.Contains(err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(5, config.Trials)
+			assert.Equal(TrialPassRate, config.TrialPass.Rule)
+			assert.Equal(10, config.Evals[0].Trials)
+			assert.Equal(3.5, config.Evals[0].TrialPass.MinScore)
+		})
+	}
+}